@@ -2,8 +2,10 @@ package tviewyaml
 
 import (
 	"fmt"
-	"regexp"
+	"io/fs"
+	"path/filepath"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/cassdeckard/tviewyaml/builder"
@@ -17,13 +19,22 @@ import (
 type Application struct {
 	*tview.Application
 	stopRefresh chan struct{}
+	stopOnce    sync.Once
+	shutdown    []func() // WithShutdown callbacks and Build cleanup (restoring tview.Borders), run once by Stop
 }
 
-// Stop gracefully shuts down the application and stops all background goroutines
+// Stop gracefully shuts down the application and stops all background goroutines.
+// Shutdown callbacks registered with WithShutdown run first, in registration order, before tview stops.
+// It is safe to call more than once (e.g. from stopApp and a deferred Stop in main).
 func (a *Application) Stop() {
-	if a.stopRefresh != nil {
-		close(a.stopRefresh)
-	}
+	a.stopOnce.Do(func() {
+		for _, fn := range a.shutdown {
+			fn()
+		}
+		if a.stopRefresh != nil {
+			close(a.stopRefresh)
+		}
+	})
 	if a.Application != nil {
 		a.Application.Stop()
 	}
@@ -31,29 +42,128 @@ func (a *Application) Stop() {
 
 // AppBuilder provides a fluent API for building tview applications from YAML configuration
 type AppBuilder struct {
-	configDir string
-	registry  *template.FunctionRegistry
-	errors    []error
-	screen    tcell.Screen // optional; if set, used for testing (caller must Init() and set size)
+	configDirs []string                       // config directories searched in order; the first match wins (NewAppBuilderDirs)
+	configFS   fs.FS                          // optional; if set, configDirs[0] is a path within it (NewAppBuilderFS)
+	appFile    string                         // app config file in the config directory (default app.yaml)
+	startPage  string                         // optional; page shown first instead of "main" (WithStartPage)
+	overlays   []string                       // files deep-merged onto the app config, in order (WithOverlay)
+	resolvers  []valueResolver                // "prefix:key" value resolvers applied at load time (WithValueResolver)
+	registry   *template.FunctionRegistry
+	errors     []error
+	screen     tcell.Screen                   // optional; if set, used for testing (caller must Init() and set size)
+	beforeDraw func(screen tcell.Screen) bool // optional; called from tview's before-draw func
+	clock      template.Clock                 // optional; time source for ctx.Now() (default wall clock)
+	drawers    map[string]builder.DrawFunc    // custom draw functions referenced by box primitives' drawer
+	shutdown   []func()                       // cleanup callbacks run by Application.Stop
+	logf       func(format string, args ...interface{}) // optional; receives recovered callback panics
+	noRecover  bool                           // let callback panics propagate (WithoutPanicRecovery)
+	noRefresh  bool                           // skip the bound-view refresh goroutine (WithoutBackgroundRefresh)
 }
 
 // NewAppBuilder creates a new application builder
 func NewAppBuilder(configDir string) *AppBuilder {
-	return &AppBuilder{
-		configDir: configDir,
-		registry:  template.NewFunctionRegistry(),
-		errors:    make([]error, 0),
+	return NewAppBuilderDirs(configDir)
+}
+
+// NewAppBuilderDirs creates an application builder that searches several config directories in
+// order, e.g. a user's ~/.config/myapp before the directory of bundled defaults. The app file,
+// overlays, and each page ref are read from the first directory that has them, so the override
+// directory only needs the files it changes.
+func NewAppBuilderDirs(configDirs ...string) *AppBuilder {
+	b := &AppBuilder{
+		configDirs: configDirs,
+		appFile:    "app.yaml",
+		registry:   template.NewFunctionRegistry(),
+		errors:     make([]error, 0),
+		drawers:    make(map[string]builder.DrawFunc),
+	}
+	if len(configDirs) == 0 {
+		b.errors = append(b.errors, fmt.Errorf("at least one config directory is required"))
+	}
+	return b
+}
+
+// NewAppBuilderFS creates an application builder that reads its YAML files from fsys, e.g. a config
+// directory embedded with go:embed, so the app ships as a single binary. dir is the slash-separated
+// config directory within fsys ("" or "." for its root).
+func NewAppBuilderFS(fsys fs.FS, dir string) *AppBuilder {
+	b := NewAppBuilder(dir)
+	b.configFS = fsys
+	return b
+}
+
+// WithAppFile sets the name of the app config file in the config directory (default app.yaml),
+// e.g. dashboard.yaml or an environment-specific app.dev.yaml.
+func (b *AppBuilder) WithAppFile(name string) *AppBuilder {
+	if name == "" {
+		b.errors = append(b.errors, fmt.Errorf("app file name is required"))
+		return b
+	}
+	b.appFile = name
+	return b
+}
+
+// WithStartPage shows the named page first instead of "main", e.g. to deep-link into a page from a
+// command-line argument. The name must be one of the app's pages or Build fails.
+func (b *AppBuilder) WithStartPage(name string) *AppBuilder {
+	if name == "" {
+		b.errors = append(b.errors, fmt.Errorf("start page name is required"))
+		return b
 	}
+	b.startPage = name
+	return b
 }
 
-// WithTemplateFunction registers a custom template function
-func (b *AppBuilder) WithTemplateFunction(name string, minArgs int, maxArgs *int, validator func(*template.Context, []string) error, handler interface{}) *AppBuilder {
-	if err := b.registry.Register(name, minArgs, maxArgs, validator, handler); err != nil {
+// WithOverlay deep-merges the app config file at path (relative to the config directory unless
+// absolute) onto the base app config after loading, e.g. per-environment colors or key bindings.
+// Mappings merge key by key; scalars and lists replace the base value. Overlays apply in the order added.
+func (b *AppBuilder) WithOverlay(path string) *AppBuilder {
+	if path == "" {
+		b.errors = append(b.errors, fmt.Errorf("overlay path is required"))
+		return b
+	}
+	b.overlays = append(b.overlays, path)
+	return b
+}
+
+// valueResolver is a resolver registered with WithValueResolver.
+type valueResolver struct {
+	prefix  string
+	resolve func(key string) (string, error)
+}
+
+// WithValueResolver resolves YAML string values of the form "prefix:key" through fn when the
+// config is loaded, e.g. WithValueResolver("secret", vault.Lookup) turns `secret:DB_PASS` into the
+// secret so credentials stay out of the files. Only whole values are resolved, after overlays are
+// merged; resolvers are tried in the order added and a resolver error fails Build (app config) or
+// the page (page files). See config.Loader.AddValueResolver.
+func (b *AppBuilder) WithValueResolver(prefix string, fn func(key string) (string, error)) *AppBuilder {
+	if prefix == "" || strings.Contains(prefix, ":") || fn == nil {
+		b.errors = append(b.errors, fmt.Errorf("value resolver %q: a prefix without ':' and a resolve function are required", prefix))
+		return b
+	}
+	b.resolvers = append(b.resolvers, valueResolver{prefix: prefix, resolve: fn})
+	return b
+}
+
+// WithTemplateFunction registers a custom template function. paramNames optionally names its
+// arguments for error messages (see template.FunctionRegistry.Register).
+func (b *AppBuilder) WithTemplateFunction(name string, minArgs int, maxArgs *int, validator func(*template.Context, []string) error, handler interface{}, paramNames ...string) *AppBuilder {
+	if err := b.registry.Register(name, minArgs, maxArgs, validator, handler, paramNames...); err != nil {
 		b.errors = append(b.errors, fmt.Errorf("failed to register template function %q: %w", name, err))
 	}
 	return b
 }
 
+// WithFunctionDescription sets the one-line description of a registered template function or
+// evaluator, as reported by FunctionRegistry.List and the listFunctions builtin.
+func (b *AppBuilder) WithFunctionDescription(name, description string) *AppBuilder {
+	if err := b.registry.Describe(name, description); err != nil {
+		b.errors = append(b.errors, fmt.Errorf("failed to describe template function: %w", err))
+	}
+	return b
+}
+
 // With calls fn with the builder so the app can perform custom
 // registration with the AppBuilder. Returns fn(b) for chaining.
 func (b *AppBuilder) With(fn func(*AppBuilder) *AppBuilder) *AppBuilder {
@@ -69,6 +179,65 @@ func (b *AppBuilder) WithScreen(screen tcell.Screen) *AppBuilder {
 	return b
 }
 
+// WithBeforeDraw sets a function called before tview draws each frame, after it clears the screen.
+// Return false to draw the UI on top as usual; pages fill their own backgrounds, so anything fn
+// paints where a page is drawn is covered. Return true to skip drawing the UI for that frame,
+// e.g. to show a "terminal too small" message instead.
+func (b *AppBuilder) WithBeforeDraw(fn func(screen tcell.Screen) bool) *AppBuilder {
+	b.beforeDraw = fn
+	return b
+}
+
+// WithClock sets the time source returned by ctx.Now() in template functions (default: wall clock).
+// Use a fixed clock in tests so time displays are deterministic.
+func (b *AppBuilder) WithClock(clock template.Clock) *AppBuilder {
+	b.clock = clock
+	return b
+}
+
+// WithDrawer registers a named draw function that box primitives can reference with
+// drawer: name. It is attached via tview's Box.SetDrawFunc, receives the box's outer rect on
+// every draw, and returns the inner rect; use it for custom rendering inside a YAML layout.
+func (b *AppBuilder) WithDrawer(name string, fn func(screen tcell.Screen, x, y, width, height int) (int, int, int, int)) *AppBuilder {
+	if name == "" || fn == nil {
+		b.errors = append(b.errors, fmt.Errorf("drawer %q: name and draw function are required", name))
+		return b
+	}
+	b.drawers[name] = fn
+	return b
+}
+
+// WithLogger sets a logger (e.g. log.Printf) for runtime problems such as panics recovered from
+// template function handlers. Messages are discarded by default.
+func (b *AppBuilder) WithLogger(logf func(format string, args ...interface{})) *AppBuilder {
+	b.logf = logf
+	return b
+}
+
+// WithoutPanicRecovery lets panics in template function handlers propagate and crash the app.
+// By default a panicking callback is recovered, logged and reported in an error modal.
+func (b *AppBuilder) WithoutPanicRecovery() *AppBuilder {
+	b.noRecover = true
+	return b
+}
+
+// WithoutBackgroundRefresh skips the goroutine that refreshes bound views, for static UIs that
+// never change state. Bound views and OnStateChange subscribers (e.g. spinners, disabledWhen)
+// then only update when the caller runs Context.RefreshDirtyBoundViews on the main goroutine.
+func (b *AppBuilder) WithoutBackgroundRefresh() *AppBuilder {
+	b.noRefresh = true
+	return b
+}
+
+// WithShutdown registers a cleanup callback that Application.Stop runs (once) before stopping tview.
+// Use it to stop goroutines started by custom template functions, such as tickers or watchers.
+func (b *AppBuilder) WithShutdown(fn func()) *AppBuilder {
+	if fn != nil {
+		b.shutdown = append(b.shutdown, fn)
+	}
+	return b
+}
+
 // Build creates and configures a tview application from YAML configuration files.
 // Returns (app, pageErrors, err) where err is fatal (app config load/validate failure),
 // and pageErrors are non-fatal per-page failures (missing/invalid pages are skipped).
@@ -87,13 +256,33 @@ func (b *AppBuilder) Build() (*Application, []error, error) {
 
 	// Create template context
 	ctx := template.NewContext(tvApp, pages)
+	// Record the screen before each draw so builtins can write to the terminal (copyToClipboard)
+	beforeDraw := b.beforeDraw
+	tvApp.SetBeforeDrawFunc(func(screen tcell.Screen) bool {
+		ctx.SetScreen(screen)
+		return beforeDraw != nil && beforeDraw(screen)
+	})
+	ctx.SetLogger(b.logf)
+	ctx.SetRecoverPanics(!b.noRecover)
+	if b.clock != nil {
+		ctx.SetClock(b.clock)
+	}
 
 	// Load configuration
-	loader := config.NewLoader(b.configDir)
-	appConfig, err := loader.LoadApp("app.yaml")
+	loader := config.NewLoaderDirs(b.configDirs...)
+	if b.configFS != nil {
+		loader = config.NewLoaderFS(b.configFS, b.configDirs[0])
+	}
+	for _, r := range b.resolvers {
+		loader.AddValueResolver(r.prefix, r.resolve)
+	}
+	appConfig, err := loader.LoadApp(b.appFile, b.overlays...)
 	if err != nil {
 		return nil, nil, err
 	}
+	if err := expandPagesGlob(appConfig, loader, append([]string{b.appFile}, b.overlays...)); err != nil {
+		return nil, nil, err
+	}
 
 	// Validate app config
 	validator := config.NewValidator()
@@ -103,32 +292,59 @@ func (b *AppBuilder) Build() (*Application, []error, error) {
 	if err := validator.ValidateAppRefs(appConfig, loader); err != nil {
 		return nil, nil, err
 	}
+	validator.SetGlobalKeyBindings(appConfig.Application.GlobalKeyBindings)
+	if b.startPage != "" && !hasPage(appConfig, b.startPage) {
+		return nil, nil, fmt.Errorf("start page %q is not one of the app's pages", b.startPage)
+	}
+
+	// Read, parse, and validate all page files concurrently; this also warms the loader cache
+	// so template validation below does not read them again.
+	loaded := loadPages(loader, validator, appConfig.Application.Root.Pages)
 
 	// Validate template expressions before building pages
 	if err := b.validateTemplateExpressions(appConfig, loader); err != nil {
 		return nil, nil, fmt.Errorf("template validation failed: %w", err)
 	}
 
+	// The theme's colors are defaults for every primitive the builder creates
+	ctx.SetTheme(appConfig.Application.Theme)
+	shutdown := append([]func(){}, b.shutdown...)
+	if theme := appConfig.Application.Theme; theme != nil && theme.BorderStyle != "" {
+		// tview.Borders is process-wide: put the previous characters back when the app stops
+		prevBorders := tview.Borders
+		setBorderStyle(theme.BorderStyle)
+		shutdown = append(shutdown, func() { tview.Borders = prevBorders })
+	}
+	ctx.SetFocusGroups(appConfig.Application.FocusGroups)
+	if bg := appConfig.Application.Root.BackgroundColor; bg != "" {
+		pages.SetBackgroundColor(ctx.Colors.Parse(bg))
+	}
+
 	// Create builder with registry
 	uiBuilder := builder.NewBuilder(ctx, b.registry)
 	uiBuilder.SetLoader(loader) // Enable nested pages support
+	uiBuilder.SetDrawers(b.drawers)
 
-	// Build all pages from config, collecting non-fatal errors
+	// Build all pages from config in declaration order, collecting non-fatal errors. Primitives
+	// are built here, sequentially, so tview and the primitive registry see a deterministic order
+	// (a later page's named primitives replace an earlier page's, as before).
 	var pageErrors []error
-	for _, pageRef := range appConfig.Application.Root.Pages {
-		pageConfig, err := loader.LoadPage(pageRef.Ref)
-		if err != nil {
+	pageMouseCaptures := make(map[string]string) // page name -> onMouseCapture expression
+	// The executor is set before building so each page's onReady can run as soon as it is added.
+	executor := template.NewExecutor(ctx, b.registry)
+	ctx.SetExecutor(executor)
+	for i, pageRef := range appConfig.Application.Root.Pages {
+		pageConfig := loaded[i].config
+		if err := loaded[i].loadErr; err != nil {
 			pageErrors = append(pageErrors, fmt.Errorf("error loading page %s: %w", pageRef.Name, err))
 			continue
 		}
-
-		// Validate page config
-		if err := validator.ValidatePage(pageConfig); err != nil {
+		if err := loaded[i].validErr; err != nil {
 			pageErrors = append(pageErrors, fmt.Errorf("invalid page config %s: %w", pageRef.Name, err))
 			continue
 		}
 
-		pagePrimitive, err := uiBuilder.BuildFromConfig(pageConfig)
+		pagePrimitive, err := uiBuilder.BuildFromConfigRef(pageConfig, pageRef.Ref)
 		if err != nil {
 			pageErrors = append(pageErrors, fmt.Errorf("error building page %s: %w", pageRef.Name, err))
 			continue
@@ -137,6 +353,13 @@ func (b *AppBuilder) Build() (*Application, []error, error) {
 		// Add to pages
 		visible := pageRef.Name == "main"
 		pages.AddPage(pageRef.Name, pagePrimitive, true, visible)
+		if pageConfig.OnMouseCapture != "" {
+			pageMouseCaptures[pageRef.Name] = pageConfig.OnMouseCapture
+		}
+		ctx.RegisterPageLifecycle(pageRef.Name, pageConfig.OnShow, pageConfig.OnHide)
+		if pageConfig.OnReady != "" {
+			ctx.RunCallback(pageConfig.OnReady)
+		}
 	}
 
 	// Create wrapped application with lifecycle management
@@ -144,38 +367,49 @@ func (b *AppBuilder) Build() (*Application, []error, error) {
 	app := &Application{
 		Application: tvApp,
 		stopRefresh: stopRefresh,
-	}
-
-	// Background goroutine: periodically refresh bound views whose state is dirty.
-	// Does not depend on clock or user input; runs continuously and queues updates via QueueUpdateDraw.
-	// The goroutine stops when stopRefresh channel is closed (via app.Stop()).
-	go func() {
-		ticker := time.NewTicker(150 * time.Millisecond)
-		defer ticker.Stop()
-		for {
-			select {
-			case <-stopRefresh:
-				return
-			case <-ticker.C:
-				if !ctx.HasDirtyKeys() {
-					continue
+		shutdown:    shutdown,
+	}
+	// Builtins such as stopApp stop through the wrapper so background goroutines stop too;
+	// goroutines started with ctx.Go see ctx.Done() close.
+	ctx.SetStopFunc(app.Stop)
+	ctx.SetDone(stopRefresh)
+
+	// Background goroutine: refresh bound views whose state is dirty, at most once per frame
+	// interval (see Context.RunRefreshLoop). It stops when stopRefresh is closed (via app.Stop()).
+	// stopRefresh is created either way, since it is also ctx.Done().
+	if !b.noRefresh {
+		go ctx.RunRefreshLoop(stopRefresh)
+	}
+
+	// onTick runs every tickInterval on the main goroutine until the app stops (ctx.Done())
+	if onTick := appConfig.Application.OnTick; onTick != "" {
+		interval, _ := time.ParseDuration(appConfig.Application.TickInterval) // checked by ValidateApp
+		ctx.Go(func() {
+			ticker := time.NewTicker(interval)
+			defer ticker.Stop()
+			for {
+				select {
+				case <-ctx.Done():
+					return
+				case <-ticker.C:
+					ctx.QueueUpdateDraw(func() { ctx.RunCallback(onTick) })
 				}
-				tvApp.QueueUpdateDraw(func() {
-					ctx.RefreshDirtyBoundViews()
-				})
 			}
-		}
-	}()
+		})
+	}
 
 	// Set input capture only when we have global key bindings; avoid running refresh
 	// from capture to prevent deadlock (QueueUpdate would block) or draw re-entrancy.
-	executor := template.NewExecutor(ctx, b.registry)
-	ctx.SetExecutor(executor)
+	ctx.SetKeyBindings(appConfig.Application.GlobalKeyBindings)
 	if len(appConfig.Application.GlobalKeyBindings) > 0 {
 		passthrough := appConfig.Application.EscapePassthroughPages
 		tvApp.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
-			// On Escape, if current page is in passthrough list, let the primitive (e.g. form) handle it.
-			if event.Key() == tcell.KeyEscape && len(passthrough) > 0 {
+			// On Escape, if the focused primitive handles Escape itself (a field of a form with a
+			// cancel callback) or the current page is in the passthrough list, let the primitive handle it.
+			if event.Key() == tcell.KeyEscape {
+				if handlesEscape(tvApp.GetFocus(), ctx) {
+					return event
+				}
 				if front, _ := pages.GetFrontPage(); front != "" {
 					for _, p := range passthrough {
 						if p == front {
@@ -189,6 +423,9 @@ func (b *AppBuilder) Build() (*Application, []error, error) {
 					callback, err := executor.ExecuteCallback(binding.Action)
 					if err == nil {
 						callback()
+						if binding.Passthrough {
+							return event
+						}
 						return nil
 					}
 				}
@@ -197,6 +434,35 @@ func (b *AppBuilder) Build() (*Application, []error, error) {
 		})
 	}
 
+	// Mouse capture hooks: store the event position and action in state, run the app-level
+	// hook and then the front page's hook, and always pass the event through.
+	if appConfig.Application.OnMouseCapture != "" || len(pageMouseCaptures) > 0 {
+		appMouseCapture := appConfig.Application.OnMouseCapture
+		tvApp.SetMouseCapture(func(event *tcell.EventMouse, action tview.MouseAction) (*tcell.EventMouse, tview.MouseAction) {
+			if event == nil {
+				return event, action
+			}
+			front, _ := pages.GetFrontPage()
+			pageMouseCapture := pageMouseCaptures[front]
+			if appMouseCapture == "" && pageMouseCapture == "" {
+				return event, action
+			}
+			x, y := event.Position()
+			ctx.SetStateDirect("__mouseX", x)
+			ctx.SetStateDirect("__mouseY", y)
+			ctx.SetStateDirect("__mouseAction", template.MouseActionName(action))
+			for _, expr := range []string{appMouseCapture, pageMouseCapture} {
+				if expr == "" {
+					continue
+				}
+				if callback, err := executor.ExecuteCallback(expr); err == nil {
+					callback()
+				}
+			}
+			return event, action
+		})
+	}
+
 	// Apply mouse setting (default to true when not specified in config)
 	enableMouse := true
 	if appConfig.Application.EnableMouse != nil {
@@ -204,9 +470,125 @@ func (b *AppBuilder) Build() (*Application, []error, error) {
 	}
 
 	app.Application = tvApp.SetRoot(pages, true).EnableMouse(enableMouse)
+
+	if b.startPage != "" && pages.HasPage(b.startPage) {
+		pages.SwitchToPage(b.startPage)
+	}
+
+	// The initial page is shown without navigation, so run its onShow here.
+	if front, _ := pages.GetFrontPage(); front != "" {
+		ctx.RunPageShow(front)
+	}
+
+	// onStartup is queued, so it runs once the app is running, right after the first draw
+	if onStartup := appConfig.Application.OnStartup; onStartup != "" {
+		ctx.QueueUpdateDraw(func() { ctx.RunCallback(onStartup) })
+	}
 	return app, pageErrors, nil
 }
 
+// setBorderStyle sets the characters tview draws unfocused borders with ("single", "rounded", or
+// "thick"). tview keeps them in a package variable, so the style applies to every box in the
+// process until Build's Application.Stop restores the previous characters.
+func setBorderStyle(style string) {
+	b := &tview.Borders
+	switch style {
+	case "single":
+		b.Horizontal, b.Vertical = tview.BoxDrawingsLightHorizontal, tview.BoxDrawingsLightVertical
+		b.TopLeft, b.TopRight = tview.BoxDrawingsLightDownAndRight, tview.BoxDrawingsLightDownAndLeft
+		b.BottomLeft, b.BottomRight = tview.BoxDrawingsLightUpAndRight, tview.BoxDrawingsLightUpAndLeft
+	case "rounded":
+		b.Horizontal, b.Vertical = tview.BoxDrawingsLightHorizontal, tview.BoxDrawingsLightVertical
+		b.TopLeft, b.TopRight = tview.BoxDrawingsLightArcDownAndRight, tview.BoxDrawingsLightArcDownAndLeft
+		b.BottomLeft, b.BottomRight = tview.BoxDrawingsLightArcUpAndRight, tview.BoxDrawingsLightArcUpAndLeft
+	case "thick":
+		b.Horizontal, b.Vertical = tview.BoxDrawingsHeavyHorizontal, tview.BoxDrawingsHeavyVertical
+		b.TopLeft, b.TopRight = tview.BoxDrawingsHeavyDownAndRight, tview.BoxDrawingsHeavyDownAndLeft
+		b.BottomLeft, b.BottomRight = tview.BoxDrawingsHeavyUpAndRight, tview.BoxDrawingsHeavyUpAndLeft
+	}
+}
+
+// hasPage reports whether name is one of the pages listed under the app's root.
+func hasPage(appConfig *config.AppConfig, name string) bool {
+	for _, page := range appConfig.Application.Root.Pages {
+		if page.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// maxPageLoaders bounds how many page files loadPages reads and parses at once.
+const maxPageLoaders = 8
+
+// loadedPage is the result of loading and validating one page file.
+type loadedPage struct {
+	config   *config.PageConfig
+	loadErr  error // reading or parsing the file failed
+	validErr error // the page config is invalid
+}
+
+// loadPages loads and validates the page files for refs concurrently, using at most
+// maxPageLoaders goroutines. Inline pages are validated without reading a file. Results are
+// returned in the same order as refs.
+func loadPages(loader *config.Loader, validator *config.Validator, refs []config.PageRef) []loadedPage {
+	results := make([]loadedPage, len(refs))
+	sem := make(chan struct{}, maxPageLoaders)
+	var wg sync.WaitGroup
+	for i, pageRef := range refs {
+		if pageRef.Page != nil {
+			results[i] = loadedPage{config: pageRef.Page, validErr: validator.ValidatePage(pageRef.Page)}
+			continue
+		}
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(i int, ref string) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			cfg, err := loader.LoadPage(ref)
+			if err != nil {
+				results[i] = loadedPage{loadErr: err}
+				return
+			}
+			results[i] = loadedPage{config: cfg, validErr: validator.ValidatePage(cfg)}
+		}(i, pageRef.Ref)
+	}
+	wg.Wait()
+	return results
+}
+
+// expandPagesGlob appends the pages matched by root.pagesGlob to root.pages, skipping the app config
+// files (app file and overlays) and any page whose name or ref is already listed, so explicit
+// entries take precedence.
+func expandPagesGlob(appConfig *config.AppConfig, loader *config.Loader, appFiles []string) error {
+	root := &appConfig.Application.Root
+	if root.PagesGlob == "" {
+		return nil
+	}
+	refs, err := loader.GlobPages(root.PagesGlob)
+	if err != nil {
+		return err
+	}
+	seen := make(map[string]bool)
+	for _, file := range appFiles {
+		seen["ref:"+filepath.Clean(file)] = true
+	}
+	for _, page := range root.Pages {
+		seen["name:"+page.Name] = true
+		if page.Ref != "" {
+			seen["ref:"+filepath.Clean(page.Ref)] = true
+		}
+	}
+	for _, page := range refs {
+		if seen["name:"+page.Name] || seen["ref:"+page.Ref] {
+			continue
+		}
+		seen["name:"+page.Name] = true
+		root.Pages = append(root.Pages, page)
+	}
+	return nil
+}
+
 // validateTemplateExpressions validates that all template expressions reference existing functions/evaluators
 func (b *AppBuilder) validateTemplateExpressions(appConfig *config.AppConfig, loader *config.Loader) error {
 	var errors []string
@@ -220,12 +602,25 @@ func (b *AppBuilder) validateTemplateExpressions(appConfig *config.AppConfig, lo
 		}
 	}
 
+	if appConfig.Application.OnMouseCapture != "" {
+		errors = append(errors, b.validateExpression(appConfig.Application.OnMouseCapture, "application onMouseCapture")...)
+	}
+	if appConfig.Application.OnStartup != "" {
+		errors = append(errors, b.validateExpression(appConfig.Application.OnStartup, "application onStartup")...)
+	}
+	if appConfig.Application.OnTick != "" {
+		errors = append(errors, b.validateExpression(appConfig.Application.OnTick, "application onTick")...)
+	}
+
 	// Validate all pages
 	for _, pageRef := range appConfig.Application.Root.Pages {
-		pageConfig, err := loader.LoadPage(pageRef.Ref)
-		if err != nil {
-			// Page loading errors are already handled in Build(), skip here
-			continue
+		pageConfig := pageRef.Page
+		if pageConfig == nil {
+			var err error
+			if pageConfig, err = loader.LoadPage(pageRef.Ref); err != nil {
+				// Page loading errors are already handled in Build(), skip here
+				continue
+			}
 		}
 
 		// Validate page-level expressions
@@ -247,7 +642,7 @@ func (b *AppBuilder) validateExpression(expr, context string) []string {
 	}
 
 	var errors []string
-	
+
 	// Extract template expressions (handles both {{ }} and bare expressions)
 	expr = strings.TrimSpace(expr)
 	expr = strings.TrimPrefix(expr, "{{")
@@ -258,23 +653,23 @@ func (b *AppBuilder) validateExpression(expr, context string) []string {
 		return nil
 	}
 
-	// Parse function name from expression
-	re := regexp.MustCompile(`^(\w+)`)
-	matches := re.FindStringSubmatch(expr)
-	if len(matches) < 2 {
-		return errors
+	// Check the function or evaluator exists and accepts the arguments given
+	if err := b.registry.CheckCall(expr); err != nil {
+		errors = append(errors, fmt.Sprintf("%s: %v in expression %q", context, err, expr))
 	}
 
-	funcName := matches[1]
+	return errors
+}
 
-	// Check if it exists as either a function or evaluator
-	if _, ok := b.registry.Get(funcName); !ok {
-		if _, ok := b.registry.GetEvaluator(funcName); !ok {
-			errors = append(errors, fmt.Sprintf("%s: unknown function/evaluator %q in expression %q", context, funcName, expr))
-		}
+// validateText validates the {{ }} blocks in display text, which are evaluated with evaluators
+func (b *AppBuilder) validateText(text, context string) []string {
+	if !strings.Contains(text, "{{") {
+		return nil
 	}
-
-	return errors
+	if err := b.registry.CheckText(text); err != nil {
+		return []string{fmt.Sprintf("%s: %v in text %q", context, err, text)}
+	}
+	return nil
 }
 
 // validatePageExpressions validates all template expressions in a page config
@@ -291,12 +686,26 @@ func (b *AppBuilder) validatePageExpressions(page *config.PageConfig, pageName s
 	if page.OnNodeSelected != "" {
 		errors = append(errors, b.validateExpression(page.OnNodeSelected, fmt.Sprintf("page %q OnNodeSelected", pageName))...)
 	}
+	if page.OnMouseCapture != "" {
+		errors = append(errors, b.validateExpression(page.OnMouseCapture, fmt.Sprintf("page %q OnMouseCapture", pageName))...)
+	}
+	if page.OnReady != "" {
+		errors = append(errors, b.validateExpression(page.OnReady, fmt.Sprintf("page %q OnReady", pageName))...)
+	}
+	if page.OnShow != "" {
+		errors = append(errors, b.validateExpression(page.OnShow, fmt.Sprintf("page %q OnShow", pageName))...)
+	}
+	if page.OnHide != "" {
+		errors = append(errors, b.validateExpression(page.OnHide, fmt.Sprintf("page %q OnHide", pageName))...)
+	}
 
 	// Validate list items
 	for i, item := range page.ListItems {
 		if item.OnSelected != "" {
 			errors = append(errors, b.validateExpression(item.OnSelected, fmt.Sprintf("page %q listItem[%d]", pageName, i))...)
 		}
+		errors = append(errors, b.validateText(item.MainText, fmt.Sprintf("page %q listItem[%d] mainText", pageName, i))...)
+		errors = append(errors, b.validateText(item.SecondaryText, fmt.Sprintf("page %q listItem[%d] secondaryText", pageName, i))...)
 	}
 
 	// Validate form items
@@ -342,12 +751,32 @@ func (b *AppBuilder) validatePrimitiveExpressions(prim *config.Primitive, contex
 	if prim.OnNodeSelected != "" {
 		errors = append(errors, b.validateExpression(prim.OnNodeSelected, fmt.Sprintf("%s OnNodeSelected", context))...)
 	}
+	if prim.OnFocus != "" {
+		errors = append(errors, b.validateExpression(prim.OnFocus, fmt.Sprintf("%s OnFocus", context))...)
+	}
+	if prim.OnBlur != "" {
+		errors = append(errors, b.validateExpression(prim.OnBlur, fmt.Sprintf("%s OnBlur", context))...)
+	}
+
+	// Validate display text, which may use evaluators
+	errors = append(errors, b.validateText(prim.Text, fmt.Sprintf("%s text", context))...)
+	errors = append(errors, b.validateText(prim.Label, fmt.Sprintf("%s label", context))...)
+	for r, row := range prim.Rows {
+		for c, cell := range row {
+			errors = append(errors, b.validateText(cell, fmt.Sprintf("%s rows[%d][%d]", context, r, c))...)
+		}
+	}
+	for _, node := range prim.Nodes {
+		errors = append(errors, b.validateText(node.Text, fmt.Sprintf("%s node %q", context, node.Name))...)
+	}
 
 	// Validate list items
 	for i, item := range prim.ListItems {
 		if item.OnSelected != "" {
 			errors = append(errors, b.validateExpression(item.OnSelected, fmt.Sprintf("%s listItem[%d]", context, i))...)
 		}
+		errors = append(errors, b.validateText(item.MainText, fmt.Sprintf("%s listItem[%d] mainText", context, i))...)
+		errors = append(errors, b.validateText(item.SecondaryText, fmt.Sprintf("%s listItem[%d] secondaryText", context, i))...)
 	}
 
 	// Validate form items
@@ -375,3 +804,14 @@ func (b *AppBuilder) validatePrimitiveExpressions(prim *config.Primitive, contex
 
 	return errors
 }
+
+// handlesEscape reports whether the focused primitive uses Escape itself, so the global
+// Escape binding should not capture it: a Form, InputField, or TextArea inside a form that
+// has a cancel callback (onCancel/onSubmit). Standalone input fields keep global Escape.
+func handlesEscape(focused tview.Primitive, ctx *template.Context) bool {
+	switch focused.(type) {
+	case *tview.Form, *tview.InputField, *tview.TextArea:
+		return ctx.CancelableFormHasFocus()
+	}
+	return false
+}
@@ -2,17 +2,22 @@ package builder
 
 import (
 	"fmt"
+	"path/filepath"
 	"strings"
 
 	"github.com/gdamore/tcell/v2"
 	"github.com/rivo/tview"
 	"github.com/cassdeckard/tviewyaml/config"
+	"github.com/cassdeckard/tviewyaml/keys"
 	"github.com/cassdeckard/tviewyaml/template"
 )
 
 // BuildContext tracks the component path for better error messages
 type BuildContext struct {
-	path []string
+	path      []string
+	shortcuts []pageShortcut // button shortcuts collected while building the page
+	filters   []listFilter   // filterable lists whose filterInput is wired once the page is built
+	dir       string         // directory of the page file being built, relative to the loader base path
 }
 
 // NewBuildContext creates a new build context
@@ -58,13 +63,24 @@ type Builder struct {
 	executor *template.Executor
 	context  *template.Context
 	loader   PageLoader
+	drawers  map[string]DrawFunc
 }
 
+// DrawFunc draws a box's content; it has the signature of tview's Box.SetDrawFunc. It receives
+// the box's outer rect and returns the inner rect left for the box's own content.
+type DrawFunc func(screen tcell.Screen, x, y, width, height int) (int, int, int, int)
+
 // PageLoader interface for loading page configurations
 type PageLoader interface {
 	LoadPage(ref string) (*config.PageConfig, error)
 }
 
+// refResolver is implemented by loaders that resolve nested refs relative to the referencing
+// file (see config.Loader.ResolveRef). Loaders without it resolve every ref against their base.
+type refResolver interface {
+	ResolveRef(fromDir, ref string) string
+}
+
 // assertPrimitiveType safely asserts a primitive to a specific type, returning an error if the type doesn't match.
 // This prevents panics when factory and builder get out of sync.
 func assertPrimitiveType[T tview.Primitive](p tview.Primitive) (T, error) {
@@ -96,9 +112,21 @@ func (b *Builder) SetLoader(loader PageLoader) {
 	b.loader = loader
 }
 
+// SetDrawers sets the named draw functions that box primitives reference with drawer.
+func (b *Builder) SetDrawers(drawers map[string]DrawFunc) {
+	b.drawers = drawers
+}
+
 // BuildFromConfig builds a tview primitive from a page configuration
 func (b *Builder) BuildFromConfig(pageConfig *config.PageConfig) (tview.Primitive, error) {
+	return b.BuildFromConfigRef(pageConfig, "")
+}
+
+// BuildFromConfigRef builds a tview primitive from a page configuration loaded from ref
+// (relative to the loader base path). Nested page refs are resolved relative to ref's directory.
+func (b *Builder) BuildFromConfigRef(pageConfig *config.PageConfig, ref string) (tview.Primitive, error) {
 	bc := NewBuildContext()
+	bc.dir = filepath.Dir(ref)
 	bc.Push(fmt.Sprintf("page:%s", pageConfig.Type))
 	defer bc.Pop()
 
@@ -112,8 +140,30 @@ func (b *Builder) BuildFromConfig(pageConfig *config.PageConfig) (tview.Primitiv
 	if err := b.mapper.ApplyPageProperties(primitive, pageConfig); err != nil {
 		return nil, bc.Errorf("%w", err)
 	}
+	b.context.RegisterPrimitive(pageConfig.Name, primitive)
+
+	result, err := b.buildPageContent(primitive, pageConfig, bc)
+	if err != nil {
+		return nil, err
+	}
+	if len(bc.shortcuts) > 0 {
+		if err := bindPageShortcuts(result, bc.shortcuts, bc); err != nil {
+			return nil, err
+		}
+	}
+	if err := b.wireListFilters(bc.filters, bc); err != nil {
+		return nil, err
+	}
+	if len(pageConfig.FocusOrder) > 0 {
+		if err := b.setupFocusOrder(result, pageConfig.FocusOrder, bc); err != nil {
+			return nil, err
+		}
+	}
+	return result, nil
+}
 
-	// Build based on type
+// buildPageContent builds the page's children based on its type
+func (b *Builder) buildPageContent(primitive tview.Primitive, pageConfig *config.PageConfig, bc *BuildContext) (tview.Primitive, error) {
 	switch pageConfig.Type {
 	case "list":
 		list, err := assertPrimitiveType[*tview.List](primitive)
@@ -151,19 +201,114 @@ func (b *Builder) BuildFromConfig(pageConfig *config.PageConfig) (tview.Primitiv
 			return nil, bc.Errorf("failed to build modal: %w", err)
 		}
 		return b.buildModal(modal, pageConfig, bc)
+	case "dialog":
+		dialog, err := assertPrimitiveType[*Dialog](primitive)
+		if err != nil {
+			return nil, bc.Errorf("failed to build dialog: %w", err)
+		}
+		return b.buildDialog(dialog, pageConfig, bc)
 	default:
 		return primitive, nil
 	}
 }
 
+// inputCapturer is implemented by every primitive built on tview's Box.
+type inputCapturer interface {
+	GetInputCapture() func(event *tcell.EventKey) *tcell.EventKey
+	SetInputCapture(capture func(event *tcell.EventKey) *tcell.EventKey) *tview.Box
+}
+
+// pageShortcut is a button shortcut bound on the page that contains the button.
+type pageShortcut struct {
+	key    string
+	button *tview.Button
+	action func()
+}
+
+// bindPageShortcuts installs an input capture on the page that runs a button's action when its
+// shortcut is pressed while focus is anywhere on the page. Disabled buttons ignore their shortcut.
+// Other keys go to any capture the page already had.
+func bindPageShortcuts(page tview.Primitive, shortcuts []pageShortcut, bc *BuildContext) error {
+	box, ok := page.(inputCapturer)
+	if !ok {
+		return bc.Errorf("button shortcut: page primitive %T does not support input capture", page)
+	}
+	prev := box.GetInputCapture()
+	box.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		for _, sc := range shortcuts {
+			if template.MatchesKeyBinding(event, config.KeyBinding{Key: sc.key}) && !sc.button.IsDisabled() {
+				sc.action()
+				return nil
+			}
+		}
+		if prev != nil {
+			return prev(event)
+		}
+		return event
+	})
+	return nil
+}
+
+// setupFocusOrder installs an input capture on the page that moves focus through the named
+// primitives in order on Tab (forward) and Backtab/Shift+Tab (backward), wrapping at the ends.
+// If focus is outside the ring, Tab enters at the first primitive and Backtab at the last.
+// Other keys go to any capture the page already had (e.g. list shortcuts).
+func (b *Builder) setupFocusOrder(page tview.Primitive, names []string, bc *BuildContext) error {
+	ring := make([]tview.Primitive, 0, len(names))
+	for _, name := range names {
+		p, ok := b.context.GetPrimitive(name)
+		if !ok {
+			return bc.Errorf("focusOrder: no primitive named %q", name)
+		}
+		ring = append(ring, p)
+	}
+	box, ok := page.(inputCapturer)
+	if !ok {
+		return bc.Errorf("focusOrder: page primitive %T does not support input capture", page)
+	}
+	prev := box.GetInputCapture()
+	box.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		step := 0
+		switch {
+		case event.Key() == tcell.KeyBacktab, event.Key() == tcell.KeyTab && event.Modifiers()&tcell.ModShift != 0:
+			step = -1
+		case event.Key() == tcell.KeyTab:
+			step = 1
+		}
+		if step == 0 {
+			if prev != nil {
+				return prev(event)
+			}
+			return event
+		}
+		next := 0
+		if step < 0 {
+			next = len(ring) - 1
+		}
+		for i, p := range ring {
+			if p.HasFocus() {
+				next = (i + step + len(ring)) % len(ring)
+				break
+			}
+		}
+		if b.context.App != nil {
+			b.context.App.SetFocus(ring[next])
+		}
+		return nil
+	})
+	return nil
+}
+
 // buildList populates a list with items
 func (b *Builder) buildList(list *tview.List, cfg *config.PageConfig, bc *BuildContext) (tview.Primitive, error) {
-	for i, item := range cfg.ListItems {
+	items := cfg.ListItems
+	if cfg.AutoShortcuts {
+		items = withAutoShortcuts(items)
+	}
+	var keyShortcuts []listKeyShortcut
+	for i, item := range items {
 		bc.Push(fmt.Sprintf("listItem[%d]", i))
-		shortcut := rune(0)
-		if len(item.Shortcut) > 0 {
-			shortcut = rune(item.Shortcut[0])
-		}
+		shortcut, plain := listShortcutRune(item.Shortcut)
 
 		// Create callback from template
 		var callback func()
@@ -177,18 +322,82 @@ func (b *Builder) buildList(list *tview.List, cfg *config.PageConfig, bc *BuildC
 		}
 
 		list.AddItem(item.MainText, item.SecondaryText, shortcut, callback)
+		if !plain {
+			keyShortcuts = append(keyShortcuts, listKeyShortcut{index: i, key: item.Shortcut, callback: callback})
+		}
 		bc.Pop()
 	}
+	bindListKeyShortcuts(list, keyShortcuts)
+	if err := b.bindListItemsText(list, items, nil, false, bc); err != nil {
+		return nil, err
+	}
 
 	return list, nil
 }
 
+// bindListItemsText evaluates template syntax in the items' main and secondary text and applies
+// their per-item colors as color tags (tview lists always parse them). Each item whose text uses
+// bindState is refreshed in place when the key changes; filtered, if not nil, holds the list's
+// entries (item positions change as the list is filtered). With hints, each item's shortcut is
+// appended to its secondary text.
+func (b *Builder) bindListItemsText(list *tview.List, items []config.ListItem, filtered *filterableList, hints bool, bc *BuildContext) error {
+	for i, item := range items {
+		i, mainText, secondaryText := i, item.MainText, item.SecondaryText
+		mainTag, secondaryTag := b.colorTag(item.MainTextColor), b.colorTag(item.SecondaryTextColor)
+		if filtered != nil {
+			filtered.entries[i].mainTag, filtered.entries[i].secondaryTag = mainTag, secondaryTag
+		}
+		shortcut := ""
+		if hints {
+			shortcut = item.Shortcut
+		}
+		update := func() {
+			secondaryText := withShortcutHint(secondaryText, shortcut)
+			if filtered != nil {
+				filtered.setEntryText(i, mainText, secondaryText)
+			} else if i < list.GetItemCount() {
+				list.SetItemText(i, mainTag+mainText, secondaryTag+secondaryText)
+			}
+		}
+		if err := b.mapper.applyBoundText(item.MainText, func(s string) { mainText = s; update() }); err != nil {
+			return bc.Errorf("listItem[%d] mainText: %w", i, err)
+		}
+		if err := b.mapper.applyBoundText(item.SecondaryText, func(s string) { secondaryText = s; update() }); err != nil {
+			return bc.Errorf("listItem[%d] secondaryText: %w", i, err)
+		}
+	}
+	return nil
+}
+
+// withShortcutHint appends "(shortcut)" to text, separated by a space unless text is empty.
+// Text is returned unchanged when there is no shortcut.
+func withShortcutHint(text, shortcut string) string {
+	switch {
+	case shortcut == "":
+		return text
+	case text == "":
+		return "(" + shortcut + ")"
+	default:
+		return text + " (" + shortcut + ")"
+	}
+}
+
+// colorTag returns the style tag that sets the foreground to color, or "" if color is unset.
+func (b *Builder) colorTag(color string) string {
+	if color == "" {
+		return ""
+	}
+	return "[" + b.context.Colors.Parse(color).String() + "]"
+}
+
 // buildFlex populates a flex container with items
 func (b *Builder) buildFlex(flex *tview.Flex, cfg *config.PageConfig, bc *BuildContext) (tview.Primitive, error) {
+	sizes := make([]flexItemSize, 0, len(cfg.Items))
 	for i, item := range cfg.Items {
 		isSpacer := item.Primitive == nil || item.Spacer
 		if isSpacer {
 			flex.AddItem(nil, item.FixedSize, item.Proportion, item.Focus)
+			sizes = append(sizes, flexItemSize{fixedSize: item.FixedSize, proportion: item.Proportion})
 			continue
 		}
 
@@ -201,17 +410,38 @@ func (b *Builder) buildFlex(flex *tview.Flex, cfg *config.PageConfig, bc *BuildC
 		bc.Pop()
 
 		flex.AddItem(child, item.FixedSize, item.Proportion, item.Focus)
+		sizes = append(sizes, flexItemSize{item: child, fixedSize: item.FixedSize, proportion: item.Proportion, minSize: item.MinSize})
+	}
+	if cfg.ResponsiveDirection || hasMinSize(cfg.Items) {
+		layout := &flexLayout{row: cfg.Direction == "row", items: sizes}
+		if cfg.ResponsiveDirection {
+			layout.breakpoint = cfg.BreakpointWidth
+		}
+		layout.attach(flex)
 	}
 
 	return flex, nil
 }
 
+// hasMinSize reports whether any flex item sets minSize.
+func hasMinSize(items []config.FlexItem) bool {
+	for _, item := range items {
+		if item.MinSize > 0 {
+			return true
+		}
+	}
+	return false
+}
+
 // buildForm populates a form with items
 func (b *Builder) buildForm(form *tview.Form, cfg *config.PageConfig, bc *BuildContext) (tview.Primitive, error) {
-	_, err := b.addFormItems(form, cfg.FormItems, bc)
+	_, err := b.addFormItems(form, cfg.FormItems, cfg.Name, cfg.MarkRequired, bc)
 	if err != nil {
 		return nil, err
 	}
+	if cfg.ButtonsAlign != "" {
+		form.SetButtonsAlign(template.ParseAlignment(cfg.ButtonsAlign))
+	}
 	// Setup form callbacks (cancel and submit)
 	if err := b.setupFormCallbacks(form, cfg.OnCancel, cfg.OnSubmit, cfg.Name, bc); err != nil {
 		return nil, err
@@ -219,10 +449,18 @@ func (b *Builder) buildForm(form *tview.Form, cfg *config.PageConfig, bc *BuildC
 	return form, nil
 }
 
-// addFormItems adds form items to a form (shared logic for both page-level and nested forms)
-func (b *Builder) addFormItems(form *tview.Form, formItems []config.FormItem, bc *BuildContext) (*tview.Form, error) {
+// addFormItems adds form items to a form (shared logic for both page-level and nested forms).
+// name is the form's name, used by items that submit the form (submitOnEnter). With markRequired,
+// the labels of required fields end in a red "*".
+func (b *Builder) addFormItems(form *tview.Form, formItems []config.FormItem, name string, markRequired bool, bc *BuildContext) (*tview.Form, error) {
+	spacers := make(map[int]bool) // button indexes of spacer items
 	for i, item := range formItems {
 		bc.Push(fmt.Sprintf("formItem[%d]:%s", i, item.Type))
+		fieldCount := form.GetFormItemCount()
+		label := item.Label
+		if markRequired && item.Required {
+			label += " [red]*[-]"
+		}
 		switch item.Type {
 		case "inputfield":
 			var acceptFunc func(textToCheck string, lastChar rune) bool
@@ -237,12 +475,15 @@ func (b *Builder) addFormItems(form *tview.Form, formItems []config.FormItem, bc
 				}
 			}
 
+			// An unset fieldWidth (0) spans the form row
+			fieldWidth := item.FieldWidth
+
 			needCustomInput := item.Placeholder != "" || item.PasswordMode || item.OnChanged != ""
 			if needCustomInput {
 				input := tview.NewInputField().
-					SetLabel(item.Label).
+					SetLabel(label).
 					SetText(item.Value).
-					SetFieldWidth(item.FieldWidth)
+					SetFieldWidth(fieldWidth)
 				if acceptFunc != nil {
 					input.SetAcceptanceFunc(acceptFunc)
 				}
@@ -266,7 +507,23 @@ func (b *Builder) addFormItems(form *tview.Form, formItems []config.FormItem, bc
 				}
 				form.AddFormItem(input)
 			} else {
-				form.AddInputField(item.Label, item.Value, item.FieldWidth, acceptFunc, nil)
+				form.AddInputField(label, item.Value, fieldWidth, acceptFunc, nil)
+			}
+			if item.ReadOnly {
+				makeReadOnly(form.GetFormItem(form.GetFormItemCount() - 1).(*tview.InputField))
+			}
+			if item.SubmitOnEnter {
+				if name == "" {
+					bc.Pop()
+					return nil, bc.Errorf("inputfield %q: submitOnEnter requires the form to have a name", item.Label)
+				}
+				// Enter runs the form's onSubmit (via RunFormSubmit); the form then moves focus as usual
+				input := form.GetFormItem(form.GetFormItemCount() - 1).(*tview.InputField)
+				input.SetDoneFunc(func(key tcell.Key) {
+					if key == tcell.KeyEnter {
+						b.context.RunFormSubmit(name)
+					}
+				})
 			}
 
 		case "button":
@@ -280,6 +537,11 @@ func (b *Builder) addFormItems(form *tview.Form, formItems []config.FormItem, bc
 				callback = cb
 			}
 			form.AddButton(item.Label, callback)
+			btn := form.GetButton(form.GetButtonCount() - 1)
+			b.mapper.applyDisabled(item.Disabled, item.DisabledWhen, func(disabled bool) { btn.SetDisabled(disabled) })
+		case "spacer":
+			spacers[form.GetButtonCount()] = true
+			addButtonSpacer(form, item.Width)
 		case "checkbox":
 			var changedFunc func(checked bool)
 			if item.OnChanged != "" {
@@ -290,7 +552,7 @@ func (b *Builder) addFormItems(form *tview.Form, formItems []config.FormItem, bc
 				}
 				changedFunc = func(checked bool) { cb() }
 			}
-			form.AddCheckbox(item.Label, item.Checked, changedFunc)
+			form.AddCheckbox(label, item.Checked, changedFunc)
 		case "dropdown":
 			var selectedFunc func(text string, index int)
 			if item.OnChanged != "" {
@@ -299,12 +561,17 @@ func (b *Builder) addFormItems(form *tview.Form, formItems []config.FormItem, bc
 					bc.Pop()
 					return nil, bc.Errorf("failed to execute callback for dropdown %q: %w", item.Label, err)
 				}
-				selectedFunc = func(text string, index int) { cb() }
+				// Like table onCellSelected, expose the selection in state so a generic handler can react.
+				selectedFunc = func(text string, index int) {
+					b.context.SetStateDirect("__dropdownText", text)
+					b.context.SetStateDirect("__dropdownIndex", index)
+					cb()
+				}
 			}
-			form.AddDropDown(item.Label, item.Options, 0, selectedFunc)
+			form.AddDropDown(label, item.Options, 0, selectedFunc)
 		case "textarea":
 			textarea := tview.NewTextArea().
-				SetLabel(item.Label)
+				SetLabel(label)
 			if item.Value != "" {
 				textarea.SetText(item.Value, true)
 			}
@@ -321,15 +588,100 @@ func (b *Builder) addFormItems(form *tview.Form, formItems []config.FormItem, bc
 			}
 			form.AddFormItem(textarea)
 		}
+		// Buttons are handled above; fields use tview's FormItem.SetDisabled (read-only, dimmed)
+		if form.GetFormItemCount() > fieldCount {
+			field := form.GetFormItem(form.GetFormItemCount() - 1)
+			b.mapper.applyDisabled(item.Disabled, item.DisabledWhen, func(disabled bool) { field.SetDisabled(disabled) })
+		}
 		bc.Pop()
 	}
+	if len(spacers) > 0 {
+		b.skipSpacersOnBacktab(form, spacers)
+	}
 
 	return form, nil
 }
 
-// setupFormCallbacks configures the cancel and submit callbacks for a form
+// defaultSpacerWidth is the width of a form button spacer that does not configure one (also the minimum).
+const defaultSpacerWidth = 4
+
+// addButtonSpacer adds a blank gap of width cells (plus the usual one-cell gaps around it) to the
+// form's button row. tview only lays out
+// buttons, so the spacer is a disabled button (skipped by Tab) whose blank label sets its width.
+// Its draw func fills the button box with the form's background color and returns an empty inner
+// rect, so tview draws no label over it.
+func addButtonSpacer(form *tview.Form, width int) {
+	if width <= 0 {
+		width = defaultSpacerWidth
+	}
+	form.AddButton(strings.Repeat(" ", max(width, defaultSpacerWidth)-defaultSpacerWidth), nil)
+	spacer := form.GetButton(form.GetButtonCount() - 1)
+	spacer.SetDisabled(true)
+	spacer.SetDrawFunc(func(screen tcell.Screen, x, y, w, h int) (int, int, int, int) {
+		bg := form.GetBackgroundColor()
+		if bg.Hex() < 0 {
+			bg = tview.Styles.PrimitiveBackgroundColor
+		}
+		for row := y; row < y+h; row++ {
+			for col := x; col < x+w; col++ {
+				screen.SetContent(col, row, ' ', nil, tcell.StyleDefault.Background(bg))
+			}
+		}
+		return x, y, 0, 0
+	})
+}
+
+// skipSpacersOnBacktab moves Backtab focus past spacer buttons. tview skips disabled buttons only
+// forwards, so Backtab onto a spacer would bounce focus back to the button after it.
+func (b *Builder) skipSpacersOnBacktab(form *tview.Form, spacers map[int]bool) {
+	form.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		if event.Key() != tcell.KeyBacktab {
+			return event
+		}
+		_, button := form.GetFocusedItemIndex()
+		target := button - 1
+		for target >= 0 && spacers[target] {
+			target--
+		}
+		if button < 0 || target == button-1 {
+			return event
+		}
+		index := form.GetFormItemCount() + target
+		if target < 0 {
+			// Before the first button: the last field, or wrap to the last button
+			index = form.GetFormItemCount() - 1
+			if index < 0 {
+				index = form.GetButtonCount() - 1
+			}
+		}
+		form.SetFocus(index)
+		if b.context.App != nil {
+			b.context.App.SetFocus(form)
+		}
+		return nil
+	})
+}
+
+// makeReadOnly keeps input's text from being edited while the field stays focusable, so a value
+// such as an account id can be scrolled through and read in place. tview's InputField has no
+// read-only mode, and its acceptance func only filters typed characters (not Backspace, Delete,
+// or Ctrl+K), so an input capture passes on only the keys that move the cursor or leave the field.
+func makeReadOnly(input *tview.InputField) {
+	input.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		switch event.Key() {
+		case tcell.KeyLeft, tcell.KeyRight, tcell.KeyHome, tcell.KeyEnd, tcell.KeyUp, tcell.KeyDown,
+			tcell.KeyEnter, tcell.KeyEscape, tcell.KeyTab, tcell.KeyBacktab:
+			return event
+		}
+		return nil
+	})
+}
+
+// setupFormCallbacks configures the cancel and submit callbacks for a form and records its
+// fields' initial values for resetForm.
 // This is shared logic used by both buildForm and populateFormItems
 func (b *Builder) setupFormCallbacks(form *tview.Form, onCancel, onSubmit, name string, bc *BuildContext) error {
+	b.context.RecordFormInitialValues(name, form)
 	// Register cancel callback if provided
 	if onCancel != "" && name != "" {
 		cb, err := b.executor.ExecuteCallback(onCancel)
@@ -349,6 +701,7 @@ func (b *Builder) setupFormCallbacks(form *tview.Form, onCancel, onSubmit, name
 			return bc.Errorf("failed to execute form cancel callback: %w", err)
 		}
 		form.SetCancelFunc(cb)
+		b.context.RegisterCancelableForm(form)
 	}
 	if onSubmit != "" && name != "" {
 		cb, err := b.executor.ExecuteCallback(onSubmit)
@@ -366,13 +719,14 @@ func (b *Builder) buildTable(table *tview.Table, cfg *config.PageConfig, bc *Bui
 		return table, nil
 	}
 
-	// Add headers
-	for col, header := range cfg.TableData.Headers {
-		cell := tview.NewTableCell(header).
-			SetTextColor(b.context.Colors.Parse("yellow")).
-			SetAlign(tview.AlignCenter).
-			SetSelectable(false)
-		table.SetCell(0, col, cell)
+	// Add headers (row 0 is reserved for them unless showHeaders is false)
+	data := cfg.TableData
+	startRow := 0
+	if data.ShowHeaders == nil || *data.ShowHeaders {
+		for col, header := range data.Headers {
+			table.SetCell(0, col, b.tableHeaderCell(header, data.HeaderColor, data.HeaderAlign, data.HeaderBackgroundColor))
+		}
+		startRow = 1
 	}
 
 	// Add rows
@@ -380,7 +734,10 @@ func (b *Builder) buildTable(table *tview.Table, cfg *config.PageConfig, bc *Bui
 		for col, cellData := range rowData {
 			cell := tview.NewTableCell(cellData).
 				SetAlign(tview.AlignLeft)
-			table.SetCell(row+1, col, cell)
+			table.SetCell(startRow+row, col, cell)
+			if err := b.mapper.applyBoundText(cellData, func(s string) { cell.SetText(s) }); err != nil {
+				return nil, bc.Errorf("tableData rows[%d][%d]: %w", row, col, err)
+			}
 		}
 	}
 
@@ -407,6 +764,33 @@ func (b *Builder) buildTable(table *tview.Table, cfg *config.PageConfig, bc *Bui
 	return table, nil
 }
 
+// tableHeaderCell creates a non-selectable header cell. Unset color and align default to yellow
+// and center; an unset background keeps the table's.
+func (b *Builder) tableHeaderCell(text, color, align, background string) *tview.TableCell {
+	if color == "" {
+		color = "yellow"
+	}
+	cell := tview.NewTableCell(text).
+		SetTextColor(b.context.Colors.Parse(color)).
+		SetAlign(tview.AlignCenter).
+		SetSelectable(false)
+	if align != "" {
+		cell.SetAlign(template.ParseAlignment(align))
+	}
+	if background != "" {
+		cell.SetBackgroundColor(b.context.Colors.Parse(background))
+	}
+	return cell
+}
+
+// primitiveID returns the name template functions target prim by: its id, or its name if it has no id.
+func primitiveID(prim *config.Primitive) string {
+	if prim.ID != "" {
+		return prim.ID
+	}
+	return prim.Name
+}
+
 // buildPrimitive builds a primitive from a Primitive config (recursive)
 func (b *Builder) buildPrimitive(prim *config.Primitive, bc *BuildContext) (tview.Primitive, error) {
 	primName := prim.Type
@@ -427,6 +811,9 @@ func (b *Builder) buildPrimitive(prim *config.Primitive, bc *BuildContext) (tvie
 		return nil, bc.Errorf("%w", err)
 	}
 
+	// Register named primitives so template functions can target them
+	b.context.RegisterPrimitive(primitiveID(prim), primitive)
+
 	// Handle callbacks
 	if prim.OnSelected != "" {
 		callback, err := b.executor.ExecuteCallback(prim.OnSelected)
@@ -434,9 +821,19 @@ func (b *Builder) buildPrimitive(prim *config.Primitive, bc *BuildContext) (tvie
 			return nil, bc.Errorf("failed to execute callback: %w", err)
 		}
 		b.attacher.AttachCallback(primitive, callback)
+		if btn, ok := primitive.(*tview.Button); ok && prim.Shortcut != "" {
+			if _, _, _, err := keys.ParseKey(prim.Shortcut); err != nil {
+				return nil, bc.Errorf("invalid button shortcut %q: %w", prim.Shortcut, err)
+			}
+			bc.shortcuts = append(bc.shortcuts, pageShortcut{key: prim.Shortcut, button: btn, action: callback})
+		}
+	}
+	if err := b.attachFocusCallbacks(primitive, prim, bc); err != nil {
+		return nil, err
 	}
 
 	// Handle nested items for specific types
+	result := primitive // a wrapper around primitive for some properties (e.g. maxWidth)
 	switch v := primitive.(type) {
 	case *tview.Flex:
 		if err := b.populateFlexItems(v, prim, bc); err != nil {
@@ -470,17 +867,118 @@ func (b *Builder) buildPrimitive(prim *config.Primitive, bc *BuildContext) (tvie
 		if err := b.setupModal(v, prim, bc); err != nil {
 			return nil, err
 		}
+	case *Dialog:
+		if err := b.setupDialog(v, prim, bc); err != nil {
+			return nil, err
+		}
+	case *tview.DropDown:
+		if prim.MaxVisibleOptions > 0 {
+			result = newBoundedDropDown(v, prim.MaxVisibleOptions)
+		}
+	case *tview.TextView:
+		if prim.MaxWidth > 0 {
+			result = centerColumn(v, prim.MaxWidth)
+		}
+	case *tview.Box:
+		switch prim.Type {
+		case "progressBar":
+			if err := b.buildProgressBar(v, prim, bc); err != nil {
+				return nil, err
+			}
+		case "sparkline":
+			if err := b.buildSparkline(v, prim, bc); err != nil {
+				return nil, err
+			}
+		case "spinner":
+			if err := b.buildSpinner(v, prim, bc); err != nil {
+				return nil, err
+			}
+		case "box":
+			if prim.Drawer != "" {
+				draw, ok := b.drawers[prim.Drawer]
+				if !ok {
+					return nil, bc.Errorf("unknown drawer %q", prim.Drawer)
+				}
+				v.SetDrawFunc(draw)
+			}
+		}
+	}
+
+	// Last, so the label's draw func wraps those set above
+	if box, ok := primitive.(titleBox); ok && prim.TitleRight != "" {
+		attachTitleRight(box, prim.TitleRight, b.titleColor())
+	}
+
+	return result, nil
+}
+
+// titleColor returns the color of box titles: the theme's title color, or tview's default.
+func (b *Builder) titleColor() tcell.Color {
+	if theme := b.context.Theme(); theme != nil && theme.TitleColor != "" {
+		return b.context.Colors.Parse(theme.TitleColor)
 	}
+	return tview.Styles.TitleColor
+}
 
-	return primitive, nil
+// centerColumn places p in a horizontally centered column at most width cells wide, between
+// two equal spacers. The column shrinks to the available width on narrow screens.
+func centerColumn(p tview.Primitive, width int) *tview.Flex {
+	flex := tview.NewFlex().
+		AddItem(nil, 0, 1, false).
+		AddItem(p, width, 0, true).
+		AddItem(nil, 0, 1, false)
+	flex.SetDrawFunc(func(screen tcell.Screen, x, y, w, h int) (int, int, int, int) {
+		flex.ResizeItem(p, max(min(width, w), 1), 0)
+		return x, y, w, h
+	})
+	return flex
+}
+
+// attachFocusCallbacks wires onFocus and onBlur for primitives that support focus/blur funcs.
+// Before the callback runs, __focusedPrimitive (or __blurredPrimitive) is set to the primitive's id (or name).
+func (b *Builder) attachFocusCallbacks(primitive tview.Primitive, prim *config.Primitive, bc *BuildContext) error {
+	if prim.OnFocus == "" && prim.OnBlur == "" {
+		return nil
+	}
+	box, ok := primitive.(interface {
+		SetFocusFunc(func()) *tview.Box
+		SetBlurFunc(func()) *tview.Box
+	})
+	if !ok {
+		return nil
+	}
+	name := primitiveID(prim)
+	if prim.OnFocus != "" {
+		cb, err := b.executor.ExecuteCallback(prim.OnFocus)
+		if err != nil {
+			return bc.Errorf("failed to execute onFocus callback: %w", err)
+		}
+		box.SetFocusFunc(func() {
+			b.context.SetStateDirect("__focusedPrimitive", name)
+			cb()
+		})
+	}
+	if prim.OnBlur != "" {
+		cb, err := b.executor.ExecuteCallback(prim.OnBlur)
+		if err != nil {
+			return bc.Errorf("failed to execute onBlur callback: %w", err)
+		}
+		box.SetBlurFunc(func() {
+			b.context.SetStateDirect("__blurredPrimitive", name)
+			cb()
+		})
+	}
+	return nil
 }
 
 // populateFlexItems adds items to a flex container
 func (b *Builder) populateFlexItems(flex *tview.Flex, prim *config.Primitive, bc *BuildContext) error {
+	sizes := make([]flexItemSize, 0, len(prim.Items))
 	for i, item := range prim.Items {
 		isSpacer := item.Primitive == nil || item.Spacer
 		if isSpacer {
 			flex.AddItem(nil, item.FixedSize, item.Proportion, item.Focus)
+			sizes = append(sizes, flexItemSize{fixedSize: item.FixedSize, proportion: item.Proportion})
 			continue
 		}
 
@@ -492,18 +990,29 @@ func (b *Builder) populateFlexItems(flex *tview.Flex, prim *config.Primitive, bc
 		}
 
 		flex.AddItem(child, item.FixedSize, item.Proportion, item.Focus)
+		sizes = append(sizes, flexItemSize{item: child, fixedSize: item.FixedSize, proportion: item.Proportion, minSize: item.MinSize})
+	}
+	if prim.ResponsiveDirection || hasMinSize(prim.Items) {
+		layout := &flexLayout{row: prim.Direction == "row", items: sizes}
+		if prim.ResponsiveDirection {
+			layout.breakpoint = prim.BreakpointWidth
+		}
+		layout.attach(flex)
 	}
 	return nil
 }
 
 // populateListItems adds items to a list
 func (b *Builder) populateListItems(list *tview.List, prim *config.Primitive, bc *BuildContext) error {
-	for i, item := range prim.ListItems {
+	items := prim.ListItems
+	if prim.AutoShortcuts {
+		items = withAutoShortcuts(items)
+	}
+	var keyShortcuts []listKeyShortcut
+	var entries []listEntry
+	for i, item := range items {
 		bc.Push(fmt.Sprintf("listItem[%d]", i))
-		shortcut := rune(0)
-		if len(item.Shortcut) > 0 {
-			shortcut = rune(item.Shortcut[0])
-		}
+		shortcut, plain := listShortcutRune(item.Shortcut)
 
 		var callback func()
 		if item.OnSelected != "" {
@@ -516,19 +1025,125 @@ func (b *Builder) populateListItems(list *tview.List, prim *config.Primitive, bc
 		}
 
 		list.AddItem(item.MainText, item.SecondaryText, shortcut, callback)
+		if !plain {
+			if prim.Filterable {
+				// Key shortcuts select by index, which filtering changes.
+				bc.Pop()
+				return bc.Errorf("shortcut %q: filterable lists support only plain-key shortcuts", item.Shortcut)
+			}
+			keyShortcuts = append(keyShortcuts, listKeyShortcut{index: i, key: item.Shortcut, callback: callback})
+		}
+		entries = append(entries, listEntry{mainText: item.MainText, secondaryText: item.SecondaryText, shortcut: shortcut, selected: callback})
 		bc.Pop()
 	}
+	bindListKeyShortcuts(list, keyShortcuts)
+	var filtered *filterableList
+	if prim.Filterable {
+		filtered = newFilterableList(list, entries)
+		b.context.SetListAppender(list, filtered.appendEntry)
+		if prim.FilterInput != "" {
+			bc.filters = append(bc.filters, listFilter{list: filtered, input: prim.FilterInput})
+		} else {
+			bindTypeToFilter(filtered)
+		}
+	}
+	if err := b.bindListItemsText(list, items, filtered, prim.ShowShortcutHint, bc); err != nil {
+		return err
+	}
+	if prim.CurrentItem != 0 {
+		list.SetCurrentItem(prim.CurrentItem)
+	}
+	if prim.Offset > 0 {
+		list.SetOffset(prim.Offset, 0)
+	}
 	return nil
 }
 
+// autoShortcutKeys are the shortcuts autoShortcuts assigns, in order.
+const autoShortcutKeys = "123456789abcdefghijklmnopqrstuvwxyz"
+
+// withAutoShortcuts returns a copy of items in which each item with an onSelected action but no
+// shortcut gets the next of autoShortcutKeys that no item uses as its shortcut. Items without an
+// action cannot be selected, so they get none; nor do items past the last key. The copy keeps
+// cached page configs unchanged.
+func withAutoShortcuts(items []config.ListItem) []config.ListItem {
+	used := make(map[string]bool)
+	for _, item := range items {
+		used[strings.ToLower(item.Shortcut)] = true
+	}
+	free := []rune(autoShortcutKeys)
+	result := make([]config.ListItem, len(items))
+	for i, item := range items {
+		if item.Shortcut == "" && item.OnSelected != "" {
+			for len(free) > 0 && used[string(free[0])] {
+				free = free[1:]
+			}
+			if len(free) > 0 {
+				item.Shortcut = string(free[0])
+				free = free[1:]
+			}
+		}
+		result[i] = item
+	}
+	return result
+}
+
+// listKeyShortcut is a list item shortcut that tview's rune shortcuts cannot express (e.g. "F1", "Ctrl+N").
+type listKeyShortcut struct {
+	index    int
+	key      string
+	callback func()
+}
+
+// listShortcutRune returns the rune to pass to List.AddItem for a shortcut string. Plain keys,
+// including multibyte runes such as "ñ", return (rune, true). Special or modified keys return
+// (0, false) so they can be bound with bindListKeyShortcuts. Empty shortcuts return (0, true).
+func listShortcutRune(shortcut string) (rune, bool) {
+	if shortcut == "" {
+		return 0, true
+	}
+	key, mod, ch, err := keys.ParseKey(shortcut)
+	if err != nil {
+		// Not a key string: keep the legacy behavior of using the first character.
+		return []rune(shortcut)[0], true
+	}
+	if key == tcell.KeyRune && mod == 0 {
+		return ch, true
+	}
+	return 0, false
+}
+
+// bindListKeyShortcuts handles special or modified item shortcuts while the list has focus:
+// a matching key selects the item and runs its callback, like tview's rune shortcuts.
+func bindListKeyShortcuts(list *tview.List, shortcuts []listKeyShortcut) {
+	if len(shortcuts) == 0 {
+		return
+	}
+	list.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		for _, sc := range shortcuts {
+			if template.MatchesKeyBinding(event, config.KeyBinding{Key: sc.key}) {
+				list.SetCurrentItem(sc.index)
+				if sc.callback != nil {
+					sc.callback()
+				}
+				return nil
+			}
+		}
+		return event
+	})
+}
+
 // populateFormItems adds items to a form (delegates to shared logic)
 func (b *Builder) populateFormItems(form *tview.Form, prim *config.Primitive, bc *BuildContext) error {
-	_, err := b.addFormItems(form, prim.FormItems, bc)
+	_, err := b.addFormItems(form, prim.FormItems, primitiveID(prim), prim.MarkRequired, bc)
 	if err != nil {
 		return err
 	}
+	if prim.ButtonsAlign != "" {
+		form.SetButtonsAlign(template.ParseAlignment(prim.ButtonsAlign))
+	}
 	// Setup form callbacks (cancel and submit)
-	return b.setupFormCallbacks(form, prim.OnCancel, prim.OnSubmit, prim.Name, bc)
+	return b.setupFormCallbacks(form, prim.OnCancel, prim.OnSubmit, primitiveID(prim), bc)
 }
 
 // populateTableData populates table with data from primitive config
@@ -544,33 +1159,60 @@ func (b *Builder) populateTableData(table *tview.Table, prim *config.Primitive,
 		table.SetBorders(true)
 	}
 	
-	if len(prim.Columns) > 0 {
+	showHeaders := len(prim.Columns) > 0 && (prim.ShowHeaders == nil || *prim.ShowHeaders)
+	if showHeaders {
 		// Add headers
 		for col, header := range prim.Columns {
-			cell := tview.NewTableCell(header).
-				SetTextColor(b.context.Colors.Parse("yellow")).
-				SetAlign(tview.AlignCenter).
-				SetSelectable(false)
-			table.SetCell(0, col, cell)
+			table.SetCell(0, col, b.tableHeaderCell(header, prim.HeaderColor, prim.HeaderAlign, prim.HeaderBackgroundColor))
 		}
 	}
 
 	if len(prim.Rows) > 0 {
 		// Add rows
 		startRow := 0
-		if len(prim.Columns) > 0 {
+		if showHeaders {
 			startRow = 1
 		}
 
+		tableRow := startRow
 		for row, rowData := range prim.Rows {
+			height := 1
 			for col, cellData := range rowData {
 				// Cycle through colors for each column
 				color := colors[col%len(colors)]
+				// Only literal text is split; bound text keeps to its row
+				if prim.MultilineCells && !strings.Contains(cellData, "{{") {
+					lines := cellLines(cellData)
+					for i, line := range lines {
+						cell := tview.NewTableCell(line).
+							SetTextColor(b.context.Colors.Parse(color)).
+							SetAlign(tview.AlignCenter).
+							SetSelectable(i == 0)
+						if len(lines) > 1 {
+							cell.SetReference(cellData)
+						}
+						table.SetCell(tableRow+i, col, cell)
+					}
+					height = max(height, len(lines))
+					continue
+				}
 				cell := tview.NewTableCell(cellData).
 					SetTextColor(b.context.Colors.Parse(color)).
 					SetAlign(tview.AlignCenter)
-				table.SetCell(startRow+row, col, cell)
+				table.SetCell(tableRow, col, cell)
+				if err := b.mapper.applyBoundText(cellData, func(s string) { cell.SetText(s) }); err != nil {
+					return bc.Errorf("rows[%d][%d]: %w", row, col, err)
+				}
+			}
+			// Continuation rows are filled out so selection skips them in every column
+			for i := 1; i < height; i++ {
+				for col := range rowData {
+					if table.GetCell(tableRow+i, col).Text == "" {
+						table.SetCell(tableRow+i, col, tview.NewTableCell("").SetSelectable(false))
+					}
+				}
 			}
+			tableRow += height
 		}
 	}
 
@@ -584,6 +1226,10 @@ func (b *Builder) populateTableData(table *tview.Table, prim *config.Primitive,
 			cellText := ""
 			if cell := table.GetCell(row, column); cell != nil {
 				cellText = cell.Text
+				// A multi-line cell reports its whole text, not just its first line
+				if full, ok := cell.GetReference().(string); ok {
+					cellText = full
+				}
 			}
 			b.context.SetStateDirect("__selectedCellText", cellText)
 			b.context.SetStateDirect("__selectedRow", row)
@@ -609,10 +1255,14 @@ func (b *Builder) populateTreeView(tree *tview.TreeView, prim *config.Primitive,
 	// Build a map of node name to tview.TreeNode and selectable mode
 	tviewNodeMap := make(map[string]*tview.TreeNode)
 	selectableModeMap := make(map[string]string) // node name -> selectable mode ("true", "auto", "false")
+	dataMap := make(map[string]string)           // node name -> data payload
 
 	// Create all nodes first
 	for _, node := range prim.Nodes {
 		tviewNode := tview.NewTreeNode(node.Text)
+		if err := b.mapper.applyBoundText(node.Text, func(s string) { tviewNode.SetText(s) }); err != nil {
+			return bc.Errorf("node %q text: %w", node.Name, err)
+		}
 		if node.Color != "" {
 			tviewNode.SetColor(b.context.Colors.Parse(node.Color))
 		}
@@ -627,6 +1277,16 @@ func (b *Builder) populateTreeView(tree *tview.TreeView, prim *config.Primitive,
 		// Store node name in Reference so we can look it up later
 		tviewNode.SetReference(node.Name)
 		tviewNodeMap[node.Name] = tviewNode
+		dataMap[node.Name] = node.Data
+	}
+
+	// Build list of available node names for error messages
+	availableNodes := func() string {
+		names := make([]string, 0, len(prim.Nodes))
+		for _, node := range prim.Nodes {
+			names = append(names, fmt.Sprintf("%q", node.Name))
+		}
+		return strings.Join(names, ", ")
 	}
 
 	// Now connect children with validation
@@ -635,42 +1295,32 @@ func (b *Builder) populateTreeView(tree *tview.TreeView, prim *config.Primitive,
 		for _, childName := range node.Children {
 			child, ok := tviewNodeMap[childName]
 			if !ok {
-				// Build list of available node names for error message
-				availableNodes := make([]string, 0, len(tviewNodeMap))
-				for name := range tviewNodeMap {
-					availableNodes = append(availableNodes, fmt.Sprintf("%q", name))
-				}
 				return bc.Errorf("node %q references unknown child %q (available nodes: %s)",
-					node.Name, childName, strings.Join(availableNodes, ", "))
+					node.Name, childName, availableNodes())
 			}
 			parent.AddChild(child)
 		}
 	}
 
-	// Set root and current node
-	var root *tview.TreeNode
+	// Set root and current node; default to the first node if root not specified
+	root := tviewNodeMap[prim.Nodes[0].Name]
 	if prim.RootNode != "" {
-		root = tviewNodeMap[prim.RootNode]
-	}
-	if root == nil && len(prim.Nodes) > 0 {
-		// Default to first node if root not specified
-		root = tviewNodeMap[prim.Nodes[0].Name]
+		var ok bool
+		if root, ok = tviewNodeMap[prim.RootNode]; !ok {
+			return bc.Errorf("rootNode %q is not a declared node (available nodes: %s)", prim.RootNode, availableNodes())
+		}
 	}
-
-	if root != nil {
-		tree.SetRoot(root)
-
-		// Set current node
-		if prim.CurrentNode != "" {
-			if current, ok := tviewNodeMap[prim.CurrentNode]; ok {
-				tree.SetCurrentNode(current)
-			} else {
-				tree.SetCurrentNode(root)
-			}
-		} else {
-			tree.SetCurrentNode(root)
+	tree.SetRoot(root)
+
+	// Set current node
+	current := root
+	if prim.CurrentNode != "" {
+		var ok bool
+		if current, ok = tviewNodeMap[prim.CurrentNode]; !ok {
+			return bc.Errorf("currentNode %q is not a declared node (available nodes: %s)", prim.CurrentNode, availableNodes())
 		}
 	}
+	tree.SetCurrentNode(current)
 
 	// Handle node selection
 	tree.SetSelectedFunc(func(node *tview.TreeNode) {
@@ -705,6 +1355,8 @@ func (b *Builder) populateTreeView(tree *tview.TreeView, prim *config.Primitive,
 			// Always run onNodeSelected if set, and toggle expansion for parent nodes
 			if prim.OnNodeSelected != "" {
 				b.context.SetStateDirect("__selectedNodeText", node.GetText())
+				b.context.SetStateDirect("__selectedNodeName", nodeName)
+				b.context.SetStateDirect("__selectedNodeData", dataMap[nodeName])
 				if cb, err := b.executor.ExecuteCallback(prim.OnNodeSelected); err == nil {
 					cb()
 				}
@@ -722,12 +1374,12 @@ func (b *Builder) populateTreeView(tree *tview.TreeView, prim *config.Primitive,
 
 // populateGridItems configures a grid and adds items to it
 func (b *Builder) populateGridItems(grid *tview.Grid, prim *config.Primitive, bc *BuildContext) error {
-	// Set rows (0 = flexible)
+	// Set rows (positive = fixed, 0 = flexible, negative = proportional; passed to tview as-is)
 	if len(prim.GridRows) > 0 {
 		grid.SetRows(prim.GridRows...)
 	}
 
-	// Set columns (0 = flexible)
+	// Set columns (same sizing rules as rows)
 	if len(prim.GridColumns) > 0 {
 		grid.SetColumns(prim.GridColumns...)
 	}
@@ -738,6 +1390,8 @@ func (b *Builder) populateGridItems(grid *tview.Grid, prim *config.Primitive, bc
 	}
 
 	// Add items
+	var placements []gridPlacement
+	hides := false
 	for _, item := range prim.GridItems {
 		if item.Primitive == nil {
 			continue
@@ -761,6 +1415,15 @@ func (b *Builder) populateGridItems(grid *tview.Grid, prim *config.Primitive, bc
 		}
 
 		grid.AddItem(child, item.Row, item.Column, rowSpan, colSpan, item.MinHeight, item.MinWidth, item.Focus)
+		placements = append(placements, gridPlacement{
+			item: child, row: item.Row, column: item.Column, rowSpan: rowSpan, colSpan: colSpan,
+			minHeight: item.MinHeight, minWidth: item.MinWidth, focus: item.Focus,
+			hideBelowWidth: item.HideBelowWidth, hideBelowHeight: item.HideBelowHeight,
+		})
+		hides = hides || item.HideBelowWidth > 0 || item.HideBelowHeight > 0
+	}
+	if hides {
+		newGridVisibility(prim.GridRows, prim.GridColumns, placements).attach(grid)
 	}
 
 	return nil
@@ -775,15 +1438,23 @@ func (b *Builder) populateNestedPages(pages *tview.Pages, prim *config.Primitive
 	for i, pageRef := range prim.Pages {
 		bc.Push(fmt.Sprintf("page[%d]:%s", i, pageRef.Name))
 
-		// Load page config from referenced file
-		pageCfg, err := b.loader.LoadPage(pageRef.Ref)
-		if err != nil {
-			bc.Pop()
-			return bc.Errorf("failed to load nested page %s: %w", pageRef.Name, err)
+		// Load page config from referenced file, relative to this page's file when possible.
+		// An inline page resolves its own nested refs as if it lived in this page's file.
+		pageCfg, ref := pageRef.Page, filepath.Join(bc.dir, pageRef.Name)
+		if pageCfg == nil {
+			ref = pageRef.Ref
+			if r, ok := b.loader.(refResolver); ok {
+				ref = r.ResolveRef(bc.dir, ref)
+			}
+			var err error
+			if pageCfg, err = b.loader.LoadPage(ref); err != nil {
+				bc.Pop()
+				return bc.Errorf("failed to load nested page %s: %w", pageRef.Name, err)
+			}
 		}
 
 		// Build the page primitive
-		pagePrim, err := b.BuildFromConfig(pageCfg)
+		pagePrim, err := b.BuildFromConfigRef(pageCfg, ref)
 		if err != nil {
 			bc.Pop()
 			return err
@@ -808,6 +1479,20 @@ func (b *Builder) setupModal(modal *tview.Modal, prim *config.Primitive, bc *Bui
 		modal.SetText(result)
 	}
 
+	// Colors; unset fields keep tview's defaults
+	if prim.BackgroundColor != "" {
+		modal.SetBackgroundColor(b.context.Colors.Parse(prim.BackgroundColor))
+	}
+	if prim.TextColor != "" {
+		modal.SetTextColor(b.context.Colors.Parse(prim.TextColor))
+	}
+	if prim.ButtonBackgroundColor != "" {
+		modal.SetButtonBackgroundColor(b.context.Colors.Parse(prim.ButtonBackgroundColor))
+	}
+	if prim.ButtonTextColor != "" {
+		modal.SetButtonTextColor(b.context.Colors.Parse(prim.ButtonTextColor))
+	}
+
 	// Configure buttons
 	if len(prim.Buttons) > 0 {
 		buttonLabels := make([]string, len(prim.Buttons))
@@ -832,6 +1517,56 @@ func (b *Builder) setupModal(modal *tview.Modal, prim *config.Primitive, bc *Bui
 	return nil
 }
 
+// setupDialog configures a dialog's text, width, colors, and buttons from primitive config
+func (b *Builder) setupDialog(dialog *Dialog, prim *config.Primitive, bc *BuildContext) error {
+	if prim.Text != "" {
+		result, err := b.executor.EvaluateToString(prim.Text)
+		if err != nil {
+			return bc.Errorf("failed to evaluate dialog text: %w", err)
+		}
+		dialog.SetText(result)
+	}
+	dialog.SetWidth(prim.Width)
+	if prim.BackgroundColor != "" {
+		dialog.SetBackgroundColor(b.context.Colors.Parse(prim.BackgroundColor))
+	}
+	if prim.TextColor != "" {
+		dialog.SetTextColor(b.context.Colors.Parse(prim.TextColor))
+	}
+
+	labels := make([]string, len(prim.Buttons))
+	callbacks := make([]func(), len(prim.Buttons))
+	for i, btn := range prim.Buttons {
+		labels[i] = btn.Label
+		if btn.OnSelected != "" {
+			cb, err := b.executor.ExecuteCallback(btn.OnSelected)
+			if err != nil {
+				return bc.Errorf("failed to execute callback for dialog button %q: %w", btn.Label, err)
+			}
+			callbacks[i] = cb
+		}
+	}
+	dialog.AddButtons(labels)
+	dialog.SetDoneFunc(func(buttonIndex int, buttonLabel string) {
+		if buttonIndex >= 0 && callbacks[buttonIndex] != nil {
+			callbacks[buttonIndex]()
+		}
+	})
+	return nil
+}
+
+// buildDialog configures a dialog from page config (for page-level type: dialog)
+func (b *Builder) buildDialog(dialog *Dialog, cfg *config.PageConfig, bc *BuildContext) (tview.Primitive, error) {
+	prim := &config.Primitive{
+		Text:            cfg.Text,
+		Buttons:         cfg.Buttons,
+		BackgroundColor: cfg.BackgroundColor,
+		TextColor:       cfg.TextColor,
+		Width:           cfg.Width,
+	}
+	return dialog, b.setupDialog(dialog, prim, bc)
+}
+
 // buildTreeView populates a tree view from page config (for page-level type: treeView)
 func (b *Builder) buildTreeView(tree *tview.TreeView, cfg *config.PageConfig, bc *BuildContext) (tview.Primitive, error) {
 	prim := &config.Primitive{
@@ -846,8 +1581,12 @@ func (b *Builder) buildTreeView(tree *tview.TreeView, cfg *config.PageConfig, bc
 // buildModal populates a modal from page config (for page-level type: modal)
 func (b *Builder) buildModal(modal *tview.Modal, cfg *config.PageConfig, bc *BuildContext) (tview.Primitive, error) {
 	prim := &config.Primitive{
-		Text:    cfg.Text,
-		Buttons: cfg.Buttons,
+		Text:                  cfg.Text,
+		Buttons:               cfg.Buttons,
+		BackgroundColor:       cfg.BackgroundColor,
+		TextColor:             cfg.TextColor,
+		ButtonBackgroundColor: cfg.ButtonBackgroundColor,
+		ButtonTextColor:       cfg.ButtonTextColor,
 	}
 	return modal, b.setupModal(modal, prim, bc)
 }
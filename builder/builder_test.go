@@ -1,10 +1,16 @@
 package builder
 
 import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/cassdeckard/tviewyaml/config"
 	"github.com/cassdeckard/tviewyaml/template"
+	"github.com/gdamore/tcell/v2"
 	"github.com/rivo/tview"
 )
 
@@ -110,3 +116,1067 @@ func TestBuildFlex_SpacerFlag(t *testing.T) {
 		t.Errorf("GetItem(1) = %v, want nil (spacer)", got)
 	}
 }
+
+func TestBuildFlex_MinSize(t *testing.T) {
+	ctx := template.NewContext(tview.NewApplication(), tview.NewPages())
+	b := NewBuilder(ctx, template.NewFunctionRegistry())
+	result, err := b.BuildFromConfig(&config.PageConfig{Type: "flex", Items: []config.FlexItem{
+		{Primitive: &config.Primitive{Type: "list", Name: "sidebar", ListItems: []config.ListItem{{MainText: "Home"}}}, Proportion: 1, MinSize: 20},
+		{Primitive: &config.Primitive{Type: "textView", Name: "content"}, Proportion: 4},
+	}})
+	if err != nil {
+		t.Fatalf("BuildFromConfig: %v", err)
+	}
+	sidebar, _ := ctx.GetPrimitive("sidebar")
+	content, _ := ctx.GetPrimitive("content")
+
+	screen := tcell.NewSimulationScreen("")
+	if err := screen.Init(); err != nil {
+		t.Fatal(err)
+	}
+	defer screen.Fini()
+
+	// Widths are checked from large to small and back, so a pinned item must be released again.
+	for _, tt := range []struct{ width, sidebar, content int }{
+		{200, 40, 160},
+		{50, 20, 30},
+		{30, 20, 10},
+		{100, 20, 80},
+		{150, 30, 120},
+	} {
+		screen.SetSize(tt.width, 5)
+		result.SetRect(0, 0, tt.width, 5)
+		result.Draw(screen)
+		_, _, sw, _ := sidebar.GetRect()
+		_, _, cw, _ := content.GetRect()
+		if sw != tt.sidebar || cw != tt.content {
+			t.Errorf("width %d: sidebar/content = %d/%d, want %d/%d", tt.width, sw, cw, tt.sidebar, tt.content)
+		}
+	}
+}
+
+func TestBuildList_Shortcuts(t *testing.T) {
+	app := tview.NewApplication()
+	pages := tview.NewPages()
+	ctx := template.NewContext(app, pages)
+	registry := template.NewFunctionRegistry()
+	maxOne := 1
+	if err := registry.Register("mark", 1, &maxOne, nil, func(ctx *template.Context, v string) {
+		ctx.SetStateDirect("selected", v)
+	}); err != nil {
+		t.Fatalf("Register: %v", err)
+	}
+	b := NewBuilder(ctx, registry)
+
+	pageConfig := &config.PageConfig{
+		Type: "list",
+		ListItems: []config.ListItem{
+			{MainText: "First", Shortcut: "a", OnSelected: `{{ mark "a" }}`},
+			{MainText: "Spanish", Shortcut: "ñ", OnSelected: `{{ mark "ñ" }}`},
+			{MainText: "Function", Shortcut: "F2", OnSelected: `{{ mark "F2" }}`},
+		},
+	}
+	result, err := b.BuildFromConfig(pageConfig)
+	if err != nil {
+		t.Fatalf("BuildFromConfig: %v", err)
+	}
+	list := result.(*tview.List)
+
+	tests := []struct {
+		name      string
+		event     *tcell.EventKey
+		wantState string
+		wantIndex int
+	}{
+		{"multibyte rune", tcell.NewEventKey(tcell.KeyRune, 'ñ', tcell.ModNone), "ñ", 1},
+		{"function key", tcell.NewEventKey(tcell.KeyF2, 0, tcell.ModNone), "F2", 2},
+		{"ascii rune", tcell.NewEventKey(tcell.KeyRune, 'a', tcell.ModNone), "a", 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			event := tt.event
+			if capture := list.GetInputCapture(); capture != nil {
+				event = capture(event)
+			}
+			if event != nil {
+				list.InputHandler()(event, func(tview.Primitive) {})
+			}
+			if got, _ := ctx.GetState("selected"); got != tt.wantState {
+				t.Errorf("selected state = %v, want %q", got, tt.wantState)
+			}
+			if got := list.GetCurrentItem(); got != tt.wantIndex {
+				t.Errorf("GetCurrentItem() = %d, want %d", got, tt.wantIndex)
+			}
+		})
+	}
+}
+
+func TestBuildForm_InputFieldWidth(t *testing.T) {
+	ctx := template.NewContext(tview.NewApplication(), tview.NewPages())
+	b := NewBuilder(ctx, template.NewFunctionRegistry())
+
+	pageConfig := &config.PageConfig{
+		Type: "form",
+		FormItems: []config.FormItem{
+			{Type: "inputfield", Label: "Search"},
+			{Type: "inputfield", Label: "Code", FieldWidth: 8},
+			{Type: "inputfield", Label: "Secret", PasswordMode: true},
+		},
+	}
+	result, err := b.BuildFromConfig(pageConfig)
+	if err != nil {
+		t.Fatalf("BuildFromConfig: %v", err)
+	}
+	form := result.(*tview.Form)
+	for i, want := range []int{0, 8, 0} {
+		if got := form.GetFormItem(i).GetFieldWidth(); got != want {
+			t.Errorf("item %d GetFieldWidth() = %d, want %d", i, got, want)
+		}
+	}
+}
+
+func TestBuildPrimitive_ListInitialState(t *testing.T) {
+	ctx := template.NewContext(tview.NewApplication(), tview.NewPages())
+	b := NewBuilder(ctx, template.NewFunctionRegistry())
+
+	items := []config.ListItem{{MainText: "One"}, {MainText: "Two"}, {MainText: "Three"}, {MainText: "Four"}}
+	tests := []struct {
+		name       string
+		prim       config.Primitive
+		wantItem   int
+		wantOffset int
+	}{
+		{"default", config.Primitive{Type: "list", ListItems: items}, 0, 0},
+		{"current item", config.Primitive{Type: "list", ListItems: items, CurrentItem: 2}, 2, 0},
+		{"current item from end", config.Primitive{Type: "list", ListItems: items, CurrentItem: -1}, 3, 0},
+		{"offset", config.Primitive{Type: "list", ListItems: items, CurrentItem: 3, Offset: 2, SelectedFocusOnly: true}, 3, 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pageConfig := &config.PageConfig{
+				Type:  "flex",
+				Items: []config.FlexItem{{Primitive: &tt.prim, Proportion: 1}},
+			}
+			result, err := b.BuildFromConfig(pageConfig)
+			if err != nil {
+				t.Fatalf("BuildFromConfig: %v", err)
+			}
+			list := result.(*tview.Flex).GetItem(0).(*tview.List)
+			if got := list.GetCurrentItem(); got != tt.wantItem {
+				t.Errorf("GetCurrentItem() = %d, want %d", got, tt.wantItem)
+			}
+			if got, _ := list.GetOffset(); got != tt.wantOffset {
+				t.Errorf("GetOffset() = %d, want %d", got, tt.wantOffset)
+			}
+		})
+	}
+}
+
+func TestBuildList_FilterAsYouType(t *testing.T) {
+	ctx := template.NewContext(tview.NewApplication(), tview.NewPages())
+	b := NewBuilder(ctx, template.NewFunctionRegistry())
+
+	items := []config.ListItem{
+		{MainText: "Apple", SecondaryText: "fruit"},
+		{MainText: "Carrot", SecondaryText: "vegetable"},
+		{MainText: "Pineapple", SecondaryText: "fruit"},
+	}
+	itemTexts := func(list *tview.List) []string {
+		var texts []string
+		for i := 0; i < list.GetItemCount(); i++ {
+			main, _ := list.GetItemText(i)
+			texts = append(texts, main)
+		}
+		return texts
+	}
+	typeInto := func(p tview.Primitive, text string) {
+		for _, r := range text {
+			event := tcell.NewEventKey(tcell.KeyRune, r, tcell.ModNone)
+			if list, ok := p.(*tview.List); ok {
+				event = list.GetInputCapture()(event)
+			}
+			if event != nil {
+				p.InputHandler()(event, func(tview.Primitive) {})
+			}
+		}
+	}
+
+	t.Run("filterInput", func(t *testing.T) {
+		pageConfig := &config.PageConfig{
+			Type:      "flex",
+			Direction: "row",
+			Items: []config.FlexItem{
+				{Primitive: &config.Primitive{Type: "inputField", Name: "search"}, FixedSize: 1},
+				{Primitive: &config.Primitive{Type: "list", Name: "produce", ListItems: items, Filterable: true, FilterInput: "search"}, Proportion: 1},
+			},
+		}
+		result, err := b.BuildFromConfig(pageConfig)
+		if err != nil {
+			t.Fatalf("BuildFromConfig: %v", err)
+		}
+		flex := result.(*tview.Flex)
+		input, list := flex.GetItem(0).(*tview.InputField), flex.GetItem(1).(*tview.List)
+		// The input's text area places its cursor by layout, so it must be drawn before typing.
+		screen := tcell.NewSimulationScreen("UTF-8")
+		if err := screen.Init(); err != nil {
+			t.Fatalf("SimulationScreen Init: %v", err)
+		}
+		defer screen.Fini()
+		input.SetRect(0, 0, 40, 1)
+		input.Draw(screen)
+
+		backspace := func(n int) {
+			for i := 0; i < n; i++ {
+				input.InputHandler()(tcell.NewEventKey(tcell.KeyBackspace2, 0, tcell.ModNone), func(tview.Primitive) {})
+			}
+		}
+		typeInto(input, "APP")
+		if got := strings.Join(itemTexts(list), ","); got != "Apple,Pineapple" {
+			t.Errorf("items after typing APP = %s, want Apple,Pineapple", got)
+		}
+		backspace(3)
+		if got := list.GetItemCount(); got != len(items) {
+			t.Errorf("GetItemCount() after clearing = %d, want %d", got, len(items))
+		}
+		typeInto(input, "veg")
+		if got := strings.Join(itemTexts(list), ","); got != "Carrot" {
+			t.Errorf("items matching secondary text = %s, want Carrot", got)
+		}
+	})
+
+	t.Run("type into list", func(t *testing.T) {
+		prim := &config.Primitive{Type: "list", ListItems: items, Filterable: true}
+		result, err := b.BuildFromConfig(&config.PageConfig{Type: "flex", Items: []config.FlexItem{{Primitive: prim, Proportion: 1}}})
+		if err != nil {
+			t.Fatalf("BuildFromConfig: %v", err)
+		}
+		list := result.(*tview.Flex).GetItem(0).(*tview.List)
+
+		typeInto(list, "pine")
+		if got := strings.Join(itemTexts(list), ","); got != "Pineapple" {
+			t.Errorf("items after typing pine = %s, want Pineapple", got)
+		}
+		list.GetInputCapture()(tcell.NewEventKey(tcell.KeyEscape, 0, tcell.ModNone))
+		if got := list.GetItemCount(); got != len(items) {
+			t.Errorf("GetItemCount() after Escape = %d, want %d", got, len(items))
+		}
+	})
+
+	t.Run("appended items", func(t *testing.T) {
+		// No app: AppendListItem's queued update runs right away
+		ctx := template.NewContext(nil, tview.NewPages())
+		b := NewBuilder(ctx, template.NewFunctionRegistry())
+		prim := &config.Primitive{Type: "list", Name: "produce", ListItems: items, Filterable: true}
+		result, err := b.BuildFromConfig(&config.PageConfig{Type: "flex", Items: []config.FlexItem{{Primitive: prim, Proportion: 1}}})
+		if err != nil {
+			t.Fatalf("BuildFromConfig: %v", err)
+		}
+		list := result.(*tview.Flex).GetItem(0).(*tview.List)
+		escape := func() { list.GetInputCapture()(tcell.NewEventKey(tcell.KeyEscape, 0, tcell.ModNone)) }
+
+		if err := ctx.AppendListItem("produce", "Papaya", "fruit", "", ""); err != nil {
+			t.Fatalf("AppendListItem: %v", err)
+		}
+		typeInto(list, "carrot")
+		escape()
+		if got := strings.Join(itemTexts(list), ","); got != "Apple,Carrot,Pineapple,Papaya" {
+			t.Errorf("items after filtering and clearing = %s, want the appended Papaya kept", got)
+		}
+
+		// An item appended while filtered shows only if it matches, and returns when cleared
+		typeInto(list, "apple")
+		if err := ctx.AppendListItem("produce", "Leek", "vegetable", "", ""); err != nil {
+			t.Fatalf("AppendListItem: %v", err)
+		}
+		if got := strings.Join(itemTexts(list), ","); got != "Apple,Pineapple" {
+			t.Errorf("items after appending a non-matching item = %s, want Apple,Pineapple", got)
+		}
+		escape()
+		if got := strings.Join(itemTexts(list), ","); got != "Apple,Carrot,Pineapple,Papaya,Leek" {
+			t.Errorf("items after clearing = %s, want every item", got)
+		}
+	})
+
+	t.Run("missing filterInput", func(t *testing.T) {
+		prim := &config.Primitive{Type: "list", ListItems: items, Filterable: true, FilterInput: "nowhere"}
+		_, err := b.BuildFromConfig(&config.PageConfig{Type: "flex", Items: []config.FlexItem{{Primitive: prim, Proportion: 1}}})
+		if err == nil || !strings.Contains(err.Error(), `no primitive named "nowhere"`) {
+			t.Errorf("BuildFromConfig() error = %v, want missing filterInput error", err)
+		}
+	})
+}
+
+func TestBuildTreeView_SelectedNodePayload(t *testing.T) {
+	ctx := template.NewContext(tview.NewApplication(), tview.NewPages())
+	registry := template.NewFunctionRegistry()
+	var got [3]interface{}
+	zero := 0
+	if err := registry.Register("open", 0, &zero, nil, func(ctx *template.Context) {
+		got[0], _ = ctx.GetState("__selectedNodeName")
+		got[1], _ = ctx.GetState("__selectedNodeData")
+		got[2], _ = ctx.GetState("__selectedNodeText")
+	}); err != nil {
+		t.Fatalf("Register: %v", err)
+	}
+	b := NewBuilder(ctx, registry)
+
+	prim := &config.Primitive{
+		Type:           "treeView",
+		OnNodeSelected: `{{ open }}`,
+		Nodes: []config.TreeNode{
+			{Name: "root", Text: "Config", Children: []string{"db"}},
+			{Name: "db", Text: "Database settings", Selectable: "true", Data: "config/db.yaml"},
+		},
+		CurrentNode: "db",
+	}
+	result, err := b.BuildFromConfig(&config.PageConfig{Type: "flex", Items: []config.FlexItem{{Primitive: prim, Proportion: 1}}})
+	if err != nil {
+		t.Fatalf("BuildFromConfig: %v", err)
+	}
+	tree := result.(*tview.Flex).GetItem(0).(*tview.TreeView)
+	tree.InputHandler()(tcell.NewEventKey(tcell.KeyEnter, 0, tcell.ModNone), func(tview.Primitive) {})
+
+	want := [3]interface{}{"db", "config/db.yaml", "Database settings"}
+	if got != want {
+		t.Errorf("onNodeSelected state (name, data, text) = %v, want %v", got, want)
+	}
+}
+
+func TestBuildPrimitive_BoundItemText(t *testing.T) {
+	ctx := template.NewContext(tview.NewApplication(), tview.NewPages())
+	b := NewBuilder(ctx, template.NewFunctionRegistry())
+	ctx.SetStateDirect("unread", 3)
+
+	pageConfig := &config.PageConfig{
+		Type: "flex",
+		Items: []config.FlexItem{
+			{Primitive: &config.Primitive{Type: "list", ListItems: []config.ListItem{
+				{MainText: "Drafts"},
+				{MainText: "Inbox ({{ bindState unread }})", SecondaryText: "{{ bindState unread }} new"},
+			}}, Proportion: 1},
+			{Primitive: &config.Primitive{Type: "list", Filterable: true, ListItems: []config.ListItem{
+				{MainText: "Archive"},
+				{MainText: "Inbox ({{ bindState unread }})"},
+			}}, Proportion: 1},
+			{Primitive: &config.Primitive{Type: "table", Rows: [][]string{{"Unread", "{{ bindState unread }}"}}}, Proportion: 1},
+			{Primitive: &config.Primitive{Type: "treeView", Nodes: []config.TreeNode{
+				{Name: "inbox", Text: "Inbox [{{ bindState unread }}]"},
+			}}, Proportion: 1},
+		},
+	}
+	result, err := b.BuildFromConfig(pageConfig)
+	if err != nil {
+		t.Fatalf("BuildFromConfig: %v", err)
+	}
+	flex := result.(*tview.Flex)
+	list := flex.GetItem(0).(*tview.List)
+	filtered := flex.GetItem(1).(*tview.List)
+	table := flex.GetItem(2).(*tview.Table)
+	tree := flex.GetItem(3).(*tview.TreeView)
+
+	check := func(wantMain, wantSecondary, wantCell, wantNode string) {
+		t.Helper()
+		if main, secondary := list.GetItemText(1); main != wantMain || secondary != wantSecondary {
+			t.Errorf("list item = (%q, %q), want (%q, %q)", main, secondary, wantMain, wantSecondary)
+		}
+		if main, _ := list.GetItemText(0); main != "Drafts" {
+			t.Errorf("unbound list item = %q, want Drafts", main)
+		}
+		if main, _ := filtered.GetItemText(1); main != wantMain {
+			t.Errorf("filterable list item = %q, want %q", main, wantMain)
+		}
+		if got := table.GetCell(0, 1).Text; got != wantCell {
+			t.Errorf("table cell = %q, want %q", got, wantCell)
+		}
+		if got := tree.GetRoot().GetText(); got != wantNode {
+			t.Errorf("tree node = %q, want %q", got, wantNode)
+		}
+	}
+	check("Inbox (3)", "3 new", "3", "Inbox [3]")
+
+	ctx.SetStateDirect("unread", 4)
+	ctx.RefreshDirtyBoundViews()
+	check("Inbox (4)", "4 new", "4", "Inbox [4]")
+}
+
+func TestBuildTable_HeaderStyle(t *testing.T) {
+	ctx := template.NewContext(tview.NewApplication(), tview.NewPages())
+	b := NewBuilder(ctx, template.NewFunctionRegistry())
+
+	styled := &config.Primitive{Type: "table", Columns: []string{"Name"}, Rows: [][]string{{"Ada"}},
+		HeaderColor: "white", HeaderAlign: "left", HeaderBackgroundColor: "blue"}
+	plain := &config.Primitive{Type: "table", Columns: []string{"Name"}, Rows: [][]string{{"Ada"}}}
+	result, err := b.BuildFromConfig(&config.PageConfig{Type: "flex", Items: []config.FlexItem{
+		{Primitive: styled, Proportion: 1},
+		{Primitive: plain, Proportion: 1},
+	}})
+	if err != nil {
+		t.Fatalf("BuildFromConfig: %v", err)
+	}
+	page, err := b.BuildFromConfig(&config.PageConfig{Type: "table", TableData: &config.TableData{
+		Headers: []string{"Name"}, Rows: [][]string{{"Ada"}}, HeaderColor: "white", HeaderAlign: "right",
+	}})
+	if err != nil {
+		t.Fatalf("BuildFromConfig (page table): %v", err)
+	}
+
+	tests := []struct {
+		name      string
+		table     *tview.Table
+		wantColor tcell.Color
+		wantAlign int
+		wantBg    tcell.Color
+	}{
+		{"styled", result.(*tview.Flex).GetItem(0).(*tview.Table), tcell.ColorWhite, tview.AlignLeft, tcell.ColorBlue},
+		{"default", result.(*tview.Flex).GetItem(1).(*tview.Table), tcell.ColorYellow, tview.AlignCenter, tview.Styles.PrimitiveBackgroundColor},
+		{"page table", page.(*tview.Table), tcell.ColorWhite, tview.AlignRight, tview.Styles.PrimitiveBackgroundColor},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cell := tt.table.GetCell(0, 0)
+			if cell.Color != tt.wantColor || cell.Align != tt.wantAlign || cell.BackgroundColor != tt.wantBg {
+				t.Errorf("header cell color/align/background = %v/%d/%v, want %v/%d/%v",
+					cell.Color, cell.Align, cell.BackgroundColor, tt.wantColor, tt.wantAlign, tt.wantBg)
+			}
+			if !cell.NotSelectable {
+				t.Error("header cell should not be selectable")
+			}
+		})
+	}
+}
+
+func TestBuildTable_WithoutHeaders(t *testing.T) {
+	ctx := template.NewContext(tview.NewApplication(), tview.NewPages())
+	b := NewBuilder(ctx, template.NewFunctionRegistry())
+	off := false
+	rows := [][]string{{"a1", "b1"}, {"a2", "b2"}}
+
+	nested, err := b.BuildFromConfig(&config.PageConfig{Type: "flex", Items: []config.FlexItem{
+		{Primitive: &config.Primitive{Type: "table", Columns: []string{"A", "B"}, Rows: rows, ShowHeaders: &off}, Proportion: 1},
+	}})
+	if err != nil {
+		t.Fatalf("BuildFromConfig: %v", err)
+	}
+	page, err := b.BuildFromConfig(&config.PageConfig{Type: "table", TableData: &config.TableData{
+		Headers: []string{"A", "B"}, Rows: rows, ShowHeaders: &off,
+	}})
+	if err != nil {
+		t.Fatalf("BuildFromConfig (page table): %v", err)
+	}
+
+	for name, table := range map[string]*tview.Table{"nested": nested.(*tview.Flex).GetItem(0).(*tview.Table), "page": page.(*tview.Table)} {
+		if got := table.GetRowCount(); got != len(rows) {
+			t.Errorf("%s: GetRowCount() = %d, want %d (no header row)", name, got, len(rows))
+		}
+		cell := table.GetCell(0, 0)
+		if cell.Text != "a1" || cell.NotSelectable {
+			t.Errorf("%s: row 0 = %q (selectable %v), want selectable data cell a1", name, cell.Text, !cell.NotSelectable)
+		}
+	}
+}
+
+func TestBuildTable_MultilineCells(t *testing.T) {
+	ctx := template.NewContext(tview.NewApplication(), tview.NewPages())
+	b := NewBuilder(ctx, template.NewFunctionRegistry())
+	prim := &config.Primitive{Type: "table", Columns: []string{"Task", "Notes"}, MultilineCells: true, Rows: [][]string{
+		{"Build", "first line\nsecond\tline\nthird"},
+		{"Ship", "done"},
+	}}
+	result, err := b.BuildFromConfig(&config.PageConfig{Type: "flex", Items: []config.FlexItem{{Primitive: prim, Proportion: 1}}})
+	if err != nil {
+		t.Fatalf("BuildFromConfig: %v", err)
+	}
+	table := result.(*tview.Flex).GetItem(0).(*tview.Table)
+
+	// Header, three rows for the multi-line cell, then the next data row
+	want := [][]string{
+		{"Task", "Notes"},
+		{"Build", "first line"},
+		{"", "second  line"},
+		{"", "third"},
+		{"Ship", "done"},
+	}
+	if got := table.GetRowCount(); got != len(want) {
+		t.Fatalf("GetRowCount() = %d, want %d", got, len(want))
+	}
+	for row, cols := range want {
+		for col, text := range cols {
+			cell := table.GetCell(row, col)
+			if cell.Text != text {
+				t.Errorf("cell (%d,%d) = %q, want %q", row, col, cell.Text, text)
+			}
+			if continuation := row == 2 || row == 3; row > 0 && cell.NotSelectable != continuation {
+				t.Errorf("cell (%d,%d) selectable = %v, want %v", row, col, !cell.NotSelectable, !continuation)
+			}
+		}
+	}
+	if ref, _ := table.GetCell(1, 1).GetReference().(string); ref != prim.Rows[0][1] {
+		t.Errorf("first line reference = %q, want the whole cell text", ref)
+	}
+}
+
+func TestBuildList_ShortcutHint(t *testing.T) {
+	ctx := template.NewContext(tview.NewApplication(), tview.NewPages())
+	b := NewBuilder(ctx, template.NewFunctionRegistry())
+	prim := &config.Primitive{Type: "list", ShowShortcutHint: true, ListItems: []config.ListItem{
+		{MainText: "Open", SecondaryText: "Open a file", Shortcut: "o"},
+		{MainText: "New", Shortcut: "Ctrl+N"},
+		{MainText: "About", SecondaryText: "Version info"},
+	}}
+	result, err := b.BuildFromConfig(&config.PageConfig{Type: "flex", Items: []config.FlexItem{{Primitive: prim, Proportion: 1}}})
+	if err != nil {
+		t.Fatalf("BuildFromConfig: %v", err)
+	}
+	list := result.(*tview.Flex).GetItem(0).(*tview.List)
+	for i, want := range []string{"Open a file (o)", "(Ctrl+N)", "Version info"} {
+		if _, secondary := list.GetItemText(i); secondary != want {
+			t.Errorf("item %d secondary text = %q, want %q", i, secondary, want)
+		}
+	}
+}
+
+func TestBuildList_AutoShortcuts(t *testing.T) {
+	ctx := template.NewContext(tview.NewApplication(), tview.NewPages())
+	b := NewBuilder(ctx, template.NewFunctionRegistry())
+	items := []config.ListItem{
+		{MainText: "Inbox", OnSelected: "{{ noop }}"},
+		{MainText: "Drafts", OnSelected: "{{ noop }}", Shortcut: "2"}, // explicit; 2 is not auto-assigned
+		{MainText: "Folders"}, // no action: not selectable
+		{MainText: "Sent", OnSelected: "{{ noop }}"},
+		{MainText: "Trash", OnSelected: "{{ noop }}"},
+	}
+	wantHints := []string{"(1)", "(2)", "", "(3)", "(4)"}
+
+	prim := &config.Primitive{Type: "list", AutoShortcuts: true, ShowShortcutHint: true, ListItems: items}
+	result, err := b.BuildFromConfig(&config.PageConfig{Type: "flex", Items: []config.FlexItem{{Primitive: prim, Proportion: 1}}})
+	if err != nil {
+		t.Fatalf("BuildFromConfig: %v", err)
+	}
+	list := result.(*tview.Flex).GetItem(0).(*tview.List)
+	for i, want := range wantHints {
+		if _, secondary := list.GetItemText(i); secondary != want {
+			t.Errorf("item %d secondary text = %q, want %q", i, secondary, want)
+		}
+	}
+	if items[0].Shortcut != "" {
+		t.Error("autoShortcuts should not modify the config's items")
+	}
+
+	// Page-level list: the shortcuts select their items
+	page, err := b.BuildFromConfig(&config.PageConfig{Type: "list", AutoShortcuts: true, ListItems: items})
+	if err != nil {
+		t.Fatalf("BuildFromConfig: %v", err)
+	}
+	pageList := page.(*tview.List)
+	for key, want := range map[rune]int{'4': 4, '3': 3, '1': 0} {
+		pageList.InputHandler()(tcell.NewEventKey(tcell.KeyRune, key, tcell.ModNone), func(tview.Primitive) {})
+		if got := pageList.GetCurrentItem(); got != want {
+			t.Errorf("after %q current item = %d, want %d", key, got, want)
+		}
+	}
+}
+
+func TestBuildList_ItemColors(t *testing.T) {
+	ctx := template.NewContext(tview.NewApplication(), tview.NewPages())
+	b := NewBuilder(ctx, template.NewFunctionRegistry())
+	items := []config.ListItem{
+		{MainText: "api", SecondaryText: "healthy"},
+		{MainText: "db", SecondaryText: "connection refused", MainTextColor: "red", SecondaryTextColor: "orange"},
+	}
+
+	for _, filterable := range []bool{false, true} {
+		t.Run(fmt.Sprintf("filterable=%v", filterable), func(t *testing.T) {
+			prim := &config.Primitive{Type: "list", ListItems: items, Filterable: filterable}
+			result, err := b.BuildFromConfig(&config.PageConfig{Type: "flex", Items: []config.FlexItem{{Primitive: prim, Proportion: 1}}})
+			if err != nil {
+				t.Fatalf("BuildFromConfig: %v", err)
+			}
+			list := result.(*tview.Flex).GetItem(0).(*tview.List)
+			if main, secondary := list.GetItemText(0); main != "api" || secondary != "healthy" {
+				t.Errorf("uncolored item = (%q, %q), want no tags", main, secondary)
+			}
+			if main, secondary := list.GetItemText(1); main != "[red]db" || secondary != "[orange]connection refused" {
+				t.Errorf("colored item = (%q, %q), want color tags", main, secondary)
+			}
+		})
+	}
+}
+
+func TestBuild_ThemeSelectionColors(t *testing.T) {
+	ctx := template.NewContext(tview.NewApplication(), tview.NewPages())
+	ctx.SetTheme(&config.Theme{SelectionColor: "purple", SelectionTextColor: "yellow"})
+	b := NewBuilder(ctx, template.NewFunctionRegistry())
+	result, err := b.BuildFromConfig(&config.PageConfig{Type: "flex", Direction: "row", Items: []config.FlexItem{
+		{Primitive: &config.Primitive{Type: "list", Name: "menu", ListItems: []config.ListItem{{MainText: "alpha"}, {MainText: "beta"}}}, FixedSize: 4},
+		{Primitive: &config.Primitive{Type: "table", Name: "grid", Columns: []string{"Name"}, Rows: [][]string{{"gamma"}}}, Proportion: 1},
+	}})
+	if err != nil {
+		t.Fatalf("BuildFromConfig: %v", err)
+	}
+	p, _ := ctx.GetPrimitive("grid")
+	p.(*tview.Table).Select(1, 0)
+
+	screen := tcell.NewSimulationScreen("")
+	if err := screen.Init(); err != nil {
+		t.Fatal(err)
+	}
+	defer screen.Fini()
+	screen.SetSize(20, 8)
+	result.SetRect(0, 0, 20, 8)
+	result.Draw(screen)
+
+	find := func(r rune) (tcell.Color, tcell.Color) {
+		t.Helper()
+		for y := 0; y < 8; y++ {
+			for x := 0; x < 20; x++ {
+				if ch, _, style, _ := screen.GetContent(x, y); ch == r {
+					fg, bg, _ := style.Decompose()
+					return fg, bg
+				}
+			}
+		}
+		t.Fatalf("%q not drawn", r)
+		return 0, 0
+	}
+	if fg, bg := find('a'); fg != tcell.ColorYellow || bg != tcell.ColorPurple {
+		t.Errorf("selected list item fg/bg = %v/%v, want yellow/purple", fg, bg)
+	}
+	if fg, bg := find('g'); fg != tcell.ColorYellow || bg != tcell.ColorPurple {
+		t.Errorf("selected table row fg/bg = %v/%v, want yellow/purple", fg, bg)
+	}
+	if _, bg := find('b'); bg == tcell.ColorPurple {
+		t.Error("unselected list item should not use the selection color")
+	}
+}
+
+func TestBuildSpinner_BusyFlagStartsAndStopsTicks(t *testing.T) {
+	ctx := template.NewContext(tview.NewApplication(), tview.NewPages())
+	b := NewBuilder(ctx, template.NewFunctionRegistry())
+	pageConfig := &config.PageConfig{
+		Type: "flex",
+		Items: []config.FlexItem{{
+			Primitive:  &config.Primitive{Type: "spinner", ValueFromState: "loading", Interval: "5ms", Text: "Loading"},
+			Proportion: 1,
+		}},
+	}
+	if _, err := b.BuildFromConfig(pageConfig); err != nil {
+		t.Fatalf("BuildFromConfig: %v", err)
+	}
+	tick := func() interface{} {
+		v, _ := ctx.GetState(spinnerTickKey)
+		return v
+	}
+	// setBusy stands in for the refresh loop, which runs state subscribers on the main goroutine.
+	setBusy := func(busy bool) {
+		ctx.SetState("loading", busy)
+		ctx.RefreshDirtyBoundViews()
+	}
+
+	time.Sleep(20 * time.Millisecond)
+	if v := tick(); v != nil {
+		t.Fatalf("spinner ticked (%v) before the busy flag was set", v)
+	}
+
+	setBusy(true)
+	deadline := time.Now().Add(time.Second)
+	for tick() == nil && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	if tick() == nil {
+		t.Fatal("spinner did not tick while busy")
+	}
+
+	setBusy(false)
+	time.Sleep(20 * time.Millisecond) // let a tick already in flight land
+	stopped := tick()
+	time.Sleep(30 * time.Millisecond)
+	if v := tick(); v != stopped {
+		t.Errorf("spinner kept ticking after busy turned false: %v -> %v", stopped, v)
+	}
+}
+
+func TestBuildForm_DisabledButtons(t *testing.T) {
+	app := tview.NewApplication()
+	pages := tview.NewPages()
+	ctx := template.NewContext(app, pages)
+	registry := template.NewFunctionRegistry()
+	maxOne := 1
+	if err := registry.Register("mark", 1, &maxOne, nil, func(ctx *template.Context, v string) {
+		ctx.SetStateDirect("pressed", v)
+	}); err != nil {
+		t.Fatalf("Register: %v", err)
+	}
+	b := NewBuilder(ctx, registry)
+
+	disabled := true
+	ctx.SetStateDirect("invalid", true)
+	pageConfig := &config.PageConfig{
+		Type: "form",
+		FormItems: []config.FormItem{
+			{Type: "inputfield", Label: "Name", Disabled: &disabled},
+			{Type: "button", Label: "Submit", DisabledWhen: "invalid", OnSelected: `{{ mark "submit" }}`},
+			{Type: "button", Label: "Never", Disabled: &disabled, OnSelected: `{{ mark "never" }}`},
+		},
+	}
+	result, err := b.BuildFromConfig(pageConfig)
+	if err != nil {
+		t.Fatalf("BuildFromConfig: %v", err)
+	}
+	form := result.(*tview.Form)
+	submit, never := form.GetButton(0), form.GetButton(1)
+
+	press := func(btn *tview.Button) {
+		btn.InputHandler()(tcell.NewEventKey(tcell.KeyEnter, 0, tcell.ModNone), func(tview.Primitive) {})
+	}
+	press(submit)
+	press(never)
+	if v, ok := ctx.GetState("pressed"); ok {
+		t.Fatalf("disabled buttons should not run their action; pressed = %v", v)
+	}
+	input := form.GetFormItem(0).(*tview.InputField)
+	input.InputHandler()(tcell.NewEventKey(tcell.KeyRune, 'x', tcell.ModNone), func(tview.Primitive) {})
+	if got := input.GetText(); got != "" {
+		t.Errorf("disabled input field accepted text %q", got)
+	}
+
+	ctx.SetStateDirect("invalid", false)
+	ctx.RefreshDirtyBoundViews()
+	press(submit)
+	if v, _ := ctx.GetState("pressed"); v != "submit" {
+		t.Errorf("pressed = %v after disabledWhen cleared, want submit", v)
+	}
+	if !never.IsDisabled() {
+		t.Error("button with disabled: true should stay disabled")
+	}
+}
+
+func TestBuildForm_MarkRequired(t *testing.T) {
+	ctx := template.NewContext(tview.NewApplication(), tview.NewPages())
+	b := NewBuilder(ctx, template.NewFunctionRegistry())
+	items := []config.FormItem{
+		{Type: "inputfield", Label: "Name", Required: true},
+		{Type: "inputfield", Label: "Nickname"},
+		{Type: "dropdown", Label: "Country", Options: []string{"NZ", "AU"}, Required: true},
+		{Type: "checkbox", Label: "Subscribe"},
+	}
+
+	for _, mark := range []bool{true, false} {
+		result, err := b.BuildFromConfig(&config.PageConfig{Type: "form", FormItems: items, MarkRequired: mark})
+		if err != nil {
+			t.Fatalf("BuildFromConfig: %v", err)
+		}
+		form := result.(*tview.Form)
+		for i, item := range items {
+			want := item.Label
+			if mark && item.Required {
+				want += " [red]*[-]"
+			}
+			if got := form.GetFormItem(i).GetLabel(); got != want {
+				t.Errorf("markRequired=%v: item %d label = %q, want %q", mark, i, got, want)
+			}
+		}
+	}
+}
+
+func TestBuildForm_ReadOnlyInputField(t *testing.T) {
+	ctx := template.NewContext(tview.NewApplication(), tview.NewPages())
+	b := NewBuilder(ctx, template.NewFunctionRegistry())
+	result, err := b.BuildFromConfig(&config.PageConfig{Type: "form", FormItems: []config.FormItem{
+		{Type: "inputfield", Label: "Account", Value: "acct-42", ReadOnly: true},
+		{Type: "inputfield", Label: "Nickname", Value: "ada"},
+	}})
+	if err != nil {
+		t.Fatalf("BuildFromConfig: %v", err)
+	}
+	form := result.(*tview.Form)
+	screen := tcell.NewSimulationScreen("")
+	if err := screen.Init(); err != nil {
+		t.Fatalf("screen.Init: %v", err)
+	}
+	screen.SetSize(40, 6)
+	form.SetRect(0, 0, 40, 6)
+	form.Draw(screen)
+
+	events := []*tcell.EventKey{
+		tcell.NewEventKey(tcell.KeyRune, 'x', tcell.ModNone),
+		tcell.NewEventKey(tcell.KeyLeft, 0, tcell.ModNone),
+		tcell.NewEventKey(tcell.KeyBackspace2, 0, tcell.ModNone),
+		tcell.NewEventKey(tcell.KeyHome, 0, tcell.ModNone),
+		tcell.NewEventKey(tcell.KeyCtrlK, 0, tcell.ModCtrl),
+	}
+	for i, want := range []string{"acct-42", ""} {
+		input := form.GetFormItem(i).(*tview.InputField)
+		for _, event := range events {
+			input.InputHandler()(event, func(tview.Primitive) {})
+		}
+		if got := input.GetText(); got != want {
+			t.Errorf("item %d text after typing = %q, want %q", i, got, want)
+		}
+	}
+}
+
+func TestBuildGrid_NestedFormCallbacks(t *testing.T) {
+	ctx := template.NewContext(tview.NewApplication(), tview.NewPages())
+	registry := template.NewFunctionRegistry()
+	maxZero := 0
+	var calls []string
+	for _, name := range []string{"saved", "canceled"} {
+		name := name
+		if err := registry.Register(name, 0, &maxZero, nil, func(ctx *template.Context) { calls = append(calls, name) }); err != nil {
+			t.Fatalf("Register: %v", err)
+		}
+	}
+	b := NewBuilder(ctx, registry)
+
+	form := &config.Primitive{
+		Type:     "form",
+		Name:     "settings",
+		OnSubmit: "{{ saved }}",
+		OnCancel: "{{ canceled }}",
+		FormItems: []config.FormItem{
+			{Type: "inputfield", Label: "Host", Value: "localhost"},
+			{Type: "button", Label: "Save", OnSelected: `{{ runFormSubmit "settings" }}`},
+			{Type: "button", Label: "Cancel", OnSelected: `{{ runFormCancel "settings" }}`},
+		},
+	}
+	grid := &config.Primitive{Type: "grid", GridItems: []config.GridItem{
+		{Primitive: &config.Primitive{Type: "textView", Text: "Stats"}, Row: 0, Column: 0},
+		{Primitive: form, Row: 0, Column: 1},
+	}}
+	if _, err := b.BuildFromConfig(&config.PageConfig{Type: "flex", Items: []config.FlexItem{{Primitive: grid, Proportion: 1}}}); err != nil {
+		t.Fatalf("BuildFromConfig: %v", err)
+	}
+	p, ok := ctx.GetPrimitive("settings")
+	if !ok {
+		t.Fatal("the nested form should be registered under its name")
+	}
+	built := p.(*tview.Form)
+
+	enter := tcell.NewEventKey(tcell.KeyEnter, 0, tcell.ModNone)
+	built.GetButton(0).InputHandler()(enter, func(tview.Primitive) {})
+	built.GetButton(1).InputHandler()(enter, func(tview.Primitive) {})
+	// Escape in a field runs the form's cancel func (SetCancelFunc)
+	var focus func(p tview.Primitive)
+	focus = func(p tview.Primitive) { p.Focus(focus) }
+	built.SetFocus(0)
+	built.Focus(focus)
+	built.InputHandler()(tcell.NewEventKey(tcell.KeyEscape, 0, tcell.ModNone), focus)
+
+	if want := "saved,canceled,canceled"; strings.Join(calls, ",") != want {
+		t.Errorf("calls = %q, want %q", strings.Join(calls, ","), want)
+	}
+}
+
+func TestBuildForm_DropdownOnChangedState(t *testing.T) {
+	ctx := template.NewContext(tview.NewApplication(), tview.NewPages())
+	registry := template.NewFunctionRegistry()
+	maxZero := 0
+	var seen []string
+	if err := registry.Register("countryChanged", 0, &maxZero, nil, func(ctx *template.Context) {
+		text, _ := ctx.GetState("__dropdownText")
+		index, _ := ctx.GetState("__dropdownIndex")
+		seen = append(seen, fmt.Sprintf("%v/%v", text, index))
+	}); err != nil {
+		t.Fatalf("Register: %v", err)
+	}
+	b := NewBuilder(ctx, registry)
+
+	pageConfig := &config.PageConfig{
+		Type: "form",
+		FormItems: []config.FormItem{
+			{Type: "dropdown", Label: "Country", Options: []string{"Canada", "Mexico", "Peru"}, OnChanged: "{{ countryChanged }}"},
+		},
+	}
+	result, err := b.BuildFromConfig(pageConfig)
+	if err != nil {
+		t.Fatalf("BuildFromConfig: %v", err)
+	}
+	seen = nil
+	result.(*tview.Form).GetFormItem(0).(*tview.DropDown).SetCurrentOption(2)
+	if len(seen) != 1 || seen[0] != "Peru/2" {
+		t.Errorf("onChanged saw %q, want [Peru/2]", seen)
+	}
+}
+
+func TestBuildDropDown_MaxVisibleOptions(t *testing.T) {
+	ctx := template.NewContext(tview.NewApplication(), tview.NewPages())
+	b := NewBuilder(ctx, template.NewFunctionRegistry())
+	var options []string
+	for i := 0; i < 50; i++ {
+		options = append(options, fmt.Sprintf("Zone %02d", i))
+	}
+	prim := &config.Primitive{Type: "dropdown", Name: "tz", Options: options, MaxVisibleOptions: 5}
+	result, err := b.BuildFromConfig(&config.PageConfig{Type: "flex", Direction: "row", Items: []config.FlexItem{
+		{Primitive: prim, FixedSize: 1},
+		{Spacer: true, Proportion: 1},
+	}})
+	if err != nil {
+		t.Fatalf("BuildFromConfig: %v", err)
+	}
+
+	screen := tcell.NewSimulationScreen("")
+	if err := screen.Init(); err != nil {
+		t.Fatal(err)
+	}
+	defer screen.Fini()
+	screen.SetSize(40, 20)
+	result.SetRect(0, 0, 40, 20)
+
+	p, _ := ctx.GetPrimitive("tz")
+	dd := p.(*tview.DropDown)
+	dd.Focus(nil)
+	dd.InputHandler()(tcell.NewEventKey(tcell.KeyEnter, 0, tcell.ModNone), func(p tview.Primitive) { p.Focus(nil) })
+	if !dd.IsOpen() {
+		t.Fatal("drop-down did not open")
+	}
+	result.Draw(screen)
+
+	zones := 0
+	for y := 0; y < 20; y++ {
+		if ch, _, _, _ := screen.GetContent(0, y); ch == 'Z' {
+			zones++
+		}
+	}
+	if zones != 5 {
+		t.Errorf("drew %d rows of options, want 5", zones)
+	}
+}
+
+func TestBuildForm_SubmitOnEnter(t *testing.T) {
+	ctx := template.NewContext(tview.NewApplication(), tview.NewPages())
+	registry := template.NewFunctionRegistry()
+	maxZero := 0
+	submits := 0
+	if err := registry.Register("login", 0, &maxZero, nil, func(ctx *template.Context) { submits++ }); err != nil {
+		t.Fatalf("Register: %v", err)
+	}
+	b := NewBuilder(ctx, registry)
+
+	pageConfig := &config.PageConfig{
+		Type:     "form",
+		Name:     "login",
+		OnSubmit: "{{ login }}",
+		FormItems: []config.FormItem{
+			{Type: "inputfield", Label: "User"},
+			{Type: "inputfield", Label: "Password", PasswordMode: true, SubmitOnEnter: true},
+		},
+	}
+	result, err := b.BuildFromConfig(pageConfig)
+	if err != nil {
+		t.Fatalf("BuildFromConfig: %v", err)
+	}
+	form := result.(*tview.Form)
+	enter := func(i int) {
+		form.GetFormItem(i).(*tview.InputField).InputHandler()(tcell.NewEventKey(tcell.KeyEnter, 0, tcell.ModNone), func(tview.Primitive) {})
+	}
+
+	enter(0)
+	if submits != 0 {
+		t.Fatalf("Enter in a field without submitOnEnter submitted the form")
+	}
+	enter(1)
+	if submits != 1 {
+		t.Errorf("Enter in the password field ran onSubmit %d times, want 1", submits)
+	}
+
+	// The submit is looked up by form name, so an unnamed form is a build error
+	pageConfig.Name = ""
+	if _, err := b.BuildFromConfig(pageConfig); err == nil || !strings.Contains(err.Error(), "submitOnEnter requires the form to have a name") {
+		t.Errorf("BuildFromConfig without a form name: err = %v", err)
+	}
+}
+
+func TestBuildModal_Colors(t *testing.T) {
+	app := tview.NewApplication()
+	pages := tview.NewPages()
+	ctx := template.NewContext(app, pages)
+	b := NewBuilder(ctx, template.NewFunctionRegistry())
+
+	result, err := b.BuildFromConfig(&config.PageConfig{
+		Type:                  "modal",
+		Text:                  "Failed",
+		Buttons:               []config.ModalButton{{Label: "OK"}},
+		BackgroundColor:       "red",
+		TextColor:             "yellow",
+		ButtonBackgroundColor: "navy",
+		ButtonTextColor:       "lime",
+	})
+	if err != nil {
+		t.Fatalf("BuildFromConfig: %v", err)
+	}
+
+	screen := tcell.NewSimulationScreen("")
+	if err := screen.Init(); err != nil {
+		t.Fatal(err)
+	}
+	defer screen.Fini()
+	screen.SetSize(40, 10)
+	result.SetRect(0, 0, 40, 10)
+	result.Draw(screen)
+
+	find := func(r rune) tcell.Style {
+		for y := 0; y < 10; y++ {
+			for x := 0; x < 40; x++ {
+				if ch, _, style, _ := screen.GetContent(x, y); ch == r {
+					return style
+				}
+			}
+		}
+		t.Fatalf("%q not drawn", r)
+		return tcell.StyleDefault
+	}
+	if fg, bg, _ := find('F').Decompose(); fg != tcell.ColorYellow || bg != tcell.ColorRed {
+		t.Errorf("text fg/bg = %v/%v, want yellow/red", fg, bg)
+	}
+	if fg, bg, _ := find('K').Decompose(); fg != tcell.ColorLime || bg != tcell.ColorNavy {
+		t.Errorf("button fg/bg = %v/%v, want lime/navy", fg, bg)
+	}
+}
+
+func TestBuildFromConfigRef_RelativeNestedRefs(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"pages/settings/index.yaml": `type: flex
+items:
+  - primitive:
+      type: pages
+      pages:
+        - name: general
+          ref: general.yaml
+        - name: footer
+          ref: ../shared/footer.yaml
+    proportion: 1
+`,
+		"pages/settings/general.yaml": "type: flex\nitems:\n  - primitive:\n      type: textView\n      name: general\n    proportion: 1\n",
+		"pages/shared/footer.yaml":    "type: flex\nitems:\n  - primitive:\n      type: textView\n      name: footer\n    proportion: 1\n",
+	}
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	app := tview.NewApplication()
+	ctx := template.NewContext(app, tview.NewPages())
+	b := NewBuilder(ctx, template.NewFunctionRegistry())
+	loader := config.NewLoader(dir)
+	b.SetLoader(loader)
+
+	ref := "pages/settings/index.yaml"
+	pageCfg, err := loader.LoadPage(ref)
+	if err != nil {
+		t.Fatalf("LoadPage: %v", err)
+	}
+	if _, err := b.BuildFromConfigRef(pageCfg, ref); err != nil {
+		t.Fatalf("BuildFromConfigRef: %v", err)
+	}
+	for _, name := range []string{"general", "footer"} {
+		if _, ok := ctx.GetPrimitive(name); !ok {
+			t.Errorf("nested page with primitive %q was not built", name)
+		}
+	}
+}
@@ -0,0 +1,164 @@
+package builder
+
+import (
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+// defaultDialogWidth is the outer width of a dialog that does not configure one.
+const defaultDialogWidth = 50
+
+// Dialog is a centered message window like tview.Modal, but with a configurable width.
+// Text is word-wrapped to fit that width (and scrolls if it is taller than the screen),
+// with a centered button row below it. Both dimensions shrink to fit small screens.
+type Dialog struct {
+	*tview.Box
+
+	// frame holds the text above the buttons and draws the border and title.
+	frame *tview.Flex
+	text  *tview.TextView
+	form  *tview.Form
+
+	// width is the outer width including the border; 0 uses defaultDialogWidth.
+	width int
+
+	done func(buttonIndex int, buttonLabel string)
+}
+
+// NewDialog returns a new dialog with no text and no buttons.
+func NewDialog() *Dialog {
+	d := &Dialog{Box: tview.NewBox()}
+	d.text = tview.NewTextView().
+		SetWordWrap(true).
+		SetTextAlign(tview.AlignCenter).
+		SetTextColor(tview.Styles.PrimaryTextColor)
+	d.text.SetBackgroundColor(tview.Styles.ContrastBackgroundColor)
+	d.form = tview.NewForm().
+		SetButtonsAlign(tview.AlignCenter).
+		SetButtonBackgroundColor(tview.Styles.PrimitiveBackgroundColor).
+		SetButtonTextColor(tview.Styles.PrimaryTextColor)
+	d.form.SetBackgroundColor(tview.Styles.ContrastBackgroundColor).SetBorderPadding(0, 0, 0, 0)
+	d.form.SetCancelFunc(func() {
+		if d.done != nil {
+			d.done(-1, "")
+		}
+	})
+	d.frame = tview.NewFlex().SetDirection(tview.FlexRow).
+		AddItem(d.text, 0, 1, false).
+		AddItem(nil, 1, 0, false).
+		AddItem(d.form, 1, 0, true)
+	d.frame.SetBorder(true).
+		SetBackgroundColor(tview.Styles.ContrastBackgroundColor).
+		SetBorderPadding(1, 0, 1, 1)
+	return d
+}
+
+// SetText sets the message text. It is word-wrapped to the dialog width.
+func (d *Dialog) SetText(text string) *Dialog {
+	d.text.SetText(text)
+	return d
+}
+
+// SetWidth sets the outer width of the dialog, including its border. 0 uses the default width.
+func (d *Dialog) SetWidth(width int) *Dialog {
+	d.width = width
+	return d
+}
+
+// SetTitle sets the title shown in the dialog border.
+func (d *Dialog) SetTitle(title string) *tview.Box {
+	return d.frame.SetTitle(title)
+}
+
+// SetTitleAlign sets the alignment of the title in the dialog border.
+func (d *Dialog) SetTitleAlign(align int) *tview.Box {
+	return d.frame.SetTitleAlign(align)
+}
+
+// SetBackgroundColor sets the background color of the dialog window.
+func (d *Dialog) SetBackgroundColor(color tcell.Color) *Dialog {
+	d.frame.SetBackgroundColor(color)
+	d.text.SetBackgroundColor(color)
+	d.form.SetBackgroundColor(color)
+	return d
+}
+
+// SetTextColor sets the color of the message text.
+func (d *Dialog) SetTextColor(color tcell.Color) *Dialog {
+	d.text.SetTextColor(color)
+	return d
+}
+
+// AddButtons adds buttons to the dialog. Pressing one calls the done func with its index and label.
+func (d *Dialog) AddButtons(labels []string) *Dialog {
+	for _, label := range labels {
+		func(i int, l string) {
+			d.form.AddButton(l, func() {
+				if d.done != nil {
+					d.done(i, l)
+				}
+			})
+		}(d.form.GetButtonCount(), label)
+	}
+	return d
+}
+
+// SetDoneFunc sets the handler called when a button is pressed (or with index -1 on Escape).
+func (d *Dialog) SetDoneFunc(handler func(buttonIndex int, buttonLabel string)) *Dialog {
+	d.done = handler
+	return d
+}
+
+// Focus is called when this primitive receives focus.
+func (d *Dialog) Focus(delegate func(p tview.Primitive)) {
+	delegate(d.form)
+}
+
+// HasFocus returns whether or not this primitive has focus.
+func (d *Dialog) HasFocus() bool {
+	return d.form.HasFocus()
+}
+
+// Draw centers the dialog on the screen and draws it. The height fits the wrapped text
+// (two border rows, one row of top padding, the text, a blank row, and the buttons).
+func (d *Dialog) Draw(screen tcell.Screen) {
+	screenWidth, screenHeight := screen.Size()
+	width := d.width
+	if width <= 0 {
+		width = defaultDialogWidth
+	}
+	width = min(width, screenWidth)
+
+	// The text area is the width minus the border (2) and horizontal padding (2).
+	lines := len(tview.WordWrap(d.text.GetText(true), max(width-4, 1)))
+	height := min(lines+5, screenHeight)
+
+	x := (screenWidth - width) / 2
+	y := (screenHeight - height) / 2
+	d.SetRect(x, y, width, height)
+	d.frame.SetRect(x, y, width, height)
+	d.frame.Draw(screen)
+}
+
+// MouseHandler returns the mouse handler for this primitive.
+func (d *Dialog) MouseHandler() func(action tview.MouseAction, event *tcell.EventMouse, setFocus func(p tview.Primitive)) (consumed bool, capture tview.Primitive) {
+	return d.WrapMouseHandler(func(action tview.MouseAction, event *tcell.EventMouse, setFocus func(p tview.Primitive)) (consumed bool, capture tview.Primitive) {
+		consumed, capture = d.frame.MouseHandler()(action, event, setFocus)
+		if !consumed && action == tview.MouseLeftDown && d.InRect(event.Position()) {
+			setFocus(d)
+			consumed = true
+		}
+		return
+	})
+}
+
+// InputHandler returns the handler for this primitive.
+func (d *Dialog) InputHandler() func(event *tcell.EventKey, setFocus func(p tview.Primitive)) {
+	return d.WrapInputHandler(func(event *tcell.EventKey, setFocus func(p tview.Primitive)) {
+		if d.frame.HasFocus() {
+			if handler := d.frame.InputHandler(); handler != nil {
+				handler(event, setFocus)
+			}
+		}
+	})
+}
@@ -0,0 +1,61 @@
+package builder
+
+import (
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+// boundedDropDown is a drop-down whose open option list shows at most maxVisible options; the
+// list scrolls to keep the current option in view. tview sizes the list to every option (clipped
+// only by the screen) and offers no setter, so the list is drawn again here at the bounded size.
+type boundedDropDown struct {
+	*tview.DropDown
+	maxVisible int
+}
+
+func newBoundedDropDown(dd *tview.DropDown, maxVisible int) *boundedDropDown {
+	return &boundedDropDown{DropDown: dd, maxVisible: maxVisible}
+}
+
+// Draw draws the drop-down's field, then its open list (if any) at the bounded height, placed
+// the way tview places it: below the field, or above if there is more room there.
+func (d *boundedDropDown) Draw(screen tcell.Screen) {
+	if !d.IsOpen() || !d.HasFocus() {
+		d.DropDown.Draw(screen)
+		return
+	}
+	x, y, width, height := d.GetRect()
+	d.DropDown.Draw(&clippedScreen{Screen: screen, x: x, y: y, width: width, height: height})
+
+	// While open, the drop-down delegates focus to its list.
+	var list *tview.List
+	d.DropDown.Focus(func(p tview.Primitive) { list, _ = p.(*tview.List) })
+	if list == nil {
+		return
+	}
+	lx, _, lwidth, _ := list.GetRect()
+	_, fy, _, _ := d.GetInnerRect()
+	_, sheight := screen.Size()
+	lheight := min(list.GetItemCount(), d.maxVisible)
+	ly := fy + 1
+	if ly+lheight >= sheight && ly-2 > lheight-ly {
+		ly = max(fy-lheight, 0)
+	}
+	if ly+lheight >= sheight {
+		lheight = sheight - ly
+	}
+	list.SetRect(lx, ly, lwidth, lheight)
+	list.Draw(screen)
+}
+
+// clippedScreen discards content set outside its rectangle.
+type clippedScreen struct {
+	tcell.Screen
+	x, y, width, height int
+}
+
+func (s *clippedScreen) SetContent(x, y int, mainc rune, combc []rune, style tcell.Style) {
+	if x >= s.x && x < s.x+s.width && y >= s.y && y < s.y+s.height {
+		s.Screen.SetContent(x, y, mainc, combc, style)
+	}
+}
@@ -18,7 +18,7 @@ func NewFactory() *Factory {
 // CreatePrimitive creates a tview primitive based on type
 func (f *Factory) CreatePrimitive(prim *config.Primitive) (tview.Primitive, error) {
 	switch prim.Type {
-	case "box":
+	case "box", "progressBar", "sparkline", "spinner":
 		return tview.NewBox(), nil
 
 	case "textView":
@@ -62,6 +62,9 @@ func (f *Factory) CreatePrimitive(prim *config.Primitive) (tview.Primitive, erro
 	case "modal":
 		return tview.NewModal(), nil
 
+	case "dialog":
+		return NewDialog(), nil
+
 	case "pages":
 		return tview.NewPages(), nil
 
@@ -107,6 +110,9 @@ func (f *Factory) CreatePrimitiveFromPageConfig(cfg *config.PageConfig) (tview.P
 	case "modal":
 		return tview.NewModal(), nil
 
+	case "dialog":
+		return NewDialog(), nil
+
 	default:
 		return nil, fmt.Errorf("unknown page type: %s", cfg.Type)
 	}
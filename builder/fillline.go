@@ -0,0 +1,74 @@
+package builder
+
+import (
+	"regexp"
+	"strings"
+	"sync"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+// fillLines makes every line of tv's text span the view's full inner width, so a background color
+// set with a style tag (e.g. "[:blue]") covers the whole row, as in a selected line of an info bar.
+// Lines are padded with spaces on the side opposite align when the view is drawn, again whenever
+// the text or the width changes; style tags are not counted. The returned set must be used to
+// change the text.
+func fillLines(tv *tview.TextView, align int) (set func(string)) {
+	var mu sync.Mutex
+	var source string
+	width := -1
+	tv.SetDrawFunc(func(screen tcell.Screen, x, y, w, h int) (int, int, int, int) {
+		// SetRect marks the inner rect stale, so GetInnerRect derives it from the border and padding.
+		tv.SetRect(x, y, w, h)
+		ix, iy, iw, ih := tv.GetInnerRect()
+		mu.Lock()
+		changed := iw != width
+		width = iw
+		text := source
+		mu.Unlock()
+		if changed {
+			// The TextView locks itself only after its draw func returns.
+			tv.SetText(padLines(text, iw, align))
+		}
+		return ix, iy, iw, ih
+	})
+	return func(s string) {
+		mu.Lock()
+		source = s
+		width = -1 // pad again on the next draw
+		mu.Unlock()
+		tv.SetText(s)
+	}
+}
+
+// leadingTags and trailingTags match the style and region tags at the start or end of a line.
+var (
+	leadingTags  = regexp.MustCompile(`^(\[[a-zA-Z0-9#:\-]*\]|\["[^"\]]*"\])+`)
+	trailingTags = regexp.MustCompile(`(\[[a-zA-Z0-9#:\-]*\]|\["[^"\]]*"\])+$`)
+)
+
+// padLines pads each line of text with spaces to width cells: after the text for left alignment,
+// before it for right alignment, and on both sides when centered. Padding goes inside the tags
+// that open or close the line, so it takes the style (or region) of the text next to it. Longer
+// lines are unchanged.
+func padLines(text string, width, align int) string {
+	lines := strings.Split(text, "\n")
+	for i, line := range lines {
+		pad := width - tview.TaggedStringWidth(line)
+		if pad <= 0 {
+			continue
+		}
+		before, after := 0, pad
+		switch align {
+		case tview.AlignRight:
+			before, after = pad, 0
+		case tview.AlignCenter:
+			before, after = pad/2, pad-pad/2
+		}
+		open := len(leadingTags.FindString(line))
+		closing := len(line) - len(trailingTags.FindString(line[open:]))
+		lines[i] = line[:open] + strings.Repeat(" ", before) + line[open:closing] + strings.Repeat(" ", after) + line[closing:]
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,104 @@
+package builder
+
+import (
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+// flexItemSize is the configured size of one flex item, in the order the items were added.
+type flexItemSize struct {
+	item                  tview.Primitive // nil for spacers
+	fixedSize, proportion int
+	minSize               int // minimum size of a proportional item (0 = none)
+}
+
+// flexLayout adjusts a flex before every draw (which also follows every resize), for layout
+// rules tview's Flex does not have: a direction that depends on the flex's width, and minimum
+// item sizes.
+type flexLayout struct {
+	row        bool // items stacked top to bottom (direction: row)
+	breakpoint int  // responsiveDirection: side by side at this width or wider, stacked below it (0 = fixed direction)
+	items      []flexItemSize
+}
+
+// attach sets the flex's draw func to apply the layout.
+func (l *flexLayout) attach(flex *tview.Flex) {
+	flex.SetDrawFunc(func(screen tcell.Screen, x, y, width, height int) (int, int, int, int) {
+		if l.breakpoint > 0 {
+			l.row = width < l.breakpoint
+			if l.row {
+				flex.SetDirection(tview.FlexRow)
+			} else {
+				flex.SetDirection(tview.FlexColumn)
+			}
+		}
+		flex.SetRect(x, y, width, height) // mark the inner rect stale so it accounts for the border
+		x, y, width, height = flex.GetInnerRect()
+		total := width
+		if l.row {
+			total = height
+		}
+		l.enforceMinSizes(flex, total)
+		return x, y, width, height
+	})
+}
+
+// enforceMinSizes keeps each proportional item with a minSize at least that many cells along
+// the flex's direction. The items are laid out the way Flex lays them out: an item whose share
+// would fall below its minimum is pinned to that fixed size and the others share what is left.
+// Pinned items get their proportion back once there is room again.
+func (l *flexLayout) enforceMinSizes(flex *tview.Flex, total int) {
+	pinned := make([]bool, len(l.items))
+	for {
+		sizes := flexSizes(l.items, pinned, total)
+		changed := false
+		for i, item := range l.items {
+			if !pinned[i] && item.fixedSize <= 0 && sizes[i] < item.minSize {
+				pinned[i], changed = true, true
+			}
+		}
+		if !changed {
+			break
+		}
+	}
+	for i, item := range l.items {
+		if item.minSize <= 0 || item.fixedSize > 0 {
+			continue
+		}
+		if pinned[i] {
+			flex.ResizeItem(item.item, item.minSize, 0)
+		} else {
+			flex.ResizeItem(item.item, item.fixedSize, item.proportion)
+		}
+	}
+}
+
+// flexSizes returns the size tview's Flex gives each item out of total cells, with pinned items
+// fixed at their minSize.
+func flexSizes(items []flexItemSize, pinned []bool, total int) []int {
+	fixed := func(i int) int {
+		if pinned[i] {
+			return items[i].minSize
+		}
+		return items[i].fixedSize
+	}
+	dist, proportionSum := total, 0
+	for i, item := range items {
+		if size := fixed(i); size > 0 {
+			dist -= size
+		} else {
+			proportionSum += item.proportion
+		}
+	}
+	sizes := make([]int, len(items))
+	for i, item := range items {
+		size := fixed(i)
+		if size <= 0 && proportionSum > 0 {
+			size = dist * item.proportion / proportionSum
+			dist -= size
+			proportionSum -= item.proportion
+		}
+		sizes[i] = max(size, 0)
+	}
+	return sizes
+}
@@ -0,0 +1,121 @@
+package builder
+
+import (
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+// gridPlacement is one grid item as configured, so it can be added to the grid again.
+type gridPlacement struct {
+	item                          tview.Primitive
+	row, column, rowSpan, colSpan int
+	minHeight, minWidth           int
+	focus                         bool
+	hideBelowWidth                int // leave the item out while the grid is narrower (0 = never)
+	hideBelowHeight               int // leave the item out while the grid is shorter (0 = never)
+}
+
+// shownAt reports whether the placement belongs in a grid of the given size.
+func (p gridPlacement) shownAt(width, height int) bool {
+	return width >= p.hideBelowWidth && height >= p.hideBelowHeight
+}
+
+// gridVisibility removes grid items with hideBelowWidth or hideBelowHeight from their grid while
+// the grid is smaller than that, and adds them back once it is large enough again. Unlike tview's
+// minGridWidth/minGridHeight, which only skip drawing an item, the item leaves the grid: it cannot
+// keep focus or receive mouse events, and rows and columns that only hidden items occupy are left
+// out, so the remaining items take their space (a hidden sidebar's column does not stay blank).
+//
+// The grid's draw func checks the grid's size before every draw (which also follows every resize).
+// When the set of visible items changes, it clears the grid and lays it out again from the
+// configured rows, columns, and items, as populateGridItems did.
+type gridVisibility struct {
+	rows, columns []int // configured gridRows and gridColumns
+	placements    []gridPlacement
+	visible       []bool // whether each placement is in the grid
+}
+
+// newGridVisibility returns the visibility rules for a grid built with all its placements.
+func newGridVisibility(rows, columns []int, placements []gridPlacement) *gridVisibility {
+	visible := make([]bool, len(placements))
+	for i := range visible {
+		visible[i] = true
+	}
+	return &gridVisibility{rows: rows, columns: columns, placements: placements, visible: visible}
+}
+
+// attach sets the grid's draw func to apply the rules.
+func (v *gridVisibility) attach(grid *tview.Grid) {
+	grid.SetDrawFunc(func(screen tcell.Screen, x, y, width, height int) (int, int, int, int) {
+		changed := false
+		for i, p := range v.placements {
+			if shown := p.shownAt(width, height); shown != v.visible[i] {
+				v.visible[i], changed = shown, true
+			}
+		}
+		if changed {
+			v.layout(grid)
+		}
+		grid.SetRect(x, y, width, height) // mark the inner rect stale so it accounts for the border
+		return grid.GetInnerRect()
+	})
+}
+
+// layout replaces the grid's rows, columns, and items with the visible ones.
+func (v *gridVisibility) layout(grid *tview.Grid) {
+	rowIndex := v.keptTracks(len(v.rows), func(p gridPlacement) (int, int) { return p.row, p.rowSpan })
+	columnIndex := v.keptTracks(len(v.columns), func(p gridPlacement) (int, int) { return p.column, p.colSpan })
+	grid.Clear()
+	grid.SetRows(keptSizes(v.rows, rowIndex)...)
+	grid.SetColumns(keptSizes(v.columns, columnIndex)...)
+	for i, p := range v.placements {
+		if v.visible[i] {
+			// Every row and column of a visible item is kept, so only its position moves.
+			grid.AddItem(p.item, rowIndex[p.row], columnIndex[p.column], p.rowSpan, p.colSpan, p.minHeight, p.minWidth, p.focus)
+		}
+	}
+}
+
+// keptTracks returns the new index of each row (or column, as selected by span) of the grid, or
+// -1 for one that hidden items occupy and no visible item does. Tracks without any item, such as
+// deliberate gaps, are kept. declared is the number of configured tracks; items may reach past it.
+func (v *gridVisibility) keptTracks(declared int, span func(gridPlacement) (start, count int)) []int {
+	n := declared
+	for _, p := range v.placements {
+		start, count := span(p)
+		n = max(n, start+count)
+	}
+	shown, hidden := make([]bool, n), make([]bool, n)
+	for i, p := range v.placements {
+		start, count := span(p)
+		for t := start; t < start+count; t++ {
+			if v.visible[i] {
+				shown[t] = true
+			} else {
+				hidden[t] = true
+			}
+		}
+	}
+	index := make([]int, n)
+	next := 0
+	for t := range index {
+		if hidden[t] && !shown[t] {
+			index[t] = -1
+			continue
+		}
+		index[t] = next
+		next++
+	}
+	return index
+}
+
+// keptSizes returns the configured sizes of the tracks keptTracks keeps.
+func keptSizes(sizes, index []int) []int {
+	var kept []int
+	for t, size := range sizes {
+		if index[t] >= 0 {
+			kept = append(kept, size)
+		}
+	}
+	return kept
+}
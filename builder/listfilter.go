@@ -0,0 +1,125 @@
+package builder
+
+import (
+	"strings"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+// listEntry is one item of a filterable list, kept so items hidden by a filter can be restored.
+type listEntry struct {
+	mainText, secondaryText string
+	mainTag, secondaryTag   string // color tags put before the texts when shown (not matched by filters)
+	shortcut                rune
+	selected                func()
+}
+
+// filterableList is a list whose items can be narrowed by a query. It keeps every entry so items
+// hidden by a filter can be restored, and maps visible items back to their entries.
+type filterableList struct {
+	list    *tview.List
+	entries []listEntry
+	visible []int  // entry index of each item currently in the list
+	query   string // current query, lowercased
+}
+
+// newFilterableList wraps list, whose items are currently entries in order.
+func newFilterableList(list *tview.List, entries []listEntry) *filterableList {
+	visible := make([]int, len(entries))
+	for i := range visible {
+		visible[i] = i
+	}
+	return &filterableList{list: list, entries: entries, visible: visible}
+}
+
+// filter repopulates the list with the entries whose main or secondary text contains query
+// (case-insensitive); an empty query shows every entry.
+func (f *filterableList) filter(query string) {
+	f.query = strings.ToLower(query)
+	f.list.Clear()
+	f.visible = f.visible[:0]
+	for i, e := range f.entries {
+		if f.matches(e) {
+			f.list.AddItem(e.mainTag+e.mainText, e.secondaryTag+e.secondaryText, e.shortcut, e.selected)
+			f.visible = append(f.visible, i)
+		}
+	}
+}
+
+// matches reports whether e is shown under the current query.
+func (f *filterableList) matches(e listEntry) bool {
+	return f.query == "" || strings.Contains(strings.ToLower(e.mainText), f.query) || strings.Contains(strings.ToLower(e.secondaryText), f.query)
+}
+
+// appendEntry adds an entry at runtime (Context.AppendListItem), showing it only if it matches the
+// current query, so it is kept when the query changes.
+func (f *filterableList) appendEntry(mainText, secondaryText string, shortcut rune, selected func()) {
+	e := listEntry{mainText: mainText, secondaryText: secondaryText, shortcut: shortcut, selected: selected}
+	f.entries = append(f.entries, e)
+	if f.matches(e) {
+		f.list.AddItem(mainText, secondaryText, shortcut, selected)
+		f.visible = append(f.visible, len(f.entries)-1)
+	}
+}
+
+// setEntryText updates entry i's text, and its item if visible. The current filter is not
+// re-applied, so the item stays visible until the query next changes.
+func (f *filterableList) setEntryText(i int, mainText, secondaryText string) {
+	e := &f.entries[i]
+	e.mainText, e.secondaryText = mainText, secondaryText
+	for j, entry := range f.visible {
+		if entry == i {
+			f.list.SetItemText(j, e.mainTag+mainText, e.secondaryTag+secondaryText)
+			return
+		}
+	}
+}
+
+// listFilter is a filterable list waiting for its filterInput to be wired once the page is built.
+type listFilter struct {
+	list  *filterableList
+	input string // name of the inputField whose text filters the list
+}
+
+// wireListFilters connects each filterable list collected while building the page to its
+// filterInput: every change to the input's text refilters the list.
+func (b *Builder) wireListFilters(filters []listFilter, bc *BuildContext) error {
+	for _, f := range filters {
+		p, ok := b.context.GetPrimitive(f.input)
+		if !ok {
+			return bc.Errorf("filterInput: no primitive named %q", f.input)
+		}
+		input, ok := p.(*tview.InputField)
+		if !ok {
+			return bc.Errorf("filterInput: primitive %q is %T, not an inputField", f.input, p)
+		}
+		input.SetChangedFunc(f.list.filter)
+	}
+	return nil
+}
+
+// bindTypeToFilter lets a filterable list without a filterInput filter itself: printable keys
+// typed while it has focus extend the query, Backspace shortens it, and Escape clears it (Escape
+// passes through when there is no query). Other keys go to any capture the list already had.
+func bindTypeToFilter(f *filterableList) {
+	prev := f.list.GetInputCapture()
+	var query []rune
+	f.list.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		switch {
+		case event.Key() == tcell.KeyRune && event.Modifiers()&(tcell.ModCtrl|tcell.ModAlt) == 0:
+			query = append(query, event.Rune())
+		case (event.Key() == tcell.KeyBackspace || event.Key() == tcell.KeyBackspace2) && len(query) > 0:
+			query = query[:len(query)-1]
+		case event.Key() == tcell.KeyEscape && len(query) > 0:
+			query = nil
+		default:
+			if prev != nil {
+				return prev(event)
+			}
+			return event
+		}
+		f.filter(string(query))
+		return nil
+	})
+}
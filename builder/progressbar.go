@@ -0,0 +1,68 @@
+package builder
+
+import (
+	"strconv"
+
+	"github.com/cassdeckard/tviewyaml/config"
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+// buildProgressBar turns a Box into a horizontal progress bar. On every draw it reads the
+// valueFromState key (0-100) and fills that share of the inner width with fillColor; the rest
+// is drawn with emptyColor. State changes redraw the application, so no binding is needed.
+func (b *Builder) buildProgressBar(box *tview.Box, prim *config.Primitive, bc *BuildContext) error {
+	if prim.ValueFromState == "" {
+		return bc.Errorf("progressBar requires valueFromState")
+	}
+	fill := tview.Styles.ContrastBackgroundColor
+	if prim.FillColor != "" {
+		fill = b.context.Colors.Parse(prim.FillColor)
+	}
+	empty := tview.Styles.PrimitiveBackgroundColor
+	if prim.EmptyColor != "" {
+		empty = b.context.Colors.Parse(prim.EmptyColor)
+	}
+	key, border := prim.ValueFromState, prim.Border
+	box.SetDrawFunc(func(screen tcell.Screen, x, y, width, height int) (int, int, int, int) {
+		if border {
+			x, y, width, height = x+1, y+1, width-2, height-2
+		}
+		if width <= 0 || height <= 0 {
+			return x, y, max(width, 0), max(height, 0)
+		}
+		var percent float64
+		if v, ok := b.context.GetState(key); ok {
+			percent = progressPercent(v)
+		}
+		filled := int(float64(width)*percent/100 + 0.5)
+		for col := 0; col < width; col++ {
+			style := tcell.StyleDefault.Background(empty)
+			if col < filled {
+				style = tcell.StyleDefault.Background(fill)
+			}
+			for row := 0; row < height; row++ {
+				screen.SetContent(x+col, y+row, ' ', nil, style)
+			}
+		}
+		return x, y, width, height
+	})
+	return nil
+}
+
+// progressPercent converts a state value (number or numeric string) to a percentage clamped to 0-100.
+// Values that are not numbers count as 0.
+func progressPercent(v interface{}) float64 {
+	var f float64
+	switch t := v.(type) {
+	case int:
+		f = float64(t)
+	case int64:
+		f = float64(t)
+	case float64:
+		f = t
+	case string:
+		f, _ = strconv.ParseFloat(t, 64)
+	}
+	return min(max(f, 0), 100)
+}
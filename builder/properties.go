@@ -2,6 +2,7 @@ package builder
 
 import (
 	"fmt"
+	"regexp"
 	"strings"
 
 	"github.com/gdamore/tcell/v2"
@@ -28,6 +29,8 @@ func NewPropertyMapper(ctx *template.Context, executor *template.Executor) *Prop
 
 // ApplyProperties applies configuration properties to a primitive
 func (pm *PropertyMapper) ApplyProperties(primitive tview.Primitive, prim *config.Primitive) error {
+	pm.applyTheme(primitive)
+
 	// Common properties that apply to Box (base of most primitives)
 	if b, ok := primitive.(interface {
 		SetBorder(bool) *tview.Box
@@ -66,46 +69,116 @@ func (pm *PropertyMapper) ApplyProperties(primitive tview.Primitive, prim *confi
 	return nil
 }
 
-func (pm *PropertyMapper) applyTextViewProperties(tv *tview.TextView, prim *config.Primitive) error {
-	if prim.Text != "" {
-		if strings.Contains(prim.Text, "{{") && strings.Contains(prim.Text, "}}") && pm.executor != nil {
-			// Template syntax: evaluate once and register for deferred refresh on key events
-			result, err := pm.executor.EvaluateToString(prim.Text)
-			if err != nil {
-				return fmt.Errorf("template evaluation failed: %w", err)
+// applyTheme applies the app theme's colors (if any) to a primitive. It runs before the
+// primitive's own properties so that colors set on the primitive take precedence.
+func (pm *PropertyMapper) applyTheme(primitive tview.Primitive) {
+	theme := pm.context.Theme()
+	if theme == nil {
+		return
+	}
+	if b, ok := primitive.(interface {
+		SetBorderColor(tcell.Color) *tview.Box
+		SetTitleColor(tcell.Color) *tview.Box
+		SetBackgroundColor(tcell.Color) *tview.Box
+	}); ok {
+		if theme.BorderColor != "" {
+			b.SetBorderColor(pm.colorHelper.Parse(theme.BorderColor))
+		}
+		if theme.TitleColor != "" {
+			b.SetTitleColor(pm.colorHelper.Parse(theme.TitleColor))
+		}
+		if theme.BackgroundColor != "" {
+			b.SetBackgroundColor(pm.colorHelper.Parse(theme.BackgroundColor))
+		}
+	}
+	if theme.TextColor != "" {
+		switch v := primitive.(type) {
+		case *tview.TextView:
+			v.SetTextColor(pm.colorHelper.Parse(theme.TextColor))
+		case *tview.List:
+			v.SetMainTextColor(pm.colorHelper.Parse(theme.TextColor))
+		}
+	}
+	// Selection colors are set on each list and table; tview.Styles has no selection colors
+	switch v := primitive.(type) {
+	case *tview.List:
+		if theme.SelectionColor != "" {
+			v.SetSelectedBackgroundColor(pm.colorHelper.Parse(theme.SelectionColor))
+		}
+		if theme.SelectionTextColor != "" {
+			v.SetSelectedTextColor(pm.colorHelper.Parse(theme.SelectionTextColor))
+		}
+	case *tview.Table:
+		if theme.SelectionColor != "" || theme.SelectionTextColor != "" {
+			style := tcell.StyleDefault
+			if theme.SelectionColor != "" {
+				style = style.Background(pm.colorHelper.Parse(theme.SelectionColor))
 			}
-			tv.SetText(result)
-			keys := pm.executor.ExtractBindStateKeys(prim.Text)
-			templateStr := prim.Text
-			for _, key := range keys {
-				pm.context.RegisterBoundView(key, template.BoundView{
-					Refresh: func() string {
-						s, err := pm.executor.EvaluateToString(templateStr)
-						if err != nil {
-							return ""
-						}
-						return s
-					},
-					SetText: func(s string) { tv.SetText(s) },
-				})
+			if theme.SelectionTextColor != "" {
+				style = style.Foreground(pm.colorHelper.Parse(theme.SelectionTextColor))
 			}
-		} else {
-			tv.SetText(prim.Text)
+			v.SetSelectedStyle(style)
 		}
 	}
+}
+
+func (pm *PropertyMapper) applyTextViewProperties(tv *tview.TextView, prim *config.Primitive) error {
+	text := prim.Text
+	if len(prim.Regions.Items) > 0 {
+		highlight := ""
+		if theme := pm.context.Theme(); theme != nil {
+			highlight = theme.HighlightColor
+		}
+		text = regionsText(prim.Text, prim.Regions.Items, highlight)
+	}
+	if prim.MaxWidth > 0 {
+		// A centered text block; the builder places the view in a centered column (see centerColumn)
+		tv.SetWrap(true).SetWordWrap(true).SetTextAlign(tview.AlignCenter)
+	}
 	if prim.TextAlign != "" {
 		tv.SetTextAlign(template.ParseAlignment(prim.TextAlign))
 	}
+	setText := func(s string) { tv.SetText(s) }
+	if prim.FillLine {
+		align := tview.AlignLeft
+		if prim.MaxWidth > 0 {
+			align = tview.AlignCenter
+		}
+		if prim.TextAlign != "" {
+			align = template.ParseAlignment(prim.TextAlign)
+		}
+		setText = fillLines(tv, align)
+	}
+	if text != "" {
+		if err := pm.applyBoundText(text, setText); err != nil {
+			return err
+		}
+	}
 	if prim.TextColor != "" {
 		tv.SetTextColor(pm.colorHelper.Parse(prim.TextColor))
 	}
-	// Enable dynamic colors and regions if specified
-	if prim.DynamicColors {
+	// Enable dynamic colors and regions if specified (structured regions need both)
+	if prim.DynamicColors || len(prim.Regions.Items) > 0 {
 		tv.SetDynamicColors(true)
 	}
-	if prim.Regions {
+	if prim.Regions.Enabled {
 		tv.SetRegions(true)
 	}
+	if prim.ToggleHighlights != nil {
+		tv.SetToggleHighlights(*prim.ToggleHighlights)
+	}
+	if prim.MaxLines > 0 {
+		tv.SetMaxLines(prim.MaxLines)
+	}
+	if prim.Scrollable != nil {
+		tv.SetScrollable(*prim.Scrollable)
+	}
+	// The initial scroll position is applied after the text is set
+	if prim.ScrollToEnd {
+		tv.ScrollToEnd()
+	} else if prim.ScrollTo > 0 {
+		tv.ScrollTo(prim.ScrollTo, 0)
+	}
 	if prim.OnDone != "" && pm.executor != nil {
 		onDoneExpr := prim.OnDone
 		tv.SetDoneFunc(func(key tcell.Key) {
@@ -121,31 +194,50 @@ func (pm *PropertyMapper) applyTextViewProperties(tv *tview.TextView, prim *conf
 				cb()
 			}
 		})
-	} else if prim.Regions {
-		// Add region navigation handlers (only when onDone is not set)
+	} else if prim.Regions.Enabled {
+		// Add region navigation handlers (only when onDone is not set). Tab and Backtab cycle
+		// through the regions in the order they appear in the text.
+		ids := regionIDs(text)
 		tv.SetDoneFunc(func(key tcell.Key) {
+			if len(ids) == 0 {
+				return
+			}
 			currentSelection := tv.GetHighlights()
 			if key == tcell.KeyEnter {
 				if len(currentSelection) > 0 {
 					tv.Highlight()
 				} else {
-					tv.Highlight("0").ScrollToHighlight()
+					tv.Highlight(ids[0]).ScrollToHighlight()
 				}
 			} else if len(currentSelection) > 0 {
 				index := 0
-				fmt.Sscanf(currentSelection[0], "%d", &index)
+				for i, id := range ids {
+					if id == currentSelection[0] {
+						index = i
+						break
+					}
+				}
 				if key == tcell.KeyTab {
-					index = (index + 1) % 3
+					index = (index + 1) % len(ids)
 				} else if key == tcell.KeyBacktab {
-					index = (index - 1 + 3) % 3
+					index = (index - 1 + len(ids)) % len(ids)
 				} else {
 					return
 				}
-				tv.Highlight(fmt.Sprintf("%d", index)).ScrollToHighlight()
+				tv.Highlight(ids[index]).ScrollToHighlight()
 			}
 		})
 	}
-	if prim.Regions && prim.OnHighlighted != "" && pm.executor != nil {
+	// onChanged is attached after the initial text is set so building the view does not fire it.
+	// tview calls the changed func on its own goroutine, so the callback is queued onto the main one.
+	if prim.OnChanged != "" && pm.executor != nil {
+		cb, err := pm.executor.ExecuteCallback(prim.OnChanged)
+		if err != nil {
+			return fmt.Errorf("failed to execute onChanged callback: %w", err)
+		}
+		tv.SetChangedFunc(func() { pm.context.QueueUpdateDraw(cb) })
+	}
+	if prim.Regions.Enabled && prim.OnHighlighted != "" && pm.executor != nil {
 		onHighlightedExpr := prim.OnHighlighted
 		tv.SetHighlightedFunc(func(added, removed, remaining []string) {
 			if len(added) == 0 {
@@ -160,11 +252,127 @@ func (pm *PropertyMapper) applyTextViewProperties(tv *tview.TextView, prim *conf
 	return nil
 }
 
+// regionsText appends structured regions to text as region-tagged, space-separated items,
+// e.g. `["home"][yellow]Home[-][""]`. Region text is escaped so brackets display literally.
+// Regions without a color use defaultColor, if set.
+func regionsText(text string, regions []config.Region, defaultColor string) string {
+	items := make([]string, 0, len(regions))
+	for _, r := range regions {
+		label := tview.Escape(r.Text)
+		color := r.Color
+		if color == "" {
+			color = defaultColor
+		}
+		if color != "" {
+			label = fmt.Sprintf("[%s]%s[-]", color, label)
+		}
+		items = append(items, fmt.Sprintf(`["%s"]%s[""]`, r.ID, label))
+	}
+	line := strings.Join(items, " ")
+	if text == "" {
+		return line
+	}
+	return strings.TrimSuffix(text, "\n") + "\n" + line
+}
+
+// regionTagPattern matches a region start tag (["id"]) in tagged text.
+var regionTagPattern = regexp.MustCompile(`\["([^"\]]+)"\]`)
+
+// regionIDs returns the distinct region IDs tagged in text, in order of appearance.
+func regionIDs(text string) []string {
+	var ids []string
+	seen := make(map[string]bool)
+	for _, m := range regionTagPattern.FindAllStringSubmatch(text, -1) {
+		if !seen[m[1]] {
+			seen[m[1]] = true
+			ids = append(ids, m[1])
+		}
+	}
+	return ids
+}
+
+// applyBoundText passes text to set. Template syntax is evaluated once and registered
+// for deferred refresh, so set runs again whenever a bindState key in text changes.
+func (pm *PropertyMapper) applyBoundText(text string, set func(string)) error {
+	if !strings.Contains(text, "{{") || !strings.Contains(text, "}}") || pm.executor == nil {
+		set(text)
+		return nil
+	}
+	result, err := pm.executor.EvaluateToString(text)
+	if err != nil {
+		return fmt.Errorf("template evaluation failed: %w", err)
+	}
+	set(result)
+	for _, key := range pm.executor.ExtractBindStateKeys(text) {
+		pm.context.RegisterBoundView(key, template.BoundView{
+			Refresh: func() string {
+				s, err := pm.executor.EvaluateToString(text)
+				if err != nil {
+					return ""
+				}
+				return s
+			},
+			SetText: set,
+		})
+	}
+	return nil
+}
+
 func (pm *PropertyMapper) applyButtonProperties(btn *tview.Button, prim *config.Primitive) error {
-	// Button label is set in factory
+	// The factory sets the raw label; re-apply it so template syntax is evaluated and bound
+	if prim.Label != "" {
+		if err := pm.applyBoundText(prim.Label, func(s string) { btn.SetLabel(s) }); err != nil {
+			return err
+		}
+	}
+	// Button.Draw paints its background from the style, so Box.SetBackgroundColor would be overwritten
+	if prim.BackgroundColor != "" {
+		btn.SetStyle(tcell.StyleDefault.
+			Background(pm.colorHelper.Parse(prim.BackgroundColor)).
+			Foreground(tview.Styles.PrimaryTextColor))
+	}
+	if prim.LabelColor != "" {
+		btn.SetLabelColor(pm.colorHelper.Parse(prim.LabelColor))
+	}
+	// tview's disabled buttons ignore Enter and clicks and draw with the dimmed disabled style
+	pm.applyDisabled(prim.Disabled, prim.DisabledWhen, func(disabled bool) { btn.SetDisabled(disabled) })
 	return nil
 }
 
+// applyDisabled sets the initial disabled state and, for disabledWhen, re-applies it whenever
+// the state key changes (on the main goroutine, via RefreshDirtyBoundViews).
+func (pm *PropertyMapper) applyDisabled(disabled *bool, disabledWhen string, set func(bool)) {
+	if disabled != nil {
+		set(*disabled)
+	}
+	if disabledWhen == "" {
+		return
+	}
+	if v, ok := pm.context.GetState(disabledWhen); ok {
+		set(stateTruthy(v))
+	}
+	pm.context.OnStateChange(disabledWhen, func(v interface{}) { set(stateTruthy(v)) })
+}
+
+// stateTruthy reports whether a state value counts as true: booleans as-is, numbers when
+// non-zero, and strings unless empty, "false", or "0".
+func stateTruthy(v interface{}) bool {
+	switch t := v.(type) {
+	case nil:
+		return false
+	case bool:
+		return t
+	case int:
+		return t != 0
+	case float64:
+		return t != 0
+	case string:
+		return t != "" && t != "false" && t != "0"
+	default:
+		return true
+	}
+}
+
 func (pm *PropertyMapper) applyInputFieldProperties(input *tview.InputField, prim *config.Primitive) error {
 	if prim.Label != "" {
 		input.SetLabel(prim.Label)
@@ -172,6 +380,10 @@ func (pm *PropertyMapper) applyInputFieldProperties(input *tview.InputField, pri
 	if prim.Text != "" {
 		input.SetText(prim.Text)
 	}
+	// Unset keeps tview's width 0, which draws the field to the right edge
+	if prim.FieldWidth > 0 {
+		input.SetFieldWidth(prim.FieldWidth)
+	}
 	if prim.OnDone != "" && pm.executor != nil {
 		onDoneExpr := prim.OnDone
 		input.SetDoneFunc(func(key tcell.Key) {
@@ -198,10 +410,23 @@ func (pm *PropertyMapper) applyCheckboxProperties(cb *tview.Checkbox, prim *conf
 	if prim.Checked {
 		cb.SetChecked(true)
 	}
-	// Add changed handler to trigger redraws (needed for standalone checkboxes)
+	// onChanged runs with __checked set to the new state; otherwise the changed handler only
+	// triggers a redraw (needed for standalone checkboxes)
+	if prim.OnChanged != "" && pm.executor != nil {
+		onChanged, err := pm.executor.ExecuteCallback(prim.OnChanged)
+		if err != nil {
+			return fmt.Errorf("failed to execute onChanged callback: %w", err)
+		}
+		cb.SetChangedFunc(func(checked bool) {
+			pm.context.SetStateDirect("__checked", checked)
+			onChanged()
+		})
+		return nil
+	}
+	// Handlers run on the event loop, where App.Draw (which waits for the loop) would deadlock
 	cb.SetChangedFunc(func(checked bool) {
 		if pm.context != nil && pm.context.App != nil {
-			pm.context.App.Draw()
+			pm.context.App.ForceDraw()
 		}
 	})
 	return nil
@@ -214,6 +439,25 @@ func (pm *PropertyMapper) applyDropDownProperties(dd *tview.DropDown, prim *conf
 	if len(prim.Options) > 0 {
 		dd.SetOptions(prim.Options, nil)
 	}
+	// onChanged runs with __selectedOption (text) and __selectedOptionIndex set to the new selection;
+	// otherwise the selected handler only triggers a redraw (needed for standalone drop-downs)
+	if prim.OnChanged != "" && pm.executor != nil {
+		onChanged, err := pm.executor.ExecuteCallback(prim.OnChanged)
+		if err != nil {
+			return fmt.Errorf("failed to execute onChanged callback: %w", err)
+		}
+		dd.SetSelectedFunc(func(text string, index int) {
+			pm.context.SetStateDirect("__selectedOption", text)
+			pm.context.SetStateDirect("__selectedOptionIndex", index)
+			onChanged()
+		})
+		return nil
+	}
+	dd.SetSelectedFunc(func(text string, index int) {
+		if pm.context != nil && pm.context.App != nil {
+			pm.context.App.ForceDraw()
+		}
+	})
 	return nil
 }
 
@@ -239,12 +483,17 @@ func (pm *PropertyMapper) applyTableProperties(table *tview.Table, prim *config.
 }
 
 func (pm *PropertyMapper) applyListProperties(list *tview.List, prim *config.Primitive) error {
-	// List items are handled in builder
+	// List items, currentItem, and offset are handled in builder (they need the items to exist)
+	if prim.SelectedFocusOnly {
+		list.SetSelectedFocusOnly(true)
+	}
 	return nil
 }
 
 // ApplyPageProperties applies page-level properties to a primitive
 func (pm *PropertyMapper) ApplyPageProperties(primitive tview.Primitive, cfg *config.PageConfig) error {
+	pm.applyTheme(primitive)
+
 	// Common properties
 	if b, ok := primitive.(interface {
 		SetBorder(bool) *tview.Box
@@ -1,10 +1,14 @@
 package builder
 
 import (
+	"reflect"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/cassdeckard/tviewyaml/config"
 	"github.com/cassdeckard/tviewyaml/template"
+	"github.com/gdamore/tcell/v2"
 	"github.com/rivo/tview"
 )
 
@@ -22,7 +26,7 @@ func TestApplyTextViewProperties_OnHighlighted(t *testing.T) {
 
 	prim := &config.Primitive{
 		Type:          "textView",
-		Regions:       true,
+		Regions:       config.Regions{Enabled: true},
 		OnHighlighted: `{{ showNotification "highlighted" }}`,
 	}
 
@@ -34,6 +38,79 @@ func TestApplyTextViewProperties_OnHighlighted(t *testing.T) {
 	// tview draws after a highlight change (tested via acceptance if needed).
 }
 
+func TestApplyTextViewProperties_ToggleHighlights(t *testing.T) {
+	ctx := template.NewContext(tview.NewApplication(), tview.NewPages())
+	pm := NewPropertyMapper(ctx, template.NewExecutor(ctx, template.NewFunctionRegistry()))
+
+	on, off := true, false
+	tests := []struct {
+		name   string
+		toggle *bool
+		want   []string
+	}{
+		{name: "unset keeps tview default", toggle: nil, want: []string{"a"}},
+		{name: "on clears on reselect", toggle: &on, want: nil},
+		{name: "off keeps highlight", toggle: &off, want: []string{"a"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tv := tview.NewTextView()
+			prim := &config.Primitive{Type: "textView", Regions: config.Regions{Enabled: true}, Text: `["a"]Region A[""]`, ToggleHighlights: tt.toggle}
+			if err := pm.ApplyProperties(tv, prim); err != nil {
+				t.Fatalf("ApplyProperties: %v", err)
+			}
+			tv.Highlight("a")
+			tv.Highlight("a")
+			if got := tv.GetHighlights(); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("highlights after selecting twice = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestApplyTextViewProperties_StructuredRegions(t *testing.T) {
+	ctx := template.NewContext(tview.NewApplication(), tview.NewPages())
+	pm := NewPropertyMapper(ctx, template.NewExecutor(ctx, template.NewFunctionRegistry()))
+
+	tv := tview.NewTextView()
+	prim := &config.Primitive{
+		Type: "textView",
+		Text: "Go to:",
+		Regions: config.Regions{Enabled: true, Items: []config.Region{
+			{ID: "home", Text: "Home", Color: "yellow"},
+			{ID: "list", Text: "[List]"},
+			{ID: "form", Text: "Form"},
+			{ID: "help", Text: "Help"},
+		}},
+	}
+	if err := pm.ApplyProperties(tv, prim); err != nil {
+		t.Fatalf("ApplyProperties: %v", err)
+	}
+
+	want := "Go to:\n" + `["home"][yellow]Home[-][""] ["list"][List[][""] ["form"]Form[""] ["help"]Help[""]`
+	if got := tv.GetText(false); got != want {
+		t.Errorf("text = %q, want %q", got, want)
+	}
+	if got := tv.GetText(true); got != "Go to:\nHome [List] Form Help" {
+		t.Errorf("displayed text = %q", got)
+	}
+
+	// Tab cycles through all four regions (not a fixed three) and wraps; Backtab goes back
+	handler := tv.InputHandler()
+	done := func(key tcell.Key) { handler(tcell.NewEventKey(key, 0, tcell.ModNone), func(tview.Primitive) {}) }
+	done(tcell.KeyEnter)
+	var visited []string
+	for i := 0; i < 4; i++ {
+		done(tcell.KeyTab)
+		visited = append(visited, tv.GetHighlights()...)
+	}
+	done(tcell.KeyBacktab)
+	visited = append(visited, tv.GetHighlights()...)
+	if want := []string{"list", "form", "help", "home", "help"}; !reflect.DeepEqual(visited, want) {
+		t.Errorf("highlights while tabbing = %v, want %v", visited, want)
+	}
+}
+
 func TestBuildFlex_TextViewWithOnHighlighted(t *testing.T) {
 	app := tview.NewApplication()
 	pages := tview.NewPages()
@@ -47,7 +124,7 @@ func TestBuildFlex_TextViewWithOnHighlighted(t *testing.T) {
 			{
 				Primitive: &config.Primitive{
 					Type:          "textView",
-					Regions:       true,
+					Regions:       config.Regions{Enabled: true},
 					DynamicColors: true,
 					Text:          `["slide1"]Slide 1[""] ["slide2"]Slide 2[""]`,
 					OnHighlighted: `{{ switchToPage "main" }}`,
@@ -72,3 +149,194 @@ func TestBuildFlex_TextViewWithOnHighlighted(t *testing.T) {
 		t.Errorf("GetItemCount() = %d, want 1", flex.GetItemCount())
 	}
 }
+
+func TestApplyTextViewProperties_OnChanged(t *testing.T) {
+	screen := tcell.NewSimulationScreen("")
+	app := tview.NewApplication().SetScreen(screen)
+	pages := tview.NewPages()
+	ctx := template.NewContext(app, pages)
+	registry := template.NewFunctionRegistry()
+	executor := template.NewExecutor(ctx, registry)
+	pm := NewPropertyMapper(ctx, executor)
+
+	tv := tview.NewTextView()
+	prim := &config.Primitive{
+		Type:      "textView",
+		Text:      "initial",
+		OnChanged: `{{ showNotification "changed" }}`,
+	}
+	if err := pm.ApplyProperties(tv, prim); err != nil {
+		t.Fatalf("ApplyProperties: %v", err)
+	}
+	if _, ok := ctx.GetState("notification"); ok {
+		t.Fatal("onChanged should not fire while building the view")
+	}
+
+	app.SetRoot(tv, true)
+	go func() { _ = app.Run() }()
+	defer app.Stop()
+
+	// Hold the main goroutine busy: the callback must wait for it instead of running on tview's goroutine
+	started, release := make(chan struct{}), make(chan struct{})
+	go app.QueueUpdate(func() {
+		close(started)
+		<-release
+	})
+	select {
+	case <-started:
+	case <-time.After(2 * time.Second):
+		t.Fatal("app did not run the queued update")
+	}
+	tv.SetText("updated")
+	time.Sleep(50 * time.Millisecond)
+	if _, ok := ctx.GetState("notification"); ok {
+		t.Fatal("onChanged ran off the main goroutine")
+	}
+	close(release)
+
+	deadline := time.Now().Add(time.Second)
+	for time.Now().Before(deadline) {
+		if v, ok := ctx.GetState("notification"); ok && v == "changed" {
+			return
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	t.Error("onChanged callback did not run after SetText")
+}
+
+func TestApplyInputFieldProperties_FieldWidth(t *testing.T) {
+	tests := []struct {
+		name  string
+		prim  config.Primitive
+		start int
+		want  int
+	}{
+		{"explicit width", config.Primitive{Type: "inputField", FieldWidth: 20}, 5, 20},
+		{"unset leaves width", config.Primitive{Type: "inputField"}, 5, 5},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := template.NewContext(tview.NewApplication(), tview.NewPages())
+			pm := NewPropertyMapper(ctx, template.NewExecutor(ctx, template.NewFunctionRegistry()))
+			input := tview.NewInputField().SetFieldWidth(tt.start)
+			if err := pm.ApplyProperties(input, &tt.prim); err != nil {
+				t.Fatalf("ApplyProperties: %v", err)
+			}
+			if got := input.GetFieldWidth(); got != tt.want {
+				t.Errorf("GetFieldWidth() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestApplyButtonProperties_BoundLabelAndColors(t *testing.T) {
+	app := tview.NewApplication()
+	pages := tview.NewPages()
+	ctx := template.NewContext(app, pages)
+	registry := template.NewFunctionRegistry()
+	executor := template.NewExecutor(ctx, registry)
+	pm := NewPropertyMapper(ctx, executor)
+
+	ctx.SetStateDirect("runLabel", "Start")
+	ctx.RefreshDirtyBoundViews()
+	prim := &config.Primitive{
+		Type:            "button",
+		Label:           "{{ bindState runLabel }}",
+		BackgroundColor: "blue",
+		LabelColor:      "yellow",
+	}
+	btn := tview.NewButton(prim.Label)
+	if err := pm.ApplyProperties(btn, prim); err != nil {
+		t.Fatalf("ApplyProperties: %v", err)
+	}
+	if got := btn.GetLabel(); got != "Start" {
+		t.Errorf("label = %q, want %q", got, "Start")
+	}
+
+	ctx.SetStateDirect("runLabel", "Stop")
+	ctx.RefreshDirtyBoundViews()
+	if got := btn.GetLabel(); got != "Stop" {
+		t.Errorf("label after state change = %q, want %q", got, "Stop")
+	}
+
+	screen := tcell.NewSimulationScreen("")
+	if err := screen.Init(); err != nil {
+		t.Fatal(err)
+	}
+	defer screen.Fini()
+	screen.SetSize(10, 1)
+	btn.SetRect(0, 0, 10, 1)
+	btn.Draw(screen)
+	_, _, style, _ := screen.GetContent(0, 0)
+	if _, bg, _ := style.Decompose(); bg != tcell.ColorBlue {
+		t.Errorf("background = %v, want blue", bg)
+	}
+	_, _, style, _ = screen.GetContent(3, 0)
+	if fg, _, _ := style.Decompose(); fg != tcell.ColorYellow {
+		t.Errorf("label color = %v, want yellow", fg)
+	}
+}
+
+func TestApplyDropDownProperties_StandaloneRedraw(t *testing.T) {
+	screen := tcell.NewSimulationScreen("")
+	app := tview.NewApplication().SetScreen(screen)
+	ctx := template.NewContext(app, tview.NewPages())
+	pm := NewPropertyMapper(ctx, template.NewExecutor(ctx, template.NewFunctionRegistry()))
+
+	dd := tview.NewDropDown()
+	prim := &config.Primitive{Type: "dropdown", Label: "Size ", Options: []string{"Small", "Large"}}
+	if err := pm.ApplyProperties(dd, prim); err != nil {
+		t.Fatalf("ApplyProperties: %v", err)
+	}
+
+	draws := make(chan struct{}, 100)
+	app.SetAfterDrawFunc(func(tcell.Screen) {
+		select {
+		case draws <- struct{}{}:
+		default:
+		}
+	})
+	flex := tview.NewFlex().SetDirection(tview.FlexRow).
+		AddItem(dd, 1, 0, true).
+		AddItem(tview.NewTextView().SetText("below"), 0, 1, false)
+	app.SetRoot(flex, true)
+	screen.SetSize(20, 4)
+	go func() { _ = app.Run() }()
+	defer app.Stop()
+	waitDraw := func(what string) {
+		t.Helper()
+		select {
+		case <-draws:
+		case <-time.After(2 * time.Second):
+			t.Fatalf("no draw after %s", what)
+		}
+	}
+	row := func(y int) string {
+		var sb strings.Builder
+		for x := 0; x < 20; x++ {
+			r, _, _, _ := screen.GetContent(x, y)
+			sb.WriteRune(r)
+		}
+		return sb.String()
+	}
+	waitDraw("start")
+
+	// QueueUpdate does not draw by itself; the draw comes from the selected handler
+	app.QueueUpdate(func() { dd.SetCurrentOption(1) })
+	waitDraw("changing the selection")
+	if got := row(0); !strings.Contains(got, "Large") {
+		t.Errorf("row 0 = %q, want the new selection", got)
+	}
+
+	// Opening the list draws it over the text view below the drop-down
+	screen.InjectKey(tcell.KeyEnter, 0, tcell.ModNone)
+	waitDraw("opening the list")
+	app.QueueUpdate(func() {
+		if !dd.IsOpen() {
+			t.Error("drop-down not open after Enter")
+		}
+		if got := row(1); !strings.Contains(got, "Small") {
+			t.Errorf("row 1 = %q, want the open list", got)
+		}
+	})
+}
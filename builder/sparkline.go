@@ -0,0 +1,87 @@
+package builder
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/cassdeckard/tviewyaml/config"
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+// sparkBlocks are the partial block characters for one to eight eighths of a cell.
+var sparkBlocks = []rune{'▁', '▂', '▃', '▄', '▅', '▆', '▇', '█'}
+
+// buildSparkline turns a Box into a sparkline. On every draw it reads the valueFromState key
+// (comma-separated numbers) and draws one column per value, newest on the right, scaled so the
+// largest value fills maxHeight rows (default: the inner height) at the bottom of the box.
+func (b *Builder) buildSparkline(box *tview.Box, prim *config.Primitive, bc *BuildContext) error {
+	if prim.ValueFromState == "" {
+		return bc.Errorf("sparkline requires valueFromState")
+	}
+	color := tview.Styles.PrimaryTextColor
+	if prim.FillColor != "" {
+		color = b.context.Colors.Parse(prim.FillColor)
+	}
+	style := tcell.StyleDefault.Foreground(color).Background(tview.Styles.PrimitiveBackgroundColor)
+	key, border, maxHeight := prim.ValueFromState, prim.Border, prim.MaxHeight
+	box.SetDrawFunc(func(screen tcell.Screen, x, y, width, height int) (int, int, int, int) {
+		if border {
+			x, y, width, height = x+1, y+1, width-2, height-2
+		}
+		if width <= 0 || height <= 0 {
+			return x, y, max(width, 0), max(height, 0)
+		}
+		var values []float64
+		if v, ok := b.context.GetState(key); ok {
+			values = sparklineValues(v)
+		}
+		if len(values) > width {
+			values = values[len(values)-width:]
+		}
+		rows := height
+		if maxHeight > 0 {
+			rows = min(maxHeight, height)
+		}
+		top := 0.0
+		for _, v := range values {
+			top = max(top, v)
+		}
+		if top <= 0 {
+			return x, y, width, height
+		}
+		bottom := y + height - 1
+		for i, v := range values {
+			col := x + width - len(values) + i
+			eighths := int(max(v, 0)/top*float64(rows*8) + 0.5)
+			for row := 0; eighths > 0; row++ {
+				block := sparkBlocks[min(eighths, 8)-1]
+				screen.SetContent(col, bottom-row, block, nil, style)
+				eighths -= 8
+			}
+		}
+		return x, y, width, height
+	})
+	return nil
+}
+
+// sparklineValues parses a sparkline series: a comma-separated string of numbers or a slice
+// of numbers. Entries that are not numbers are skipped.
+func sparklineValues(v interface{}) []float64 {
+	var values []float64
+	switch t := v.(type) {
+	case string:
+		for _, field := range strings.Split(t, ",") {
+			if f, err := strconv.ParseFloat(strings.TrimSpace(field), 64); err == nil {
+				values = append(values, f)
+			}
+		}
+	case []float64:
+		values = append(values, t...)
+	case []int:
+		for _, n := range t {
+			values = append(values, float64(n))
+		}
+	}
+	return values
+}
@@ -0,0 +1,95 @@
+package builder
+
+import (
+	"sync/atomic"
+	"time"
+
+	"github.com/cassdeckard/tviewyaml/config"
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+// defaultSpinnerFrames are the frames a spinner cycles through when frames is not set.
+var defaultSpinnerFrames = []string{"⠋", "⠙", "⠹", "⠸", "⠼", "⠴", "⠦", "⠧", "⠇", "⠏"}
+
+// defaultSpinnerInterval is the time between spinner frames when interval is not set.
+const defaultSpinnerInterval = 100 * time.Millisecond
+
+// spinnerTickKey is the state key spinners touch on every frame so the refresh loop redraws.
+const spinnerTickKey = "__spinnerFrame"
+
+// buildSpinner turns a Box into a busy indicator. While the valueFromState key is truthy it draws
+// the current frame followed by text; otherwise it draws nothing. A goroutine advances the frame
+// every interval and marks spinnerTickKey dirty, so redraws go through the coalescing refresh loop.
+// The goroutine starts when the key turns truthy and exits when it turns false or the app stops.
+func (b *Builder) buildSpinner(box *tview.Box, prim *config.Primitive, bc *BuildContext) error {
+	if prim.ValueFromState == "" {
+		return bc.Errorf("spinner requires valueFromState")
+	}
+	interval := defaultSpinnerInterval
+	if prim.Interval != "" {
+		d, err := time.ParseDuration(prim.Interval)
+		if err != nil || d <= 0 {
+			return bc.Errorf("spinner interval %q must be a positive duration (e.g. 100ms)", prim.Interval)
+		}
+		interval = d
+	}
+	frames := prim.Frames
+	if len(frames) == 0 {
+		frames = defaultSpinnerFrames
+	}
+	color := tview.Styles.PrimaryTextColor
+	if prim.TextColor != "" {
+		color = b.context.Colors.Parse(prim.TextColor)
+	}
+	key, text, border := prim.ValueFromState, prim.Text, prim.Border
+
+	var frame atomic.Int64
+	var stop chan struct{} // non-nil while the ticker goroutine runs; only touched on the main goroutine
+	setBusy := func(busy bool) {
+		switch {
+		case busy && stop == nil:
+			stop = make(chan struct{})
+			done := stop
+			b.context.Go(func() {
+				ticker := time.NewTicker(interval)
+				defer ticker.Stop()
+				for {
+					select {
+					case <-done:
+						return
+					case <-b.context.Done():
+						return
+					case <-ticker.C:
+						b.context.SetStateDirect(spinnerTickKey, frame.Add(1))
+					}
+				}
+			})
+		case !busy && stop != nil:
+			close(stop)
+			stop = nil
+		}
+	}
+	if v, ok := b.context.GetState(key); ok {
+		setBusy(stateTruthy(v))
+	}
+	b.context.OnStateChange(key, func(v interface{}) { setBusy(stateTruthy(v)) })
+
+	box.SetDrawFunc(func(screen tcell.Screen, x, y, width, height int) (int, int, int, int) {
+		if border {
+			x, y, width, height = x+1, y+1, width-2, height-2
+		}
+		if width <= 0 || height <= 0 {
+			return x, y, max(width, 0), max(height, 0)
+		}
+		if v, ok := b.context.GetState(key); ok && stateTruthy(v) {
+			label := frames[int(frame.Load()%int64(len(frames)))]
+			if text != "" {
+				label += " " + text
+			}
+			tview.Print(screen, tview.Escape(label), x, y, width, tview.AlignLeft, color)
+		}
+		return x, y, width, height
+	})
+	return nil
+}
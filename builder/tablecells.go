@@ -0,0 +1,37 @@
+package builder
+
+import "strings"
+
+// tabWidth is the tab stop interval used when expanding tabs in multi-line table cells.
+const tabWidth = 4
+
+// cellLines splits table cell text into its lines and expands tabs to spaces. tview draws a
+// cell on a single row (a newline is squashed) and has no row heights, so each line of a
+// multi-line cell takes its own table row.
+func cellLines(text string) []string {
+	lines := strings.Split(strings.TrimSuffix(text, "\n"), "\n")
+	for i, line := range lines {
+		lines[i] = expandTabs(line)
+	}
+	return lines
+}
+
+// expandTabs replaces each tab with spaces up to the next tab stop.
+func expandTabs(line string) string {
+	if !strings.Contains(line, "\t") {
+		return line
+	}
+	var sb strings.Builder
+	col := 0
+	for _, r := range line {
+		if r == '\t' {
+			n := tabWidth - col%tabWidth
+			sb.WriteString(strings.Repeat(" ", n))
+			col += n
+			continue
+		}
+		sb.WriteRune(r)
+		col++
+	}
+	return sb.String()
+}
@@ -0,0 +1,33 @@
+package builder
+
+import (
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+// titleBox is the part of tview's Box that attachTitleRight needs.
+type titleBox interface {
+	SetRect(x, y, width, height int)
+	GetInnerRect() (int, int, int, int)
+	GetDrawFunc() func(screen tcell.Screen, x, y, width, height int) (int, int, int, int)
+	SetDrawFunc(func(screen tcell.Screen, x, y, width, height int) (int, int, int, int)) *tview.Box
+}
+
+// attachTitleRight prints text right-aligned in the top border of box, next to the box's own
+// (left or centered) title; tview's Box has a single title. The box draws its border before
+// calling its draw func, so the label is printed from a draw func wrapping any draw func the box
+// already has (e.g. a flex layout or a custom drawer), which must therefore be attached first.
+func attachTitleRight(box titleBox, text string, color tcell.Color) {
+	inner := box.GetDrawFunc()
+	box.SetDrawFunc(func(screen tcell.Screen, x, y, width, height int) (int, int, int, int) {
+		if width >= 4 {
+			tview.Print(screen, text, x+1, y, width-2, tview.AlignRight, color)
+		}
+		if inner != nil {
+			return inner(screen, x, y, width, height)
+		}
+		// SetRect marks the inner rect stale, so GetInnerRect derives it from the border and padding.
+		box.SetRect(x, y, width, height)
+		return box.GetInnerRect()
+	})
+}
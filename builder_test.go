@@ -0,0 +1,1842 @@
+package tviewyaml_test
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/cassdeckard/tviewyaml"
+	"github.com/cassdeckard/tviewyaml/template"
+	"github.com/cassdeckard/tviewyaml/testutil"
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+// writeConfig writes app.yaml and a single "main" page to a temp dir and returns its path.
+func writeConfig(t *testing.T, appYAML, mainYAML string) string {
+	t.Helper()
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "app.yaml"), []byte(appYAML), 0644); err != nil {
+		t.Fatalf("write app.yaml: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "main.yaml"), []byte(mainYAML), 0644); err != nil {
+		t.Fatalf("write main.yaml: %v", err)
+	}
+	return dir
+}
+
+const mainOnlyAppYAML = `application:
+  root:
+    type: pages
+    pages:
+      - name: main
+        ref: main.yaml
+`
+
+func TestAppBuilder_WithClock(t *testing.T) {
+	mainYAML := `type: flex
+direction: row
+items:
+  - primitive:
+      type: textView
+      text: 'Time: {{ bindState "clock" }}'
+    fixedSize: 1
+  - primitive:
+      type: button
+      label: "Start"
+      onSelected: '{{ showTime }}'
+    fixedSize: 1
+    focus: true
+`
+	fixed := time.Date(2024, 1, 2, 15, 4, 5, 0, time.UTC)
+	maxZero := 0
+	b := tviewyaml.NewAppBuilder(writeConfig(t, mainOnlyAppYAML, mainYAML)).
+		WithClock(template.ClockFunc(func() time.Time { return fixed })).
+		WithTemplateFunction("showTime", 0, &maxZero, nil, func(ctx *template.Context) {
+			ctx.SetStateDirect("clock", ctx.Now().Format("15:04:05"))
+		})
+	h := testutil.New(t, testutil.FromBuilder(b), 40, 5)
+
+	h.TypeKey("Enter")
+	if !h.WaitForContent("Time: 15:04:05") {
+		t.Errorf("screen should show fixed clock time; got:\n%s", h.Content())
+	}
+}
+
+func TestNewAppBuilderFS(t *testing.T) {
+	fsys := fstest.MapFS{
+		"config/app.yaml":  {Data: []byte(mainOnlyAppYAML)},
+		"config/main.yaml": {Data: []byte("type: flex\nitems:\n  - primitive:\n      type: textView\n      text: Embedded page\n    proportion: 1\n")},
+	}
+	h := testutil.New(t, testutil.FromBuilder(tviewyaml.NewAppBuilderFS(fsys, "config")), 40, 5)
+	if !h.WaitForContent("Embedded page") {
+		t.Errorf("screen should show the page read from the FS; got:\n%s", h.Content())
+	}
+}
+
+func TestNewAppBuilderDirs(t *testing.T) {
+	page := func(text string) string {
+		return "type: flex\nitems:\n  - primitive:\n      type: textView\n      text: " + text + "\n    proportion: 1\n"
+	}
+	defaults := writeConfig(t, mainOnlyAppYAML, page("Bundled page"))
+	overrides := t.TempDir() // no app.yaml: it falls through to the defaults
+	if err := os.WriteFile(filepath.Join(overrides, "main.yaml"), []byte(page("User page")), 0644); err != nil {
+		t.Fatalf("write main.yaml: %v", err)
+	}
+	h := testutil.New(t, testutil.FromBuilder(tviewyaml.NewAppBuilderDirs(overrides, defaults)), 40, 5)
+	if !h.WaitForContent("User page") {
+		t.Errorf("screen should show the overriding page; got:\n%s", h.Content())
+	}
+
+	if _, _, err := tviewyaml.NewAppBuilderDirs().Build(); err == nil {
+		t.Error("Build() with no config directories should fail")
+	}
+}
+
+func TestAppBuilder_InlinePagesInAppFile(t *testing.T) {
+	appYAML := `application:
+  root:
+    type: pages
+    pages:
+      - name: main
+        ref: main
+pages:
+  main:
+    type: flex
+    items:
+      - primitive:
+          type: textView
+          text: Defined in app.yaml
+        proportion: 1
+`
+	h := testutil.New(t, testutil.FromBuilder(tviewyaml.NewAppBuilder(writeConfig(t, appYAML, "type: flex\n"))), 40, 5)
+	if !h.WaitForContent("Defined in app.yaml") {
+		t.Errorf("screen should show the page defined in app.yaml; got:\n%s", h.Content())
+	}
+}
+
+func TestAppBuilder_InlinePageRef(t *testing.T) {
+	appYAML := `application:
+  root:
+    type: pages
+    pages:
+      - name: main
+        page:
+          type: flex
+          items:
+            - primitive:
+                type: textView
+                text: Inline under root
+              proportion: 1
+`
+	dir := writeConfig(t, appYAML, "type: flex\n")
+	if err := os.Remove(filepath.Join(dir, "main.yaml")); err != nil {
+		t.Fatalf("remove main.yaml: %v", err)
+	}
+	h := testutil.New(t, testutil.FromBuilder(tviewyaml.NewAppBuilder(dir)), 40, 5)
+	if !h.WaitForContent("Inline under root") {
+		t.Errorf("screen should show the inline page; got:\n%s", h.Content())
+	}
+}
+
+func TestAppBuilder_WithStartPage(t *testing.T) {
+	appYAML := `application:
+  root:
+    type: pages
+    pages:
+      - name: main
+        ref: main.yaml
+      - name: settings
+        page:
+          type: flex
+          items:
+            - primitive:
+                type: textView
+                text: Settings page
+              proportion: 1
+`
+	dir := writeConfig(t, appYAML, "type: flex\nitems:\n  - primitive:\n      type: textView\n      text: Main page\n    proportion: 1\n")
+	b := tviewyaml.NewAppBuilder(dir).WithStartPage("settings")
+	h := testutil.New(t, testutil.FromBuilder(b), 40, 5)
+	if !h.WaitForContent("Settings page") {
+		t.Errorf("screen should start on the settings page; got:\n%s", h.Content())
+	}
+	if strings.Contains(h.Content(), "Main page") {
+		t.Errorf("main page should be hidden; got:\n%s", h.Content())
+	}
+
+	if _, _, err := tviewyaml.NewAppBuilder(dir).WithStartPage("missing").Build(); err == nil || !strings.Contains(err.Error(), `start page "missing"`) {
+		t.Errorf("Build() error = %v, want unknown start page error", err)
+	}
+}
+
+func TestAppBuilder_WithAppFile(t *testing.T) {
+	dir := writeConfig(t, mainOnlyAppYAML, "type: flex\n")
+	dashboardYAML := `application:
+  root:
+    type: pages
+    pages:
+      - name: main
+        ref: dashboard-page.yaml
+`
+	pageYAML := `type: flex
+items:
+  - primitive:
+      type: textView
+      text: Dashboard page
+    proportion: 1
+`
+	for name, content := range map[string]string{"dashboard.yaml": dashboardYAML, "dashboard-page.yaml": pageYAML} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatalf("write %s: %v", name, err)
+		}
+	}
+
+	h := testutil.New(t, testutil.FromBuilder(tviewyaml.NewAppBuilder(dir).WithAppFile("dashboard.yaml")), 40, 5)
+	if !h.WaitForContent("Dashboard page") {
+		t.Errorf("screen should show the page from dashboard.yaml; got:\n%s", h.Content())
+	}
+
+	if _, _, err := tviewyaml.NewAppBuilder(dir).WithAppFile("missing.yaml").Build(); err == nil {
+		t.Error("Build() with a missing app file should fail")
+	}
+}
+
+func TestAppBuilder_PageOnShowOnHide(t *testing.T) {
+	appYAML := `application:
+  globalKeyBindings:
+    - key: "F2"
+      action: '{{ switchToPage "other" }}'
+    - key: "F3"
+      action: '{{ goBack }}'
+  root:
+    type: pages
+    pages:
+      - name: main
+        ref: main
+      - name: other
+        ref: other
+pages:
+  main:
+    type: flex
+    onShow: '{{ mark "show:main" }}'
+    onHide: '{{ mark "hide:main" }}'
+    items:
+      - primitive:
+          type: textView
+          text: Main page
+        proportion: 1
+  other:
+    type: flex
+    onShow: '{{ mark "show:other" }}'
+    items:
+      - primitive:
+          type: textView
+          text: Other page
+        proportion: 1
+`
+	var mu sync.Mutex
+	var events []string
+	maxOne := 1
+	b := tviewyaml.NewAppBuilder(writeConfig(t, appYAML, "type: flex\n")).
+		WithTemplateFunction("mark", 1, &maxOne, nil, func(ctx *template.Context, event string) {
+			mu.Lock()
+			defer mu.Unlock()
+			events = append(events, event)
+		})
+	h := testutil.New(t, testutil.FromBuilder(b), 40, 5)
+	waitForEvents := func(want string) {
+		t.Helper()
+		var got string
+		for deadline := time.Now().Add(time.Second); time.Now().Before(deadline); time.Sleep(5 * time.Millisecond) {
+			mu.Lock()
+			got = strings.Join(events, ",")
+			mu.Unlock()
+			if got == want {
+				return
+			}
+		}
+		t.Fatalf("lifecycle events = %q, want %q", got, want)
+	}
+
+	waitForEvents("show:main")
+	h.TypeKey("F2")
+	waitForEvents("show:main,hide:main,show:other")
+	h.TypeKey("F3")
+	waitForEvents("show:main,hide:main,show:other,show:main") // other has no onHide
+}
+
+func TestAppBuilder_PageOnReady(t *testing.T) {
+	appYAML := `application:
+  root:
+    type: pages
+    pages:
+      - name: main
+        ref: main
+      - name: other
+        ref: other
+pages:
+  main:
+    type: flex
+    onReady: '{{ mark "main" }}'
+    items:
+      - primitive:
+          type: textView
+          text: 'Seeded: {{ bindState seeded }}'
+        proportion: 1
+  other:
+    type: flex
+    onReady: '{{ mark "other" }}'
+    items:
+      - primitive:
+          type: textView
+          text: Other page
+        proportion: 1
+`
+	var ready []string
+	maxOne := 1
+	b := tviewyaml.NewAppBuilder(writeConfig(t, appYAML, "type: flex\n")).
+		WithTemplateFunction("mark", 1, &maxOne, nil, func(ctx *template.Context, page string) {
+			ready = append(ready, page)
+			ctx.SetStateDirect("seeded", "by "+page)
+		})
+	h := testutil.New(t, testutil.FromBuilder(b), 40, 5)
+
+	if got := strings.Join(ready, ","); got != "main,other" {
+		t.Errorf("onReady ran for %q, want once per page in order: main,other", got)
+	}
+	if !h.WaitForContent("Seeded: by other") {
+		t.Errorf("bound view should show the state seeded by onReady; got:\n%s", h.Content())
+	}
+}
+
+func TestAppBuilder_WithValueResolver(t *testing.T) {
+	mainYAML := `type: flex
+items:
+  - primitive:
+      type: textView
+      text: secret:GREETING
+    proportion: 1
+`
+	resolve := func(key string) (string, error) {
+		if key == "GREETING" {
+			return "Hello from the vault", nil
+		}
+		return "", fmt.Errorf("unknown secret %s", key)
+	}
+	dir := writeConfig(t, mainOnlyAppYAML, mainYAML)
+	h := testutil.New(t, testutil.FromBuilder(tviewyaml.NewAppBuilder(dir).WithValueResolver("secret", resolve)), 40, 5)
+	if !h.WaitForContent("Hello from the vault") {
+		t.Errorf("screen should show the resolved value; got:\n%s", h.Content())
+	}
+
+	appYAML := strings.Replace(mainOnlyAppYAML, "application:\n", "application:\n  name: secret:MISSING\n", 1)
+	_, _, err := tviewyaml.NewAppBuilder(writeConfig(t, appYAML, mainYAML)).WithValueResolver("secret", resolve).Build()
+	if err == nil || !strings.Contains(err.Error(), "unknown secret MISSING") {
+		t.Errorf("Build() error = %v, want the resolver error", err)
+	}
+	if _, _, err := tviewyaml.NewAppBuilder(dir).WithValueResolver("bad:prefix", resolve).Build(); err == nil {
+		t.Error("Build() with a prefix containing ':' should fail")
+	}
+}
+
+const escapeAppYAML = `application:
+  globalKeyBindings:
+    - key: "Escape"
+      action: '{{ switchToPage "other" }}'
+  root:
+    type: pages
+    pages:
+      - name: main
+        ref: main.yaml
+      - name: other
+        ref: other.yaml
+`
+
+const otherPageYAML = `type: flex
+items:
+  - primitive:
+      type: textView
+      text: "Other Page"
+    proportion: 1
+`
+
+func newEscapeHarness(t *testing.T, mainYAML string) *testutil.Harness {
+	t.Helper()
+	dir := writeConfig(t, escapeAppYAML, mainYAML)
+	if err := os.WriteFile(filepath.Join(dir, "other.yaml"), []byte(otherPageYAML), 0644); err != nil {
+		t.Fatalf("write other.yaml: %v", err)
+	}
+	return testutil.New(t, testutil.FromBuilder(tviewyaml.NewAppBuilder(dir)), 40, 10)
+}
+
+func TestAppBuilder_EscapeCancelsFocusedNestedForm(t *testing.T) {
+	mainYAML := `type: flex
+direction: row
+items:
+  - primitive:
+      type: textView
+      name: log
+      text: "Main Page"
+    fixedSize: 2
+  - primitive:
+      type: form
+      name: settings
+      onCancel: '{{ appendText "log" "cancelled" }}'
+      formItems:
+        - type: inputfield
+          label: "Name"
+    proportion: 1
+    focus: true
+`
+	h := newEscapeHarness(t, mainYAML)
+
+	h.TypeKey("Escape")
+	if !h.WaitForContent("cancelled") {
+		t.Fatalf("Escape should run the focused form's onCancel; got:\n%s", h.Content())
+	}
+	if h.ScreenContains("Other Page") {
+		t.Errorf("global Escape binding should not run while a cancelable form has focus")
+	}
+}
+
+func TestAppBuilder_EscapeStandaloneInputFieldUsesGlobalBinding(t *testing.T) {
+	mainYAML := `type: flex
+items:
+  - primitive:
+      type: inputField
+      label: "Name: "
+    proportion: 1
+    focus: true
+`
+	h := newEscapeHarness(t, mainYAML)
+
+	h.TypeKey("Escape")
+	if !h.WaitForContent("Other Page") {
+		t.Errorf("Escape on a standalone input field should run the global binding; got:\n%s", h.Content())
+	}
+}
+
+func TestAppBuilder_GlobalKeyBindingPassthrough(t *testing.T) {
+	appYAML := `application:
+  globalKeyBindings:
+    - key: "Ctrl+S"
+      action: '{{ appendText "log" "saved" }}'
+      passthrough: true
+    - key: "Ctrl+X"
+      action: '{{ appendText "log" "cut" }}'
+  root:
+    type: pages
+    pages:
+      - name: main
+        ref: main.yaml
+`
+	mainYAML := `type: flex
+direction: row
+items:
+  - primitive:
+      type: textView
+      name: log
+    fixedSize: 3
+  - primitive:
+      type: inputField
+      label: "Keys: "
+    fixedSize: 1
+    focus: true
+`
+	var seen []rune
+	b := tviewyaml.NewAppBuilder(writeConfig(t, appYAML, mainYAML))
+	h := testutil.New(t, testutil.FromBuilder(b), 40, 10)
+	input, ok := h.App().GetFocus().(*tview.InputField)
+	if !ok {
+		t.Fatalf("focused primitive = %T, want *tview.InputField", h.App().GetFocus())
+	}
+	keys := make(chan rune, 4)
+	h.App().QueueUpdate(func() {
+		input.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+			keys <- event.Rune()
+			return event
+		})
+	})
+
+	h.TypeKey("Ctrl+S")
+	h.TypeKey("Ctrl+X")
+	if !h.WaitForContent("cut") || !h.ScreenContains("saved") {
+		t.Fatalf("both binding actions should run; got:\n%s", h.Content())
+	}
+	h.App().QueueUpdate(func() {
+		for len(keys) > 0 {
+			seen = append(seen, <-keys)
+		}
+	})
+	if len(seen) != 1 || seen[0] != 'S' {
+		t.Errorf("focused primitive saw %q, want only Ctrl+S (passthrough) and not Ctrl+X (consumed)", seen)
+	}
+}
+
+func TestAppBuilder_ShowKeyHelp(t *testing.T) {
+	appYAML := `application:
+  globalKeyBindings:
+    - key: "?"
+      action: '{{ showKeyHelp }}'
+      description: "Show key help"
+    - key: "Ctrl+Q"
+      action: '{{ stopApp }}'
+      description: "Quit"
+  root:
+    type: pages
+    pages:
+      - name: main
+        ref: main.yaml
+`
+	mainYAML := `type: flex
+items:
+  - primitive:
+      type: textView
+      text: "Main Page"
+    proportion: 1
+`
+	h := testutil.New(t, testutil.FromBuilder(tviewyaml.NewAppBuilder(writeConfig(t, appYAML, mainYAML))), 60, 15)
+
+	h.TypeKey("?")
+	if !h.WaitForContent("Quit") {
+		t.Fatalf("key help should list bindings; got:\n%s", h.Content())
+	}
+	if !h.ScreenContains("Ctrl+Q") {
+		t.Errorf("key help should contain bound key Ctrl+Q; got:\n%s", h.Content())
+	}
+}
+
+func TestAppBuilder_OnFocusOnBlur(t *testing.T) {
+	appYAML := `application:
+  globalKeyBindings:
+    - key: "Ctrl+F"
+      action: '{{ focusSearch }}'
+  root:
+    type: pages
+    pages:
+      - name: main
+        ref: main.yaml
+`
+	mainYAML := `type: flex
+direction: row
+items:
+  - primitive:
+      type: textView
+      name: log
+    fixedSize: 3
+  - primitive:
+      type: inputField
+      name: first
+      label: "First: "
+      onBlur: '{{ appendText "log" "blur" }}'
+    fixedSize: 1
+    focus: true
+  - primitive:
+      type: inputField
+      name: search
+      label: "Search: "
+      onFocus: '{{ appendText "log" "focus" }}'
+    fixedSize: 1
+`
+	maxZero := 0
+	var blurred, focused interface{}
+	b := tviewyaml.NewAppBuilder(writeConfig(t, appYAML, mainYAML)).
+		WithTemplateFunction("focusSearch", 0, &maxZero, nil, func(ctx *template.Context) {
+			if p, ok := ctx.GetPrimitive("search"); ok {
+				ctx.App.SetFocus(p)
+			}
+			blurred, _ = ctx.GetState("__blurredPrimitive")
+			focused, _ = ctx.GetState("__focusedPrimitive")
+		})
+	h := testutil.New(t, testutil.FromBuilder(b), 40, 6)
+
+	h.TypeKey("Ctrl+F")
+	if !h.WaitForContent("focus") {
+		t.Fatalf("onFocus should run when the field gains focus; got:\n%s", h.Content())
+	}
+	if got := h.RegionText(0, 0, 5, 2); got != "blur \nfocus" {
+		t.Errorf("log = %q, want onBlur then onFocus", got)
+	}
+	h.App().QueueUpdate(func() {
+		if blurred != "first" || focused != "search" {
+			t.Errorf("__blurredPrimitive = %v, __focusedPrimitive = %v; want first, search", blurred, focused)
+		}
+	})
+}
+
+func TestAppBuilder_FocusOrder(t *testing.T) {
+	mainYAML := `type: flex
+direction: row
+focusOrder: [alpha, gamma, delta, bravo]
+items:
+  - primitive:
+      type: textView
+      name: log
+    fixedSize: 6
+  - primitive:
+      type: inputField
+      name: alpha
+      label: "A: "
+      onFocus: '{{ appendText "log" "to-alpha" }}'
+    fixedSize: 1
+    focus: true
+  - primitive:
+      type: inputField
+      name: bravo
+      label: "B: "
+      onFocus: '{{ appendText "log" "to-bravo" }}'
+    fixedSize: 1
+  - primitive:
+      type: inputField
+      name: gamma
+      label: "C: "
+      onFocus: '{{ appendText "log" "to-gamma" }}'
+    fixedSize: 1
+  - primitive:
+      type: inputField
+      name: delta
+      label: "D: "
+      onFocus: '{{ appendText "log" "to-delta" }}'
+    fixedSize: 1
+`
+	h := testutil.New(t, testutil.FromBuilder(tviewyaml.NewAppBuilder(writeConfig(t, mainOnlyAppYAML, mainYAML))), 40, 12)
+
+	for _, key := range []string{"Tab", "Tab", "Tab", "Tab", "Backtab"} {
+		h.TypeKey(key)
+	}
+	want := "to-gamma\nto-delta\nto-bravo\nto-alpha\nto-bravo"
+	deadline := time.Now().Add(2 * time.Second)
+	for !strings.HasSuffix(h.RegionText(0, 0, 8, 6), want) && time.Now().Before(deadline) {
+		h.WaitForDraw()
+	}
+	if got := h.RegionText(0, 0, 8, 6); !strings.HasSuffix(got, want) {
+		t.Errorf("focus log = %q, want it to end with %q", got, want)
+	}
+}
+
+func TestAppBuilder_PrimitiveID(t *testing.T) {
+	appYAML := `application:
+  globalKeyBindings:
+    - key: "F2"
+      action: '{{ focus "search" }}'
+  root:
+    type: pages
+    pages:
+      - name: main
+        ref: main.yaml
+`
+	// Builtins target primitives by id, so the display names can change freely
+	mainYAML := `type: flex
+direction: row
+items:
+  - primitive:
+      type: textView
+      id: log
+      name: Activity log
+    fixedSize: 3
+  - primitive:
+      type: inputField
+      id: search
+      name: Search box
+      label: "Find: "
+      onFocus: '{{ appendText "log" "focused search" }}'
+    fixedSize: 1
+  - primitive:
+      type: inputField
+      name: other
+    fixedSize: 1
+    focus: true
+`
+	h := testutil.New(t, testutil.FromBuilder(tviewyaml.NewAppBuilder(writeConfig(t, appYAML, mainYAML))), 40, 6)
+	h.TypeKey("F2")
+	if !h.WaitForContent("focused search") {
+		t.Errorf("focus and appendText should find the primitives by id; got:\n%s", h.Content())
+	}
+}
+
+func TestAppBuilder_FocusOrderUnknownName(t *testing.T) {
+	mainYAML := `type: flex
+focusOrder: [missing]
+items:
+  - primitive:
+      type: inputField
+      name: present
+    fixedSize: 1
+`
+	_, pageErrors, err := tviewyaml.NewAppBuilder(writeConfig(t, mainOnlyAppYAML, mainYAML)).Build()
+	if err != nil {
+		t.Fatalf("Build: %v", err)
+	}
+	if len(pageErrors) != 1 || !strings.Contains(pageErrors[0].Error(), `focusOrder: no primitive named "missing"`) {
+		t.Fatalf("page errors = %v, want unknown focusOrder name", pageErrors)
+	}
+}
+
+func TestAppBuilder_ValidatesTemplateArgCounts(t *testing.T) {
+	tests := []struct {
+		name    string
+		prim    string
+		wantErr string
+	}{
+		{"callback too few", `type: button
+      label: Go
+      onSelected: '{{ switchToPage }}'`, `function "switchToPage" expects pageName, got 0 argument(s)`},
+		{"callback too many", `type: button
+      label: Go
+      onSelected: '{{ goBack "now" }}'`, `function "goBack" accepts at most 0 argument(s), got 1`},
+		{"evaluator too few", `type: textView
+      text: 'Count: {{ bindState }}'`, `evaluator "bindState" expects key, got 0 args`},
+		{"evaluator too many", `type: textView
+      text: '{{ formatState count "%d" "extra" }}'`, `evaluator "formatState" expects key format, got 3 args`},
+		{"evaluator in list item", `type: list
+      listItems:
+        - mainText: '{{ bindState a b }}'`, `listItem[0] mainText: evaluator "bindState" expects key, got 2 args`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mainYAML := "type: flex\nitems:\n  - primitive:\n      " + tt.prim + "\n    proportion: 1\n"
+			_, _, err := tviewyaml.NewAppBuilder(writeConfig(t, mainOnlyAppYAML, mainYAML)).Build()
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Build() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestAppBuilder_ValidatesFocusCallbacks(t *testing.T) {
+	for _, field := range []string{"onFocus", "onBlur"} {
+		t.Run(field, func(t *testing.T) {
+			mainYAML := "type: flex\nitems:\n  - primitive:\n      type: inputField\n      " + field + ": '{{ noSuchFunc }}'\n    proportion: 1\n"
+			_, _, err := tviewyaml.NewAppBuilder(writeConfig(t, mainOnlyAppYAML, mainYAML)).Build()
+			if err == nil || !strings.Contains(err.Error(), `unknown function/evaluator "noSuchFunc"`) {
+				t.Errorf("Build() error = %v, want unknown function error for %s", err, field)
+			}
+		})
+	}
+}
+
+func TestAppBuilder_ConfirmActionCheckedAtBuild(t *testing.T) {
+	mainYAML := `type: flex
+items:
+  - primitive:
+      type: button
+      label: Delete
+      onSelected: '{{ confirm "Delete?" "{{ deleteItm }}" }}'
+    proportion: 1
+`
+	_, pageErrors, err := tviewyaml.NewAppBuilder(writeConfig(t, mainOnlyAppYAML, mainYAML)).Build()
+	if err != nil {
+		t.Fatalf("Build: %v", err)
+	}
+	if len(pageErrors) != 1 || !strings.Contains(pageErrors[0].Error(), "unknown function: deleteItm") {
+		t.Errorf("pageErrors = %v, want the confirm action's unknown function", pageErrors)
+	}
+}
+
+func TestAppBuilder_ButtonShortcut(t *testing.T) {
+	mainYAML := `type: flex
+direction: row
+items:
+  - primitive:
+      type: textView
+      name: log
+    fixedSize: 3
+  - primitive:
+      type: inputField
+      name: notes
+      label: "Notes: "
+    fixedSize: 1
+    focus: true
+  - primitive:
+      type: button
+      label: "Save (Ctrl+S)"
+      shortcut: "Ctrl+S"
+      onSelected: '{{ appendText "log" "saved" }}'
+    fixedSize: 1
+`
+	h := testutil.New(t, testutil.FromBuilder(tviewyaml.NewAppBuilder(writeConfig(t, mainOnlyAppYAML, mainYAML))), 40, 8)
+
+	h.TypeKey("Ctrl+S")
+	if !h.WaitForContent("saved") {
+		t.Fatalf("Ctrl+S should run the button's onSelected while the input field has focus; got:\n%s", h.Content())
+	}
+	if !h.ScreenContains("Save (Ctrl+S)") {
+		t.Errorf("button label should still be shown; got:\n%s", h.Content())
+	}
+}
+
+func TestAppBuilder_ShowStyledModal(t *testing.T) {
+	appYAML := `application:
+  globalKeyBindings:
+    - key: "Ctrl+E"
+      action: '{{ showStyledModal "Save failed" "red" "white" "" "OK" }}'
+  root:
+    type: pages
+    pages:
+      - name: main
+        ref: main.yaml
+`
+	mainYAML := `type: flex
+items:
+  - primitive:
+      type: textView
+      text: "Ready"
+    proportion: 1
+`
+	h := testutil.New(t, testutil.FromBuilder(tviewyaml.NewAppBuilder(writeConfig(t, appYAML, mainYAML))), 40, 10)
+
+	h.TypeKey("Ctrl+E")
+	if !h.WaitForContent("Save failed") {
+		t.Fatalf("styled modal not shown; got:\n%s", h.Content())
+	}
+	for y := 0; y < 10; y++ {
+		for x := 0; x < 40; x++ {
+			if ch, style := h.CellAt(x, y); ch == 'S' {
+				if fg, bg, _ := style.Decompose(); fg != tcell.ColorWhite || bg != tcell.ColorRed {
+					t.Errorf("modal text fg/bg = %v/%v, want white/red", fg, bg)
+				}
+				return
+			}
+		}
+	}
+	t.Fatal("modal text not found on screen")
+}
+
+func TestAppBuilder_Dialog(t *testing.T) {
+	mainYAML := `type: dialog
+title: "Warning"
+width: 30
+text: "The configuration file could not be saved because the disk is full."
+buttons:
+  - label: "Retry"
+  - label: "Cancel"
+`
+	h := testutil.New(t, testutil.FromBuilder(tviewyaml.NewAppBuilder(writeConfig(t, mainOnlyAppYAML, mainYAML))), 40, 12)
+	if !h.WaitForContent("Retry") {
+		t.Fatalf("dialog not drawn; got:\n%s", h.Content())
+	}
+
+	// 30 columns wide (columns 5-34 when centered in 40) with the text word-wrapped inside the border
+	h.AssertSnapshot(t, "")
+}
+
+func TestAppBuilder_ProgressBar(t *testing.T) {
+	appYAML := `application:
+  globalKeyBindings:
+    - key: "F2"
+      action: '{{ setProgress "40" }}'
+    - key: "F3"
+      action: '{{ setProgress "100" }}'
+  root:
+    type: pages
+    pages:
+      - name: main
+        ref: main.yaml
+`
+	mainYAML := `type: flex
+direction: row
+items:
+  - primitive:
+      type: textView
+      text: "Upload"
+    fixedSize: 1
+  - primitive:
+      type: progressBar
+      border: true
+      valueFromState: progress
+      fillColor: green
+      emptyColor: gray
+    fixedSize: 3
+`
+	one := 1
+	b := tviewyaml.NewAppBuilder(writeConfig(t, appYAML, mainYAML)).
+		WithTemplateFunction("setProgress", 1, &one, nil, func(ctx *template.Context, value string) {
+			ctx.SetState("progress", value)
+		})
+	h := testutil.New(t, testutil.FromBuilder(b), 40, 5)
+	if !h.WaitForContent("Upload") {
+		t.Fatalf("page not drawn; got:\n%s", h.Content())
+	}
+
+	// waitForFill waits until the bar's inner row (38 cells at y=2) is filled up to column x.
+	waitForFill := func(x int) {
+		t.Helper()
+		for i := 0; i < 20; i++ {
+			_, filled := h.CellAt(x, 2)
+			_, empty := h.CellAt(x+1, 2)
+			_, fillBg, _ := filled.Decompose()
+			_, emptyBg, _ := empty.Decompose()
+			if fillBg == tcell.ColorGreen && (x+1 > 38 || emptyBg == tcell.ColorGray) {
+				return
+			}
+			h.WaitForDraw()
+		}
+		t.Fatalf("bar not filled through column %d; got:\n%s", x, h.Content())
+	}
+
+	// 40% of 38 inner cells rounds to 15 (columns 1-15)
+	h.TypeKey("F2")
+	waitForFill(15)
+	h.AssertSnapshot(t, "TestAppBuilder_ProgressBar_40")
+
+	h.TypeKey("F3")
+	waitForFill(38)
+	h.AssertSnapshot(t, "TestAppBuilder_ProgressBar_100")
+}
+
+func TestAppBuilder_Sparkline(t *testing.T) {
+	appYAML := `application:
+  globalKeyBindings:
+    - key: "F2"
+      action: '{{ setSeries "0, 1, 2, 3, 4, 5, 6, 8" }}'
+  root:
+    type: pages
+    pages:
+      - name: main
+        ref: main.yaml
+`
+	mainYAML := `type: flex
+direction: row
+items:
+  - primitive:
+      type: sparkline
+      title: "Requests"
+      border: true
+      valueFromState: requests
+      fillColor: green
+      maxHeight: 2
+    fixedSize: 5
+`
+	one := 1
+	b := tviewyaml.NewAppBuilder(writeConfig(t, appYAML, mainYAML)).
+		WithTemplateFunction("setSeries", 1, &one, nil, func(ctx *template.Context, series string) {
+			ctx.SetState("requests", series)
+		})
+	h := testutil.New(t, testutil.FromBuilder(b), 20, 5)
+	if !h.WaitForContent("Requests") {
+		t.Fatalf("page not drawn; got:\n%s", h.Content())
+	}
+
+	// Eight values right-aligned in 18 inner columns; the largest (8) fills both rows
+	h.TypeKey("F2")
+	if !h.WaitForContent("██") {
+		t.Fatalf("series not drawn; got:\n%s", h.Content())
+	}
+	for x, want := range map[int]rune{11: ' ', 12: '▂', 15: '█', 18: '█'} {
+		if got, _ := h.CellAt(x, 3); got != want {
+			t.Errorf("bottom row column %d = %q, want %q", x, got, want)
+		}
+	}
+	h.AssertSnapshot(t, "")
+}
+
+func TestAppBuilder_WithDrawer(t *testing.T) {
+	mainYAML := `type: flex
+items:
+  - primitive:
+      type: box
+      border: true
+      drawer: stripes
+    proportion: 1
+`
+	var mu sync.Mutex
+	var calls int
+	var rect [4]int
+	b := tviewyaml.NewAppBuilder(writeConfig(t, mainOnlyAppYAML, mainYAML)).
+		WithDrawer("stripes", func(screen tcell.Screen, x, y, width, height int) (int, int, int, int) {
+			mu.Lock()
+			calls++
+			rect = [4]int{x, y, width, height}
+			mu.Unlock()
+			for col := x + 1; col < x+width-1; col += 2 {
+				screen.SetContent(col, y+1, '|', nil, tcell.StyleDefault)
+			}
+			return x + 1, y + 1, width - 2, height - 2
+		})
+	h := testutil.New(t, testutil.FromBuilder(b), 10, 4)
+	if !h.WaitForContent("|") {
+		t.Fatalf("drawer output not shown; got:\n%s", h.Content())
+	}
+	if got := h.RegionText(1, 1, 8, 1); got != "| | | | " {
+		t.Errorf("drawer row = %q, want %q", got, "| | | | ")
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if calls == 0 {
+		t.Fatal("drawer was not called")
+	}
+	if rect != [4]int{0, 0, 10, 4} {
+		t.Errorf("drawer rect = %v, want the box's outer rect [0 0 10 4]", rect)
+	}
+}
+
+func TestAppBuilder_UnknownDrawer(t *testing.T) {
+	mainYAML := `type: flex
+items:
+  - primitive:
+      type: box
+      drawer: missing
+    proportion: 1
+`
+	_, pageErrors, err := tviewyaml.NewAppBuilder(writeConfig(t, mainOnlyAppYAML, mainYAML)).Build()
+	if err != nil {
+		t.Fatalf("Build() error = %v", err)
+	}
+	if len(pageErrors) != 1 || !strings.Contains(pageErrors[0].Error(), `unknown drawer "missing"`) {
+		t.Errorf("pageErrors = %v, want unknown drawer error", pageErrors)
+	}
+}
+
+func TestAppBuilder_WithShutdown(t *testing.T) {
+	mainYAML := `type: box
+title: Main
+`
+	var calls []string
+	app, _, err := tviewyaml.NewAppBuilder(writeConfig(t, mainOnlyAppYAML, mainYAML)).
+		WithShutdown(func() { calls = append(calls, "first") }).
+		WithShutdown(func() { calls = append(calls, "second") }).
+		Build()
+	if err != nil {
+		t.Fatalf("Build() error = %v", err)
+	}
+	app.Stop()
+	app.Stop()
+	if got := strings.Join(calls, ","); got != "first,second" {
+		t.Errorf("shutdown calls = %q, want first,second (once each, in order)", got)
+	}
+}
+
+func TestAppBuilder_TextViewInitialScroll(t *testing.T) {
+	var lines []string
+	for i := 1; i <= 20; i++ {
+		lines = append(lines, fmt.Sprintf("        line %d", i))
+	}
+	text := strings.Join(lines, "\n")
+	tests := []struct {
+		name      string
+		scroll    string
+		wantFirst string
+	}{
+		{name: "scrollToEnd", scroll: "scrollToEnd: true", wantFirst: "line 17"},
+		{name: "scrollTo", scroll: "scrollTo: 10", wantFirst: "line 11"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mainYAML := fmt.Sprintf(`type: flex
+items:
+  - primitive:
+      type: textView
+      %s
+      text: |-
+%s
+    proportion: 1
+`, tt.scroll, text)
+			h := testutil.New(t, testutil.FromBuilder(tviewyaml.NewAppBuilder(writeConfig(t, mainOnlyAppYAML, mainYAML))), 20, 4)
+			if !h.WaitForContent("line") {
+				t.Fatalf("text not drawn; got:\n%s", h.Content())
+			}
+			if got := strings.TrimSpace(h.RegionText(0, 0, 20, 1)); got != tt.wantFirst {
+				t.Errorf("first visible line = %q, want %q", got, tt.wantFirst)
+			}
+		})
+	}
+}
+
+func TestAppBuilder_TextViewMaxWidth(t *testing.T) {
+	mainYAML := `type: flex
+direction: row
+items:
+  - primitive:
+      type: textView
+      maxWidth: 30
+      border: true
+      title: "About"
+      text: "tviewyaml builds terminal user interfaces from YAML files, so layouts can change without recompiling."
+    proportion: 1
+`
+	// A 30-column block centered on a wide screen; the full width (and more wrapping) on a narrow one
+	for _, cols := range []int{120, 40, 20} {
+		t.Run(fmt.Sprint(cols), func(t *testing.T) {
+			h := testutil.New(t, testutil.FromBuilder(tviewyaml.NewAppBuilder(writeConfig(t, mainOnlyAppYAML, mainYAML))), cols, 8)
+			if !h.WaitForContent("About") {
+				t.Fatalf("text view not drawn; got:\n%s", h.Content())
+			}
+			h.AssertSnapshot(t, "TestAppBuilder_TextViewMaxWidth")
+		})
+	}
+}
+
+func TestAppBuilder_RootBackgroundColor(t *testing.T) {
+	appYAML := `application:
+  root:
+    type: pages
+    backgroundColor: navy
+    pages:
+      - name: main
+        ref: main.yaml
+`
+	mainYAML := `type: flex
+direction: row
+items:
+  - primitive:
+      type: textView
+      maxWidth: 10
+      text: "Centered"
+    proportion: 1
+`
+	h := testutil.New(t, testutil.FromBuilder(tviewyaml.NewAppBuilder(writeConfig(t, appYAML, mainYAML))), 30, 3)
+	if !h.WaitForContent("Centered") {
+		t.Fatalf("text view not drawn; got:\n%s", h.Content())
+	}
+	h.AssertSnapshot(t, "")
+
+	background := func(x, y int) tcell.Color {
+		_, style := h.CellAt(x, y)
+		_, bg, _ := style.Decompose()
+		return bg
+	}
+	// The spacers around the column show the root's fill; the text view keeps its own background
+	for _, x := range []int{0, 29} {
+		if bg := background(x, 1); bg != tcell.ColorNavy {
+			t.Errorf("background at (%d,1) = %v, want the root's navy", x, bg)
+		}
+	}
+	if bg := background(10, 0); bg != tview.Styles.PrimitiveBackgroundColor {
+		t.Errorf("text view background = %v, want %v", bg, tview.Styles.PrimitiveBackgroundColor)
+	}
+}
+
+func TestAppBuilder_WithBeforeDraw(t *testing.T) {
+	mainYAML := `type: flex
+items:
+  - primitive:
+      type: textView
+      text: "Hello"
+    proportion: 1
+`
+	var calls atomic.Int64
+	b := tviewyaml.NewAppBuilder(writeConfig(t, mainOnlyAppYAML, mainYAML)).
+		WithBeforeDraw(func(screen tcell.Screen) bool {
+			calls.Add(1)
+			tview.Print(screen, "WATERMARK", 0, 1, 20, tview.AlignLeft, tcell.ColorGray)
+			return false
+		})
+	h := testutil.New(t, testutil.FromBuilder(b), 20, 3)
+
+	// Returning false still draws the pages, which paint over what the function drew
+	if !h.WaitForContent("Hello") {
+		t.Fatalf("pages not drawn after beforeDraw returned false; got:\n%s", h.Content())
+	}
+	if calls.Load() == 0 {
+		t.Error("beforeDraw was not called")
+	}
+	if h.ScreenContains("WATERMARK") {
+		t.Errorf("pages did not cover the beforeDraw output; got:\n%s", h.Content())
+	}
+}
+
+func TestAppBuilder_OnStartup(t *testing.T) {
+	appYAML := `application:
+  onStartup: '{{ started }}'
+  root:
+    type: pages
+    pages:
+      - name: main
+        ref: main.yaml
+`
+	mainYAML := `type: flex
+items:
+  - primitive:
+      type: textView
+      text: 'Status: {{ bindState status }}'
+    proportion: 1
+`
+	var mu sync.Mutex
+	calls := 0
+	drawnFirst := false
+	zero := 0
+	b := tviewyaml.NewAppBuilder(writeConfig(t, appYAML, mainYAML)).
+		WithTemplateFunction("started", 0, &zero, nil, func(ctx *template.Context) {
+			mu.Lock()
+			calls++
+			drawnFirst = ctx.Screen() != nil
+			mu.Unlock()
+			ctx.SetState("status", "ready")
+		})
+	h := testutil.New(t, testutil.FromBuilder(b), 30, 3)
+	if !h.WaitForContent("Status: ready") {
+		t.Fatalf("onStartup did not run; got:\n%s", h.Content())
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if calls != 1 {
+		t.Errorf("onStartup ran %d times, want 1", calls)
+	}
+	if !drawnFirst {
+		t.Error("onStartup ran before the first draw")
+	}
+}
+
+func TestAppBuilder_WithoutBackgroundRefresh(t *testing.T) {
+	appYAML := `application:
+  onStartup: '{{ started }}'
+  root:
+    type: pages
+    pages:
+      - name: main
+        ref: main.yaml
+`
+	mainYAML := `type: flex
+items:
+  - primitive:
+      type: textView
+      text: 'Status: {{ bindState status }}'
+    proportion: 1
+`
+	ctxCh := make(chan *template.Context, 1)
+	zero := 0
+	b := tviewyaml.NewAppBuilder(writeConfig(t, appYAML, mainYAML)).
+		WithoutBackgroundRefresh().
+		WithTemplateFunction("started", 0, &zero, nil, func(ctx *template.Context) {
+			ctx.SetState("status", "ready")
+			ctxCh <- ctx
+		})
+	h := testutil.New(t, testutil.FromBuilder(b), 30, 3)
+	var ctx *template.Context
+	select {
+	case ctx = <-ctxCh:
+	case <-time.After(2 * time.Second):
+		t.Fatal("onStartup did not run")
+	}
+
+	// No refresh goroutine: the dirty state is not drawn on its own
+	time.Sleep(100 * time.Millisecond)
+	if h.ScreenContains("ready") {
+		t.Fatalf("bound view refreshed without a refresh goroutine; got:\n%s", h.Content())
+	}
+
+	// Refreshing on the main goroutine still works
+	go h.App().QueueUpdateDraw(ctx.RefreshDirtyBoundViews)
+	if !h.WaitForContent("Status: ready") {
+		t.Fatalf("RefreshDirtyBoundViews did not update the view; got:\n%s", h.Content())
+	}
+
+	// Stop is safe, also when called again
+	h.Stop()
+	h.App().Stop()
+}
+
+func TestAppBuilder_OnTick(t *testing.T) {
+	appYAML := `application:
+  onTick: '{{ tick }}'
+  tickInterval: 10ms
+  root:
+    type: pages
+    pages:
+      - name: main
+        ref: main.yaml
+`
+	mainYAML := `type: flex
+items:
+  - primitive:
+      type: textView
+      text: 'Ticks: {{ bindState ticks }}'
+    proportion: 1
+`
+	var ticks atomic.Int64
+	zero := 0
+	b := tviewyaml.NewAppBuilder(writeConfig(t, appYAML, mainYAML)).
+		WithTemplateFunction("tick", 0, &zero, nil, func(ctx *template.Context) {
+			ctx.SetState("ticks", ticks.Add(1))
+		})
+	h := testutil.New(t, testutil.FromBuilder(b), 30, 3)
+	if !h.WaitForContent("Ticks: 2") {
+		t.Fatalf("onTick did not run repeatedly; got:\n%s", h.Content())
+	}
+
+	// Stopping the app stops the ticker
+	h.Stop()
+	stopped := ticks.Load()
+	time.Sleep(50 * time.Millisecond)
+	if got := ticks.Load(); got > stopped+1 {
+		t.Errorf("onTick ran %d more times after Stop", got-stopped)
+	}
+}
+
+func TestAppBuilder_TextViewFillLine(t *testing.T) {
+	mainYAML := `type: flex
+direction: row
+items:
+  - primitive:
+      type: textView
+      dynamicColors: true
+      fillLine: true
+      text: "[white:blue]Selected[-:-]\nOther"
+    proportion: 1
+`
+	h := testutil.New(t, testutil.FromBuilder(tviewyaml.NewAppBuilder(writeConfig(t, mainOnlyAppYAML, mainYAML))), 20, 3)
+	if !h.WaitForContent("Selected") {
+		t.Fatalf("text view not drawn; got:\n%s", h.Content())
+	}
+	background := func(x, y int) tcell.Color {
+		_, style := h.CellAt(x, y)
+		_, bg, _ := style.Decompose()
+		return bg
+	}
+	for x := 0; x < 20; x++ {
+		if background(x, 0) != tcell.ColorBlue {
+			t.Fatalf("cell %d of the selected line is not blue; the background should span the row", x)
+		}
+	}
+	if background(19, 1) == tcell.ColorBlue {
+		t.Error("unstyled line should keep the default background")
+	}
+	h.AssertSnapshot(t, "TestAppBuilder_TextViewFillLine")
+}
+
+func TestAppBuilder_Theme(t *testing.T) {
+	borders := tview.Borders
+	t.Cleanup(func() { tview.Borders = borders }) // borderStyle changes tview's package-level borders
+	appYAML := `application:
+  theme:
+    borderColor: red
+    titleColor: cyan
+    textColor: yellow
+    backgroundColor: navy
+    borderStyle: rounded
+  root:
+    type: pages
+    pages:
+      - name: main
+        ref: main.yaml
+`
+	mainYAML := `type: flex
+direction: row
+items:
+  - primitive:
+      type: textView
+      border: true
+      title: T
+      text: Hi
+    fixedSize: 3
+  - primitive:
+      type: textView
+      text: Own
+      textColor: lime
+    proportion: 1
+`
+	h := testutil.New(t, testutil.FromBuilder(tviewyaml.NewAppBuilder(writeConfig(t, appYAML, mainYAML))), 20, 4)
+	if !h.WaitForContent("Own") {
+		t.Fatalf("page not drawn; got:\n%s", h.Content())
+	}
+	find := func(r rune) (tcell.Color, tcell.Color) {
+		t.Helper()
+		for y := 0; y < 4; y++ {
+			for x := 0; x < 20; x++ {
+				if ch, style := h.CellAt(x, y); ch == r {
+					fg, bg, _ := style.Decompose()
+					return fg, bg
+				}
+			}
+		}
+		t.Fatalf("%q not drawn; got:\n%s", r, h.Content())
+		return 0, 0
+	}
+
+	if fg, _ := find('╭'); fg != tcell.ColorRed {
+		t.Errorf("border color = %v, want the theme's red", fg)
+	}
+	if fg, _ := find('T'); fg != tcell.ColorAqua {
+		t.Errorf("title color = %v, want the theme's cyan", fg)
+	}
+	if fg, bg := find('H'); fg != tcell.ColorYellow || bg != tcell.ColorNavy {
+		t.Errorf("themed text fg/bg = %v/%v, want yellow/navy", fg, bg)
+	}
+	if fg, bg := find('O'); fg != tcell.ColorLime || bg != tcell.ColorNavy {
+		t.Errorf("text with its own textColor fg/bg = %v/%v, want lime/navy", fg, bg)
+	}
+
+	// Stopping the app puts tview's process-wide borders back
+	if tview.Borders.TopLeft != tview.BoxDrawingsLightArcDownAndRight {
+		t.Errorf("borders while running: top left = %q, want rounded", tview.Borders.TopLeft)
+	}
+	h.Stop()
+	if tview.Borders != borders {
+		t.Errorf("tview.Borders not restored after Stop: top left = %q, want %q", tview.Borders.TopLeft, borders.TopLeft)
+	}
+}
+
+func TestAppBuilder_ResponsiveDirection(t *testing.T) {
+	mainYAML := `type: flex
+responsiveDirection: true
+breakpointWidth: 80
+items:
+  - primitive:
+      type: textView
+      border: true
+      text: Menu
+    proportion: 1
+  - primitive:
+      type: textView
+      border: true
+      text: Content
+    proportion: 2
+`
+	for _, cols := range []int{40, 120} {
+		t.Run(fmt.Sprintf("%dx10", cols), func(t *testing.T) {
+			h := testutil.New(t, testutil.FromBuilder(tviewyaml.NewAppBuilder(writeConfig(t, mainOnlyAppYAML, mainYAML))), cols, 10)
+			if !h.WaitForContent("Content") {
+				t.Fatalf("page not drawn; got:\n%s", h.Content())
+			}
+			lines := strings.Split(h.Content(), "\n")
+			sideBySide := strings.Contains(lines[1], "Menu") && strings.Contains(lines[1], "Content")
+			if want := cols >= 80; sideBySide != want {
+				t.Errorf("items side by side = %v at %d columns, want %v; got:\n%s", sideBySide, cols, want, h.Content())
+			}
+			h.AssertSnapshot(t, "TestAppBuilder_ResponsiveDirection")
+		})
+	}
+}
+
+func TestAppBuilder_GridNestedForm(t *testing.T) {
+	mainYAML := `type: flex
+direction: row
+items:
+  - primitive:
+      type: textView
+      name: log
+    fixedSize: 2
+  - primitive:
+      type: grid
+      gridColumns: [10, 0]
+      gridItems:
+        - primitive:
+            type: textView
+            text: Stats
+          row: 0
+          column: 0
+        - primitive:
+            type: form
+            name: settings
+            onSubmit: '{{ appendText "log" "saved" }}'
+            onCancel: '{{ appendText "log" "canceled" }}'
+            formItems:
+              - type: inputfield
+                label: Host
+                value: localhost
+              - type: button
+                label: Save
+                onSelected: '{{ runFormSubmit "settings" }}'
+          row: 0
+          column: 1
+          focus: true
+    proportion: 1
+    focus: true
+`
+	h := testutil.New(t, testutil.FromBuilder(tviewyaml.NewAppBuilder(writeConfig(t, mainOnlyAppYAML, mainYAML))), 40, 10)
+	if !h.WaitForContent("Host") {
+		t.Fatalf("page not drawn; got:\n%s", h.Content())
+	}
+	// Tab to the Save button, whose runFormSubmit runs the form's onSubmit
+	h.TypeKey("Tab")
+	h.TypeKey("Enter")
+	if !h.WaitForContent("saved") {
+		t.Errorf("Save should run the form's onSubmit; got:\n%s", h.Content())
+	}
+	h.TypeKey("Escape")
+	if !h.WaitForContent("canceled") {
+		t.Errorf("Escape should run the form's onCancel; got:\n%s", h.Content())
+	}
+}
+
+func TestAppBuilder_GridHideBelowWidth(t *testing.T) {
+	mainYAML := `type: flex
+items:
+  - primitive:
+      type: grid
+      gridColumns: [12, 0]
+      gridItems:
+        - primitive:
+            type: textView
+            border: true
+            text: Sidebar
+          row: 0
+          column: 0
+          hideBelowWidth: 60
+        - primitive:
+            type: textView
+            border: true
+            text: Content
+          row: 0
+          column: 1
+    proportion: 1
+`
+	for _, cols := range []int{40, 80} {
+		t.Run(fmt.Sprintf("%dx5", cols), func(t *testing.T) {
+			h := testutil.New(t, testutil.FromBuilder(tviewyaml.NewAppBuilder(writeConfig(t, mainOnlyAppYAML, mainYAML))), cols, 5)
+			if !h.WaitForContent("Content") {
+				t.Fatalf("page not drawn; got:\n%s", h.Content())
+			}
+			if want := cols >= 60; h.ScreenContains("Sidebar") != want {
+				t.Errorf("sidebar shown = %v at %d columns, want %v; got:\n%s", !want, cols, want, h.Content())
+			}
+			// Without the sidebar, its column is left out and the content starts at the left edge
+			if r, _ := h.CellAt(0, 1); cols < 60 && r != '│' {
+				t.Errorf("cell (0,1) = %q, want the content's border", r)
+			}
+			h.AssertSnapshot(t, "TestAppBuilder_GridHideBelowWidth")
+		})
+	}
+
+	// The sidebar comes back when the terminal grows again
+	h := testutil.New(t, testutil.FromBuilder(tviewyaml.NewAppBuilder(writeConfig(t, mainOnlyAppYAML, mainYAML))), 40, 5)
+	if !h.WaitForContent("Content") {
+		t.Fatalf("page not drawn; got:\n%s", h.Content())
+	}
+	h.Screen().SetSize(80, 5)
+	h.Resize(80, 5)
+	if !h.WaitForContent("Sidebar") {
+		t.Errorf("sidebar should return after growing to 80 columns; got:\n%s", h.Content())
+	}
+}
+
+func TestAppBuilder_TitleRight(t *testing.T) {
+	mainYAML := `type: flex
+items:
+  - primitive:
+      type: textView
+      border: true
+      title: Logs
+      titleAlign: left
+      titleRight: tail -f
+      text: server started
+    proportion: 1
+`
+	h := testutil.New(t, testutil.FromBuilder(tviewyaml.NewAppBuilder(writeConfig(t, mainOnlyAppYAML, mainYAML))), 40, 5)
+	if !h.WaitForContent("server started") {
+		t.Fatalf("page not drawn; got:\n%s", h.Content())
+	}
+	top := h.RegionText(0, 0, 40, 1)
+	if !strings.HasPrefix(strings.TrimPrefix(top, "┌"), "Logs") || !strings.HasSuffix(strings.TrimSuffix(top, "┐"), "tail -f") {
+		t.Errorf("top border = %q, want Logs on the left and tail -f on the right", top)
+	}
+	h.AssertSnapshot(t, "TestAppBuilder_TitleRight")
+}
+
+func TestAppBuilder_StandaloneCheckboxAndDropdownOnChanged(t *testing.T) {
+	mainYAML := `type: flex
+direction: row
+focusOrder: [dark, size]
+items:
+  - primitive:
+      type: checkbox
+      name: dark
+      label: "Dark mode "
+      onChanged: '{{ record "__checked" }}'
+    fixedSize: 1
+    focus: true
+  - primitive:
+      type: dropdown
+      name: size
+      label: "Size "
+      options: ["Small", "Large"]
+      onChanged: '{{ record "__selectedOption" }}'
+    fixedSize: 1
+`
+	var mu sync.Mutex
+	var got []string
+	one := 1
+	b := tviewyaml.NewAppBuilder(writeConfig(t, mainOnlyAppYAML, mainYAML)).
+		WithTemplateFunction("record", 1, &one, nil, func(ctx *template.Context, key string) {
+			v, _ := ctx.GetState(key)
+			mu.Lock()
+			got = append(got, fmt.Sprintf("%s=%v", key, v))
+			mu.Unlock()
+		})
+	h := testutil.New(t, testutil.FromBuilder(b), 30, 4)
+	if !h.WaitForContent("Dark mode") {
+		t.Fatalf("page not drawn; got:\n%s", h.Content())
+	}
+
+	// Toggle the checkbox, then move to the dropdown and pick the second option
+	h.TypeKey("Enter")
+	h.TypeKey("Tab")
+	h.TypeKey("Enter")
+	h.TypeKey("Down")
+	h.TypeKey("Enter")
+	if !h.WaitForContent("Large") {
+		t.Fatalf("dropdown selection not drawn; got:\n%s", h.Content())
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	want := []string{"__checked=true", "__selectedOption=Large"}
+	if strings.Join(got, ", ") != strings.Join(want, ", ") {
+		t.Errorf("onChanged calls = %v, want %v", got, want)
+	}
+}
+
+func TestAppBuilder_FormButtonSpacer(t *testing.T) {
+	mainYAML := `type: form
+border: true
+buttonsAlign: right
+formItems:
+  - type: inputfield
+    label: "Name"
+  - type: button
+    label: "Cancel"
+  - type: spacer
+    width: 8
+  - type: button
+    label: "Save"
+`
+	h := testutil.New(t, testutil.FromBuilder(tviewyaml.NewAppBuilder(writeConfig(t, mainOnlyAppYAML, mainYAML))), 40, 8)
+	if !h.WaitForContent("Save") {
+		t.Fatalf("form not drawn; got:\n%s", h.Content())
+	}
+
+	// Tab skips the spacer going forward, and Backtab skips it going back. Keys and the focus
+	// reads go through different tview channels, so wait until the key's effect shows.
+	focused := func() string {
+		var label string
+		h.App().QueueUpdate(func() {
+			if btn, ok := h.App().GetFocus().(*tview.Button); ok {
+				label = btn.GetLabel()
+			}
+		})
+		return label
+	}
+	waitFocus := func(want string) string {
+		deadline := time.Now().Add(2 * time.Second)
+		for {
+			got := focused()
+			if got == want || time.Now().After(deadline) {
+				return got
+			}
+			time.Sleep(5 * time.Millisecond)
+		}
+	}
+	h.TypeKey("Tab")
+	if got := waitFocus("Cancel"); got != "Cancel" {
+		t.Fatalf("focus after Tab = %q, want Cancel", got)
+	}
+	h.TypeKey("Tab")
+	if got := waitFocus("Save"); got != "Save" {
+		t.Fatalf("focus after second Tab = %q, want Save", got)
+	}
+	h.TypeKey("Backtab")
+	if got := waitFocus("Cancel"); got != "Cancel" {
+		t.Errorf("focus after Backtab = %q, want Cancel", got)
+	}
+	if !h.WaitForContent("Cancel") {
+		t.Fatalf("form not redrawn; got:\n%s", h.Content())
+	}
+
+	// Right-aligned buttons with an 8-cell gap (plus the usual one-cell gaps) between Cancel and Save
+	h.AssertSnapshot(t, "")
+}
+
+func TestAppBuilder_Confirm(t *testing.T) {
+	appYAML := `application:
+  globalKeyBindings:
+    - key: "Ctrl+D"
+      action: '{{ confirm "Delete item?" "{{ appendText \"log\" \"deleted\" }}" }}'
+  root:
+    type: pages
+    pages:
+      - name: main
+        ref: main.yaml
+`
+	mainYAML := `type: flex
+items:
+  - primitive:
+      type: textView
+      name: log
+      text: "Items: 1"
+    proportion: 1
+`
+	h := testutil.New(t, testutil.FromBuilder(tviewyaml.NewAppBuilder(writeConfig(t, appYAML, mainYAML))), 40, 10)
+
+	// Cancel closes the first modal without running the action; OK on the second runs it.
+	h.TypeKey("Ctrl+D")
+	if !h.WaitForContent("Delete item?") {
+		t.Fatalf("confirm modal not shown; got:\n%s", h.Content())
+	}
+	h.TypeKey("Tab")
+	h.TypeKey("Enter")
+	h.TypeKey("Ctrl+D")
+	h.TypeKey("Enter")
+	if !h.WaitForContent("deleted") {
+		t.Fatalf("OK should run the action; got:\n%s", h.Content())
+	}
+	if got := h.RegionText(0, 0, 8, 3); got != "Items: 1\ndeleted \n        " {
+		t.Errorf("log = %q, want the action to run once (OK only)", got)
+	}
+	if h.ScreenContains("Delete item?") {
+		t.Errorf("both modals should be closed; got:\n%s", h.Content())
+	}
+}
+
+func TestAppBuilder_PagesGlob(t *testing.T) {
+	appYAML := `application:
+  globalKeyBindings:
+    - key: "Ctrl+A"
+      action: '{{ switchToPage "about" }}'
+  root:
+    type: pages
+    pagesGlob: pages
+    pages:
+      - name: main
+        ref: main.yaml
+`
+	page := func(text string) string {
+		return "type: flex\nitems:\n  - primitive:\n      type: textView\n      text: \"" + text + "\"\n    proportion: 1\n"
+	}
+	dir := writeConfig(t, appYAML, page("Main Page"))
+	if err := os.MkdirAll(filepath.Join(dir, "pages"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	// pages/main.yaml has the same name as the explicit main page, so it is skipped.
+	for name, text := range map[string]string{"about": "About Page", "main": "Shadowed Main"} {
+		if err := os.WriteFile(filepath.Join(dir, "pages", name+".yaml"), []byte(page(text)), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	h := testutil.New(t, testutil.FromBuilder(tviewyaml.NewAppBuilder(dir)), 40, 5)
+
+	if !h.WaitForContent("Main Page") {
+		t.Fatalf("explicit main page should win over pages/main.yaml; got:\n%s", h.Content())
+	}
+	h.TypeKey("Ctrl+A")
+	if !h.WaitForContent("About Page") {
+		t.Errorf("globbed page should be registered as %q; got:\n%s", "about", h.Content())
+	}
+}
+
+// writePagesConfig writes an app.yaml with the given globalKeyBindings YAML and one textView
+// page per entry of texts, declared under names (page-0.yaml, page-1.yaml, ...). Returns the config dir.
+func writePagesConfig(t testing.TB, bindings string, names, texts []string) string {
+	t.Helper()
+	dir := t.TempDir()
+	var app strings.Builder
+	app.WriteString("application:\n" + bindings + "  root:\n    type: pages\n    pages:\n")
+	for i, name := range names {
+		ref := fmt.Sprintf("page-%d.yaml", i)
+		fmt.Fprintf(&app, "      - name: %s\n        ref: %s\n", name, ref)
+		page := "type: flex\nitems:\n  - primitive:\n      type: textView\n      text: \"" + texts[i] + "\"\n    proportion: 1\n"
+		if err := os.WriteFile(filepath.Join(dir, ref), []byte(page), 0644); err != nil {
+			t.Fatalf("write %s: %v", ref, err)
+		}
+	}
+	if err := os.WriteFile(filepath.Join(dir, "app.yaml"), []byte(app.String()), 0644); err != nil {
+		t.Fatalf("write app.yaml: %v", err)
+	}
+	return dir
+}
+
+// manyPages returns n page names and texts; page i is "page<i>" showing "Page number <i>".
+func manyPages(n int) (names, texts []string) {
+	for i := 0; i < n; i++ {
+		names = append(names, fmt.Sprintf("page%d", i))
+		texts = append(texts, fmt.Sprintf("Page number %d", i))
+	}
+	return names, texts
+}
+
+func TestAppBuilder_ManyPagesKeepOrder(t *testing.T) {
+	names, texts := manyPages(30)
+	// main is in the middle; "dup" is declared twice and the later declaration must win.
+	names[15], texts[15] = "main", "Main Page"
+	names[3], texts[3] = "dup", "First dup"
+	names[27], texts[27] = "dup", "Second dup"
+	bindings := `  globalKeyBindings:
+    - key: "Ctrl+N"
+      action: '{{ countPages }}'
+    - key: "Ctrl+D"
+      action: '{{ switchToPage "dup" }}'
+`
+	maxZero := 0
+	pageCount := make(chan int, 1)
+	b := tviewyaml.NewAppBuilder(writePagesConfig(t, bindings, names, texts)).
+		WithTemplateFunction("countPages", 0, &maxZero, nil, func(ctx *template.Context) {
+			pageCount <- ctx.Pages.GetPageCount()
+		})
+	h := testutil.New(t, testutil.FromBuilder(b), 40, 5)
+
+	if !h.WaitForContent("Main Page") {
+		t.Fatalf("main should be the visible page; got:\n%s", h.Content())
+	}
+	h.TypeKey("Ctrl+N")
+	select {
+	case n := <-pageCount:
+		if n != 29 {
+			t.Errorf("page count = %d, want 29 (30 declarations, one duplicate name)", n)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("countPages did not run")
+	}
+	h.TypeKey("Ctrl+D")
+	if !h.WaitForContent("Second dup") {
+		t.Errorf("the later declaration of a duplicate page name should win; got:\n%s", h.Content())
+	}
+}
+
+func BenchmarkAppBuilder_Build50Pages(b *testing.B) {
+	names, texts := manyPages(50)
+	names[0] = "main"
+	dir := writePagesConfig(b, "", names, texts)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		app, pageErrors, err := tviewyaml.NewAppBuilder(dir).Build()
+		if err != nil || len(pageErrors) > 0 {
+			b.Fatalf("Build: %v %v", err, pageErrors)
+		}
+		app.Stop()
+	}
+}
+
+func TestAppBuilder_StateChangeRefreshesPromptly(t *testing.T) {
+	appYAML := `application:
+  globalKeyBindings:
+    - key: "Ctrl+U"
+      action: '{{ updateStatus }}'
+  root:
+    type: pages
+    pages:
+      - name: main
+        ref: main.yaml
+`
+	mainYAML := `type: flex
+items:
+  - primitive:
+      type: textView
+      text: 'Status: {{ bindState status }}'
+    proportion: 1
+`
+	maxZero := 0
+	changed := make(chan time.Time, 1)
+	b := tviewyaml.NewAppBuilder(writeConfig(t, appYAML, mainYAML)).
+		WithTemplateFunction("updateStatus", 0, &maxZero, nil, func(ctx *template.Context) {
+			ctx.SetStateDirect("status", "ready")
+			changed <- time.Now()
+		})
+	h := testutil.New(t, testutil.FromBuilder(b), 40, 3)
+
+	h.TypeKey("Ctrl+U")
+	if !h.WaitForContent("Status: ready") {
+		t.Fatalf("bound view should refresh after the state change; got:\n%s", h.Content())
+	}
+	// The refresh loop wakes on the change rather than on a polling tick (previously 150ms).
+	if elapsed := time.Since(<-changed); elapsed > 100*time.Millisecond {
+		t.Errorf("refresh took %v after the state change, want it to run without waiting for a tick", elapsed)
+	}
+}
@@ -1,73 +1,399 @@
 package config
 
 import (
+	"bytes"
+	"errors"
 	"fmt"
+	"io"
+	"io/fs"
 	"os"
 	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
 
 	"gopkg.in/yaml.v3"
 )
 
-// Loader handles loading YAML configuration files
+// Loader handles loading YAML configuration files.
+// Parsed page configs are cached by path, so loading the same page again (e.g. for template
+// validation and then for building) does not re-read or re-parse the file. Cached configs are
+// shared between callers and must not be modified; call ClearCache before reloading changed files.
+//
+// A loader may search several base paths (NewLoaderDirs); the first one containing a file wins.
 type Loader struct {
-	basePath string
+	basePaths []string // search path, in order of precedence
+
+	mu       sync.Mutex
+	pages    map[string]*PageConfig                 // cleaned file path -> parsed page config
+	inline   map[string]*PageConfig                 // cleaned ref -> page defined in the app file (AppConfig.Pages)
+	readFile func(name string) ([]byte, error)      // os.ReadFile or fs.ReadFile; replaced in tests to count reads
+	stat     func(name string) (fs.FileInfo, error) // os.Stat or fs.Stat
+	glob     func(pattern string) ([]string, error) // filepath.Glob or fs.Glob
+
+	resolvers []valueResolver // applied to string values at load time (AddValueResolver)
+}
+
+// valueResolver resolves string values of the form "prefix:key" (see AddValueResolver).
+type valueResolver struct {
+	prefix  string
+	resolve func(key string) (string, error)
 }
 
 // NewLoader creates a new config loader with a base path
 func NewLoader(basePath string) *Loader {
-	return &Loader{basePath: basePath}
+	return NewLoaderDirs(basePath)
+}
+
+// NewLoaderDirs creates a config loader that searches several base paths in order, e.g. a user's
+// ~/.config/myapp before the app's bundled defaults. The app file, overlays, and page refs are
+// each read from the first base path where they exist, so an override directory only needs the
+// files it changes. Files found in none of them resolve against the first base path.
+func NewLoaderDirs(basePaths ...string) *Loader {
+	if len(basePaths) == 0 {
+		basePaths = []string{""}
+	}
+	return &Loader{
+		basePaths: basePaths,
+		pages:     make(map[string]*PageConfig),
+		readFile:  os.ReadFile,
+		stat:      os.Stat,
+		glob:      filepath.Glob,
+	}
+}
+
+// NewLoaderFS creates a config loader that reads files from fsys instead of the disk, e.g. a
+// config directory embedded with go:embed. basePath is a slash-separated path within fsys
+// ("" or "." for its root). Paths follow fs.FS rules, so absolute refs and refs leaving fsys fail.
+func NewLoaderFS(fsys fs.FS, basePath string) *Loader {
+	if basePath == "" {
+		basePath = "."
+	}
+	return &Loader{
+		basePaths: []string{basePath},
+		pages:     make(map[string]*PageConfig),
+		readFile:  func(name string) ([]byte, error) { return fs.ReadFile(fsys, filepath.ToSlash(name)) },
+		stat:      func(name string) (fs.FileInfo, error) { return fs.Stat(fsys, filepath.ToSlash(name)) },
+		glob:      func(pattern string) ([]string, error) { return fs.Glob(fsys, filepath.ToSlash(pattern)) },
+	}
+}
+
+// path returns the path of ref under the first base path where it exists, or under the first
+// base path if it exists in none of them.
+func (l *Loader) path(ref string) string {
+	if len(l.basePaths) > 1 {
+		for _, dir := range l.basePaths {
+			path := filepath.Join(dir, ref)
+			if _, err := l.stat(path); err == nil {
+				return path
+			}
+		}
+	}
+	return filepath.Join(l.basePaths[0], ref)
+}
+
+// AddValueResolver makes the loader replace string values of the form "prefix:key" with
+// resolve(key) when it loads the app config (after overlays are merged) and page files, e.g. a
+// "secret" resolver backed by a secrets manager turns `password: secret:DB_PASS` into the secret.
+// Only whole values are resolved, not text inside longer strings, and resolved values are not
+// resolved again. Resolvers are tried in the order added; the first matching prefix wins. A resolver
+// error fails the load. Add resolvers before loading; cached pages are not re-resolved.
+func (l *Loader) AddValueResolver(prefix string, resolve func(key string) (string, error)) {
+	l.resolvers = append(l.resolvers, valueResolver{prefix: prefix + ":", resolve: resolve})
+}
+
+// resolveValues applies the value resolvers to every string scalar under node (not mapping keys).
+func (l *Loader) resolveValues(node *yaml.Node) error {
+	switch node.Kind {
+	case yaml.DocumentNode, yaml.SequenceNode:
+		for _, child := range node.Content {
+			if err := l.resolveValues(child); err != nil {
+				return err
+			}
+		}
+	case yaml.MappingNode:
+		for i := 1; i < len(node.Content); i += 2 {
+			if err := l.resolveValues(node.Content[i]); err != nil {
+				return err
+			}
+		}
+	case yaml.ScalarNode:
+		if node.ShortTag() != "!!str" {
+			return nil
+		}
+		for _, r := range l.resolvers {
+			key, ok := strings.CutPrefix(node.Value, r.prefix)
+			if !ok {
+				continue
+			}
+			value, err := r.resolve(key)
+			if err != nil {
+				return fmt.Errorf("line %d: failed to resolve %q: %w", node.Line, node.Value, err)
+			}
+			node.Value = value
+			return nil
+		}
+	}
+	return nil
+}
+
+// ClearCache discards all cached page configs so the next loads read the files again.
+func (l *Loader) ClearCache() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.pages = make(map[string]*PageConfig)
 }
 
-// LoadApp loads the application configuration file
-func (l *Loader) LoadApp(filename string) (*AppConfig, error) {
-	path := filepath.Join(l.basePath, filename)
-	data, err := os.ReadFile(path)
+// LoadApp loads the application configuration file. Pages defined in its pages map become
+// available to LoadPage and RefExists under their refs, ahead of files on disk. The file may hold
+// several YAML documents separated by "---"; later documents add to the pages map (the first
+// document that defines application settings wins).
+//
+// Each overlay file (found on the search path unless absolute) is then deep-merged onto the first
+// document, in order: mappings merge key by key, while scalars and sequences (such as
+// globalKeyBindings or root.pages) replace the base value as a whole. Set a key to null to clear it.
+func (l *Loader) LoadApp(filename string, overlays ...string) (*AppConfig, error) {
+	path := l.path(filename)
+	docs, err := l.readAppDocuments(path)
 	if err != nil {
-		return nil, fmt.Errorf("failed to read app config %s: %w", path, err)
+		return nil, err
+	}
+	for _, overlay := range overlays {
+		overlayPath := overlay
+		if !filepath.IsAbs(overlay) {
+			overlayPath = l.path(overlay)
+		}
+		overlayDocs, err := l.readAppDocuments(overlayPath)
+		if err != nil {
+			return nil, err
+		}
+		for _, doc := range overlayDocs {
+			if len(docs) == 0 {
+				docs = append(docs, doc)
+				continue
+			}
+			mergeNodes(docs[0], doc)
+		}
 	}
 
 	var config AppConfig
-	if err := yaml.Unmarshal(data, &config); err != nil {
-		return nil, fmt.Errorf("failed to parse app config %s: %w", path, err)
+	for i, doc := range docs {
+		if err := l.resolveValues(doc); err != nil {
+			return nil, fmt.Errorf("app config %s: %w", path, err)
+		}
+		var next AppConfig
+		if err := doc.Decode(&next); err != nil {
+			if len(overlays) > 0 {
+				return nil, fmt.Errorf("failed to parse app config %s with overlays %v: %w", path, overlays, err)
+			}
+			return nil, fmt.Errorf("failed to parse app config %s: %w", path, err)
+		}
+		if i == 0 {
+			config.Application = next.Application
+		}
+		for ref, page := range next.Pages {
+			if _, dup := config.Pages[ref]; dup {
+				return nil, fmt.Errorf("app config %s: page %q is defined more than once", path, ref)
+			}
+			if page == nil {
+				return nil, fmt.Errorf("app config %s: page %q is empty", path, ref)
+			}
+			if config.Pages == nil {
+				config.Pages = make(map[string]*PageConfig)
+			}
+			config.Pages[ref] = page
+		}
 	}
 
+	l.mu.Lock()
+	l.inline = make(map[string]*PageConfig, len(config.Pages))
+	for ref, page := range config.Pages {
+		l.inline[filepath.Clean(ref)] = page
+	}
+	l.mu.Unlock()
 	return &config, nil
 }
 
-// RefExists returns true if the page ref file exists under the loader's base path.
+// readAppDocuments reads an app config file and parses each of its YAML documents into a node.
+func (l *Loader) readAppDocuments(path string) ([]*yaml.Node, error) {
+	data, err := l.readFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read app config %s: %w", path, err)
+	}
+	var docs []*yaml.Node
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	for {
+		var doc yaml.Node
+		if err := dec.Decode(&doc); errors.Is(err, io.EOF) {
+			return docs, nil
+		} else if err != nil {
+			return nil, fmt.Errorf("failed to parse app config %s: %w", path, err)
+		}
+		docs = append(docs, &doc)
+	}
+}
+
+// mergeNodes deep-merges src onto dst: mappings merge key by key (recursively); any other value
+// (a scalar, a sequence, or a mapping replacing a non-mapping) replaces the value in dst.
+func mergeNodes(dst, src *yaml.Node) {
+	if src.Kind == yaml.DocumentNode {
+		if len(src.Content) == 0 {
+			return // empty overlay document
+		}
+		src = src.Content[0]
+	}
+	if dst.Kind == yaml.DocumentNode && len(dst.Content) > 0 {
+		dst = dst.Content[0]
+	}
+	if dst.Kind != yaml.MappingNode || src.Kind != yaml.MappingNode {
+		*dst = *src
+		return
+	}
+	for i := 0; i+1 < len(src.Content); i += 2 {
+		key, value := src.Content[i], src.Content[i+1]
+		found := false
+		for j := 0; j+1 < len(dst.Content); j += 2 {
+			if dst.Content[j].Value == key.Value {
+				mergeNodes(dst.Content[j+1], value)
+				found = true
+				break
+			}
+		}
+		if !found {
+			dst.Content = append(dst.Content, key, value)
+		}
+	}
+}
+
+// inlinePage returns the page defined for ref in the app file, if any.
+func (l *Loader) inlinePage(ref string) (*PageConfig, bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	page, ok := l.inline[filepath.Clean(ref)]
+	return page, ok
+}
+
+// RefExists returns true if the page ref is defined in the app file or exists under any of the loader's base paths.
 func (l *Loader) RefExists(ref string) bool {
-	path := filepath.Join(l.basePath, ref)
-	_, err := os.Stat(path)
-	return err == nil
+	if _, ok := l.inlinePage(ref); ok {
+		return true
+	}
+	for _, dir := range l.basePaths {
+		if _, err := l.stat(filepath.Join(dir, ref)); err == nil {
+			return true
+		}
+	}
+	return false
 }
 
-// LoadPage loads a page configuration file
-func (l *Loader) LoadPage(ref string) (*PageConfig, error) {
-	path := filepath.Join(l.basePath, ref)
-	data, err := os.ReadFile(path)
-	if err != nil {
-		return nil, fmt.Errorf("failed to read page config %s: %w", path, err)
+// ResolveRef returns the path, relative to the base path, of a ref found in a page file located
+// in fromDir (also relative to the base path; "" or "." is the base path itself). The ref is tried
+// relative to fromDir first, so pages in subfolders can use refs such as "../shared/footer.yaml".
+// If no file exists there, the ref is resolved against the base path as before. Absolute refs are
+// returned unchanged.
+func (l *Loader) ResolveRef(fromDir, ref string) string {
+	if filepath.IsAbs(ref) || fromDir == "" || fromDir == "." {
+		return ref
 	}
+	if rel := filepath.Join(fromDir, ref); l.RefExists(rel) {
+		return rel
+	}
+	return ref
+}
 
-	var config PageConfig
-	if err := yaml.Unmarshal(data, &config); err != nil {
-		return nil, fmt.Errorf("failed to parse page config %s: %w", path, err)
+// GlobPages returns a page ref for each file matching pattern, relative to the base path, in
+// lexical order. If pattern names a directory, its *.yaml files are used. Each page is named
+// after its file without the extension (e.g. "pages/settings.yaml" becomes "settings"). With
+// several base paths, matches from all of them are combined and each ref appears once.
+func (l *Loader) GlobPages(pattern string) ([]PageRef, error) {
+	var refs []PageRef
+	seen := make(map[string]bool)
+	for _, dir := range l.basePaths {
+		dirPattern := pattern
+		if info, err := l.stat(filepath.Join(dir, pattern)); err == nil && info.IsDir() {
+			dirPattern = filepath.Join(pattern, "*.yaml")
+		}
+		matches, err := l.glob(filepath.Join(dir, dirPattern))
+		if err != nil {
+			return nil, fmt.Errorf("invalid pages glob %q: %w", pattern, err)
+		}
+		for _, match := range matches {
+			if info, err := l.stat(match); err != nil || info.IsDir() {
+				continue
+			}
+			ref, err := filepath.Rel(dir, match)
+			if err != nil {
+				return nil, fmt.Errorf("pages glob match %s: %w", match, err)
+			}
+			if seen[ref] {
+				continue
+			}
+			seen[ref] = true
+			base := filepath.Base(ref)
+			refs = append(refs, PageRef{Name: strings.TrimSuffix(base, filepath.Ext(base)), Ref: ref})
+		}
 	}
+	sort.Slice(refs, func(i, j int) bool { return refs[i].Ref < refs[j].Ref })
+	return refs, nil
+}
 
-	return &config, nil
+// LoadPage loads a page configuration file from the first base path containing ref, or returns the page defined for ref in the app file's
+// pages map if there is one. The result is cached; see Loader.
+func (l *Loader) LoadPage(ref string) (*PageConfig, error) {
+	if page, ok := l.inlinePage(ref); ok {
+		return page, nil
+	}
+	return l.loadPageFile(l.path(ref))
 }
 
-// LoadPageDirect loads a page config from an absolute or relative path
+// LoadPageDirect loads a page config from an absolute or relative path. The result is cached; see Loader.
 func (l *Loader) LoadPageDirect(path string) (*PageConfig, error) {
-	data, err := os.ReadFile(path)
+	return l.loadPageFile(path)
+}
+
+// loadPageFile returns the cached config for path, reading and parsing the file on first use.
+// Errors are not cached, so a missing or invalid file is retried on the next load. Safe for
+// concurrent use; the lock is not held while reading, so different files load in parallel.
+func (l *Loader) loadPageFile(path string) (*PageConfig, error) {
+	key := filepath.Clean(path)
+	l.mu.Lock()
+	cfg, ok := l.pages[key]
+	l.mu.Unlock()
+	if ok {
+		return cfg, nil
+	}
+
+	data, err := l.readFile(path)
 	if err != nil {
 		return nil, fmt.Errorf("failed to read page config %s: %w", path, err)
 	}
 
 	var config PageConfig
-	if err := yaml.Unmarshal(data, &config); err != nil {
-		return nil, fmt.Errorf("failed to parse page config %s: %w", path, err)
+	if len(l.resolvers) == 0 {
+		if err := yaml.Unmarshal(data, &config); err != nil {
+			return nil, fmt.Errorf("failed to parse page config %s: %w", path, err)
+		}
+	} else {
+		var doc yaml.Node
+		if err := yaml.Unmarshal(data, &doc); err != nil {
+			return nil, fmt.Errorf("failed to parse page config %s: %w", path, err)
+		}
+		if err := l.resolveValues(&doc); err != nil {
+			return nil, fmt.Errorf("page config %s: %w", path, err)
+		}
+		if doc.Kind != 0 {
+			if err := doc.Decode(&config); err != nil {
+				return nil, fmt.Errorf("failed to parse page config %s: %w", path, err)
+			}
+		}
 	}
 
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if cached, ok := l.pages[key]; ok {
+		// Another goroutine loaded the same file first; share its result.
+		return cached, nil
+	}
+	l.pages[key] = &config
 	return &config, nil
 }
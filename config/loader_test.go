@@ -1,10 +1,12 @@
 package config
 
 import (
+	"fmt"
 	"os"
 	"path/filepath"
 	"strings"
 	"testing"
+	"testing/fstest"
 )
 
 func TestLoadApp(t *testing.T) {
@@ -152,6 +154,37 @@ listItems:
 			wantErr: true,
 			errContains: "failed to read page config",
 		},
+		{
+			name: "regions as bool and as list",
+			setup: func() string {
+				ref := "regions.yaml"
+				yaml := `type: flex
+items:
+  - primitive:
+      type: textView
+      regions: true
+  - primitive:
+      type: textView
+      regions:
+        - id: home
+          text: Home
+          color: yellow
+        - id: help
+          text: Help
+`
+				if err := os.WriteFile(filepath.Join(tmpDir, ref), []byte(yaml), 0644); err != nil {
+					t.Fatalf("Failed to create test file: %v", err)
+				}
+				return ref
+			},
+			wantErr: false,
+			validate: func(cfg *PageConfig) bool {
+				tagged, listed := cfg.Items[0].Primitive.Regions, cfg.Items[1].Primitive.Regions
+				return tagged.Enabled && len(tagged.Items) == 0 &&
+					listed.Enabled && len(listed.Items) == 2 &&
+					listed.Items[0] == Region{ID: "home", Text: "Home", Color: "yellow"}
+			},
+		},
 		{
 			name: "invalid YAML",
 			setup: func() string {
@@ -222,6 +255,152 @@ listItems:
 	}
 }
 
+func TestResolveRef(t *testing.T) {
+	tmpDir := t.TempDir()
+	for _, file := range []string{"pages/settings/general.yaml", "pages/shared/footer.yaml", "footer.yaml"} {
+		path := filepath.Join(tmpDir, file)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("Failed to create test dir: %v", err)
+		}
+		if err := os.WriteFile(path, []byte("type: flex\n"), 0644); err != nil {
+			t.Fatalf("Failed to create test file: %v", err)
+		}
+	}
+	loader := NewLoader(tmpDir)
+
+	tests := []struct {
+		name    string
+		fromDir string
+		ref     string
+		want    string
+	}{
+		{"base path is unchanged", ".", "footer.yaml", "footer.yaml"},
+		{"empty dir is the base path", "", "pages/shared/footer.yaml", "pages/shared/footer.yaml"},
+		{"sibling in same folder", "pages/settings", "general.yaml", "pages/settings/general.yaml"},
+		{"parent-relative ref", "pages/settings", "../shared/footer.yaml", "pages/shared/footer.yaml"},
+		{"falls back to base path", "pages/settings", "footer.yaml", "footer.yaml"},
+		{"missing file falls back to ref", "pages/settings", "missing.yaml", "missing.yaml"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := loader.ResolveRef(tt.fromDir, tt.ref); got != tt.want {
+				t.Errorf("ResolveRef(%q, %q) = %q, want %q", tt.fromDir, tt.ref, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGlobPages(t *testing.T) {
+	tmpDir := t.TempDir()
+	for _, file := range []string{"app.yaml", "main.yaml", "pages/about.yaml", "pages/help.yaml", "pages/notes.txt", "pages/nested/deep.yaml"} {
+		path := filepath.Join(tmpDir, file)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("Failed to create test dir: %v", err)
+		}
+		if err := os.WriteFile(path, []byte("type: flex\n"), 0644); err != nil {
+			t.Fatalf("Failed to create test file: %v", err)
+		}
+	}
+	loader := NewLoader(tmpDir)
+
+	tests := []struct {
+		name    string
+		pattern string
+		want    []PageRef
+		wantErr bool
+	}{
+		{
+			name:    "directory uses its yaml files",
+			pattern: "pages",
+			want:    []PageRef{{Name: "about", Ref: "pages/about.yaml"}, {Name: "help", Ref: "pages/help.yaml"}},
+		},
+		{
+			name:    "glob pattern",
+			pattern: "pages/h*.yaml",
+			want:    []PageRef{{Name: "help", Ref: "pages/help.yaml"}},
+		},
+		{
+			name:    "nested directories are not descended",
+			pattern: "pages/*",
+			want:    []PageRef{{Name: "about", Ref: "pages/about.yaml"}, {Name: "help", Ref: "pages/help.yaml"}, {Name: "notes", Ref: "pages/notes.txt"}},
+		},
+		{
+			name:    "no matches",
+			pattern: "missing/*.yaml",
+			want:    []PageRef{},
+		},
+		{
+			name:    "malformed pattern",
+			pattern: "pages/[",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := loader.GlobPages(tt.pattern)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("GlobPages(%q) error = %v, wantErr %v", tt.pattern, err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("GlobPages(%q) = %v, want %v", tt.pattern, got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Errorf("GlobPages(%q)[%d] = %v, want %v", tt.pattern, i, got[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestLoadPageCache(t *testing.T) {
+	tmpDir := t.TempDir()
+	path := filepath.Join(tmpDir, "page.yaml")
+	if err := os.WriteFile(path, []byte("type: flex\ntitle: First\n"), 0644); err != nil {
+		t.Fatalf("Failed to create test file: %v", err)
+	}
+	loader := NewLoader(tmpDir)
+	reads := 0
+	loader.readFile = func(name string) ([]byte, error) {
+		reads++
+		return os.ReadFile(name)
+	}
+
+	first, err := loader.LoadPage("page.yaml")
+	if err != nil {
+		t.Fatalf("LoadPage: %v", err)
+	}
+	second, err := loader.LoadPage("./page.yaml")
+	if err != nil {
+		t.Fatalf("LoadPage: %v", err)
+	}
+	direct, err := loader.LoadPageDirect(path)
+	if err != nil {
+		t.Fatalf("LoadPageDirect: %v", err)
+	}
+	if reads != 1 {
+		t.Errorf("file read %d times, want 1 (later loads should hit the cache)", reads)
+	}
+	if second != first || direct != first {
+		t.Error("cached loads should return the same parsed config")
+	}
+
+	if err := os.WriteFile(path, []byte("type: flex\ntitle: Second\n"), 0644); err != nil {
+		t.Fatalf("Failed to update test file: %v", err)
+	}
+	loader.ClearCache()
+	reloaded, err := loader.LoadPage("page.yaml")
+	if err != nil {
+		t.Fatalf("LoadPage after ClearCache: %v", err)
+	}
+	if reads != 2 || reloaded.Title != "Second" {
+		t.Errorf("after ClearCache: reads = %d, title = %q; want 2, %q", reads, reloaded.Title, "Second")
+	}
+}
+
 func TestLoadPageDirect(t *testing.T) {
 	tmpDir := t.TempDir()
 
@@ -384,3 +563,325 @@ func TestRefExists(t *testing.T) {
 		})
 	}
 }
+
+func TestLoaderFS(t *testing.T) {
+	fsys := fstest.MapFS{
+		"config/app.yaml": {Data: []byte(`application:
+  name: "Embedded"
+  root:
+    type: pages
+    pages:
+      - name: main
+        ref: main.yaml
+`)},
+		"config/main.yaml":        {Data: []byte("type: list\ntitle: Main\n")},
+		"config/pages/about.yaml": {Data: []byte("type: textView\ntext: About\n")},
+		"config/pages/help.yaml":  {Data: []byte("type: textView\ntext: Help\n")},
+		"config/shared/foot.yaml": {Data: []byte("type: box\n")},
+	}
+	loader := NewLoaderFS(fsys, "config")
+
+	app, err := loader.LoadApp("app.yaml")
+	if err != nil {
+		t.Fatalf("LoadApp() error = %v", err)
+	}
+	if app.Application.Name != "Embedded" {
+		t.Errorf("Application.Name = %q, want Embedded", app.Application.Name)
+	}
+	page, err := loader.LoadPage("main.yaml")
+	if err != nil {
+		t.Fatalf("LoadPage() error = %v", err)
+	}
+	if page.Type != "list" || page.Title != "Main" {
+		t.Errorf("LoadPage() = %+v, want list titled Main", page)
+	}
+	if _, err := loader.LoadPage("missing.yaml"); err == nil {
+		t.Error("LoadPage(missing.yaml) should fail")
+	}
+
+	if !loader.RefExists("pages/about.yaml") || loader.RefExists("pages/missing.yaml") {
+		t.Error("RefExists does not reflect the files in the FS")
+	}
+	if got := loader.ResolveRef("pages", "../shared/foot.yaml"); got != "shared/foot.yaml" {
+		t.Errorf("ResolveRef() = %q, want shared/foot.yaml", got)
+	}
+
+	refs, err := loader.GlobPages("pages")
+	if err != nil {
+		t.Fatalf("GlobPages() error = %v", err)
+	}
+	want := []PageRef{{Name: "about", Ref: "pages/about.yaml"}, {Name: "help", Ref: "pages/help.yaml"}}
+	if len(refs) != len(want) || refs[0] != want[0] || refs[1] != want[1] {
+		t.Errorf("GlobPages() = %v, want %v", refs, want)
+	}
+
+	root := NewLoaderFS(fsys, "")
+	if _, err := root.LoadPage("config/main.yaml"); err != nil {
+		t.Errorf("LoadPage from the FS root: %v", err)
+	}
+}
+
+func TestLoaderDirs(t *testing.T) {
+	user, defaults := t.TempDir(), t.TempDir()
+	write := func(dir, name, content string) {
+		t.Helper()
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("Failed to create directory: %v", err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to write %s: %v", name, err)
+		}
+	}
+	write(defaults, "app.yaml", "application:\n  name: Defaults\n")
+	write(defaults, "main.yaml", "type: list\ntitle: Default main\n")
+	write(defaults, "pages/about.yaml", "type: textView\ntitle: Default about\n")
+	write(defaults, "pages/help.yaml", "type: textView\ntitle: Default help\n")
+	write(user, "main.yaml", "type: list\ntitle: User main\n")
+	write(user, "pages/about.yaml", "type: textView\ntitle: User about\n")
+	write(user, "pages/extra.yaml", "type: textView\ntitle: User extra\n")
+	loader := NewLoaderDirs(user, defaults)
+
+	// The app file exists only in the defaults, so it falls through
+	app, err := loader.LoadApp("app.yaml")
+	if err != nil {
+		t.Fatalf("LoadApp() error = %v", err)
+	}
+	if app.Application.Name != "Defaults" {
+		t.Errorf("Application.Name = %q, want Defaults", app.Application.Name)
+	}
+
+	for ref, want := range map[string]string{
+		"main.yaml":        "User main",    // overridden
+		"pages/about.yaml": "User about",   // overridden
+		"pages/help.yaml":  "Default help", // falls through
+		"pages/extra.yaml": "User extra",   // only in the user directory
+	} {
+		page, err := loader.LoadPage(ref)
+		if err != nil {
+			t.Errorf("LoadPage(%q) error = %v", ref, err)
+			continue
+		}
+		if page.Title != want {
+			t.Errorf("LoadPage(%q).Title = %q, want %q", ref, page.Title, want)
+		}
+		if !loader.RefExists(ref) {
+			t.Errorf("RefExists(%q) = false, want true", ref)
+		}
+	}
+	if loader.RefExists("pages/missing.yaml") {
+		t.Error("RefExists(pages/missing.yaml) = true, want false")
+	}
+	if _, err := loader.LoadPage("missing.yaml"); err == nil || !strings.Contains(err.Error(), user) {
+		t.Errorf("LoadPage(missing.yaml) error = %v, want a path in the first directory", err)
+	}
+
+	refs, err := loader.GlobPages("pages")
+	if err != nil {
+		t.Fatalf("GlobPages() error = %v", err)
+	}
+	want := []PageRef{
+		{Name: "about", Ref: "pages/about.yaml"},
+		{Name: "extra", Ref: "pages/extra.yaml"},
+		{Name: "help", Ref: "pages/help.yaml"},
+	}
+	if fmt.Sprint(refs) != fmt.Sprint(want) {
+		t.Errorf("GlobPages() = %v, want %v", refs, want)
+	}
+}
+
+func TestLoadAppInlinePages(t *testing.T) {
+	tmpDir := t.TempDir()
+	appYAML := `application:
+  name: "One file"
+  root:
+    type: pages
+    pages:
+      - name: main
+        ref: main
+      - name: about
+        ref: about.yaml
+pages:
+  main:
+    type: list
+    title: Inline main
+---
+pages:
+  help:
+    type: textView
+    text: Inline help
+`
+	if err := os.WriteFile(filepath.Join(tmpDir, "app.yaml"), []byte(appYAML), 0644); err != nil {
+		t.Fatalf("Failed to create test file: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(tmpDir, "about.yaml"), []byte("type: textView\ntext: From disk\n"), 0644); err != nil {
+		t.Fatalf("Failed to create test file: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(tmpDir, "main"), []byte("type: flex\n"), 0644); err != nil {
+		t.Fatalf("Failed to create test file: %v", err)
+	}
+	loader := NewLoader(tmpDir)
+
+	app, err := loader.LoadApp("app.yaml")
+	if err != nil {
+		t.Fatalf("LoadApp() error = %v", err)
+	}
+	if app.Application.Name != "One file" || len(app.Application.Root.Pages) != 2 {
+		t.Errorf("Application = %+v, want the first document's settings", app.Application)
+	}
+	if len(app.Pages) != 2 {
+		t.Errorf("Pages = %v, want main and help from both documents", app.Pages)
+	}
+
+	tests := []struct {
+		ref      string
+		wantType string
+	}{
+		{ref: "main", wantType: "list"},           // in-file definition wins over the file named main
+		{ref: "help", wantType: "textView"},       // defined in the second document
+		{ref: "about.yaml", wantType: "textView"}, // falls back to the disk
+	}
+	for _, tt := range tests {
+		page, err := loader.LoadPage(tt.ref)
+		if err != nil {
+			t.Errorf("LoadPage(%q) error = %v", tt.ref, err)
+			continue
+		}
+		if page.Type != tt.wantType {
+			t.Errorf("LoadPage(%q).Type = %q, want %q", tt.ref, page.Type, tt.wantType)
+		}
+	}
+	if !loader.RefExists("help") || loader.RefExists("missing") {
+		t.Error("RefExists does not reflect the in-file pages")
+	}
+
+	dup := "application:\n  root:\n    type: pages\npages:\n  main:\n    type: list\n---\npages:\n  main:\n    type: flex\n"
+	if err := os.WriteFile(filepath.Join(tmpDir, "dup.yaml"), []byte(dup), 0644); err != nil {
+		t.Fatalf("Failed to create test file: %v", err)
+	}
+	if _, err := loader.LoadApp("dup.yaml"); err == nil || !strings.Contains(err.Error(), `page "main" is defined more than once`) {
+		t.Errorf("LoadApp(dup.yaml) error = %v, want duplicate page error", err)
+	}
+}
+
+func TestLoadAppOverlay(t *testing.T) {
+	tmpDir := t.TempDir()
+	files := map[string]string{
+		"app.yaml": `application:
+  name: "Base"
+  enableMouse: true
+  globalKeyBindings:
+    - key: "Ctrl+Q"
+      action: '{{ stopApp }}'
+    - key: "F1"
+      action: '{{ showKeyHelp }}'
+  root:
+    type: pages
+    pages:
+      - name: main
+        ref: main.yaml
+`,
+		"app.dev.yaml": `application:
+  name: "Dev"
+  globalKeyBindings:
+    - key: "F5"
+      action: '{{ switchToPage "debug" }}'
+`,
+		"no-mouse.yaml": `application:
+  enableMouse: false
+`,
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(tmpDir, name), []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to create test file: %v", err)
+		}
+	}
+	loader := NewLoader(tmpDir)
+
+	app, err := loader.LoadApp("app.yaml", "app.dev.yaml", "no-mouse.yaml")
+	if err != nil {
+		t.Fatalf("LoadApp() error = %v", err)
+	}
+	if app.Application.Name != "Dev" {
+		t.Errorf("Name = %q, want the overlay's scalar Dev", app.Application.Name)
+	}
+	if app.Application.EnableMouse == nil || *app.Application.EnableMouse {
+		t.Errorf("EnableMouse = %v, want false from the second overlay", app.Application.EnableMouse)
+	}
+	if got := app.Application.GlobalKeyBindings; len(got) != 1 || got[0].Key != "F5" {
+		t.Errorf("GlobalKeyBindings = %+v, want the overlay's list to replace the base list", got)
+	}
+	if got := app.Application.Root.Pages; len(got) != 1 || got[0].Ref != "main.yaml" {
+		t.Errorf("Root.Pages = %+v, want the base pages kept", got)
+	}
+
+	base, err := loader.LoadApp("app.yaml")
+	if err != nil {
+		t.Fatalf("LoadApp() without overlays error = %v", err)
+	}
+	if base.Application.Name != "Base" || len(base.Application.GlobalKeyBindings) != 2 {
+		t.Errorf("LoadApp() without overlays = %+v, want the base config unchanged", base.Application)
+	}
+
+	if _, err := loader.LoadApp("app.yaml", "missing.yaml"); err == nil {
+		t.Error("LoadApp() with a missing overlay should fail")
+	}
+}
+
+func TestLoaderValueResolver(t *testing.T) {
+	fsys := fstest.MapFS{
+		"app.yaml": {Data: []byte(`application:
+  name: secret:APP_NAME
+  root:
+    type: pages
+    pages:
+      - name: main
+        ref: main.yaml
+`)},
+		"main.yaml": {Data: []byte(`type: form
+formItems:
+  - type: inputfield
+    label: "Password"
+    value: secret:DB_PASS
+  - type: inputfield
+    label: "Not a token: secret:DB_PASS"
+    value: env:HOME
+`)},
+		"broken.yaml": {Data: []byte("type: flex\ntitle: secret:MISSING\n")},
+	}
+	secrets := map[string]string{"APP_NAME": "Vault app", "DB_PASS": "hunter2"}
+	loader := NewLoaderFS(fsys, "")
+	loader.AddValueResolver("secret", func(key string) (string, error) {
+		if v, ok := secrets[key]; ok {
+			return v, nil
+		}
+		return "", fmt.Errorf("no secret %s", key)
+	})
+
+	app, err := loader.LoadApp("app.yaml")
+	if err != nil {
+		t.Fatalf("LoadApp() error = %v", err)
+	}
+	if app.Application.Name != "Vault app" {
+		t.Errorf("Name = %q, want the resolved secret", app.Application.Name)
+	}
+
+	page, err := loader.LoadPage("main.yaml")
+	if err != nil {
+		t.Fatalf("LoadPage() error = %v", err)
+	}
+	if got := page.FormItems[0].Value; got != "hunter2" {
+		t.Errorf("FormItems[0].Value = %q, want hunter2", got)
+	}
+	if got := page.FormItems[1].Label; got != "Not a token: secret:DB_PASS" {
+		t.Errorf("FormItems[1].Label = %q, want text inside a longer string left alone", got)
+	}
+	if got := page.FormItems[1].Value; got != "env:HOME" {
+		t.Errorf("FormItems[1].Value = %q, want values with an unregistered prefix left alone", got)
+	}
+
+	_, err = loader.LoadPage("broken.yaml")
+	if err == nil || !strings.Contains(err.Error(), "no secret MISSING") || !strings.Contains(err.Error(), "line 2") {
+		t.Errorf("LoadPage(broken.yaml) error = %v, want the resolver error with its line", err)
+	}
+}
@@ -1,8 +1,11 @@
 package config
 
+import "gopkg.in/yaml.v3"
+
 // AppConfig represents the top-level application configuration
 type AppConfig struct {
-	Application ApplicationElement `yaml:"application"`
+	Application ApplicationElement     `yaml:"application"`
+	Pages       map[string]*PageConfig `yaml:"pages,omitempty"` // Page configs keyed by ref, so a small app fits in one file; refs are looked up here before the disk
 }
 
 // ApplicationElement contains application-level settings
@@ -10,26 +13,64 @@ type ApplicationElement struct {
 	Name                   string       `yaml:"name,omitempty"`
 	EnableMouse            *bool        `yaml:"enableMouse,omitempty"` // nil = default true
 	GlobalKeyBindings      []KeyBinding `yaml:"globalKeyBindings,omitempty"`
-	EscapePassthroughPages []string     `yaml:"escapePassthroughPages,omitempty"` // pages where Escape is not captured globally (e.g. so form SetCancelFunc runs)
+	EscapePassthroughPages []string     `yaml:"escapePassthroughPages,omitempty"` // pages where Escape is not captured globally (fields of forms with onCancel/onSubmit already pass Escape through)
+	OnMouseCapture         string       `yaml:"onMouseCapture,omitempty"`         // Template expression run for every mouse event (state: __mouseX, __mouseY, __mouseAction)
+	OnStartup              string       `yaml:"onStartup,omitempty"`              // Template expression run once when the app starts, right after the first draw
+	OnTick                 string       `yaml:"onTick,omitempty"`                 // Template expression run every tickInterval on the main goroutine
+	TickInterval           string       `yaml:"tickInterval,omitempty"`           // Time between onTick runs, e.g. "5s" (required with onTick)
+	Theme                  *Theme       `yaml:"theme,omitempty"`                  // Default colors for every primitive
 	Root                   RootElement `yaml:"root"`
+
+	// FocusGroups: named lists of primitive ids (or names) that the focusNext and focusPrev
+	// builtins cycle focus through, e.g. the panels of a dashboard
+	FocusGroups map[string][]string `yaml:"focusGroups,omitempty"`
+}
+
+// Theme sets default colors for every primitive. The theme is applied first, so a color a
+// primitive sets itself (e.g. a textView's textColor) takes precedence. Unset fields keep tview's defaults.
+type Theme struct {
+	BorderColor        string `yaml:"borderColor,omitempty"`        // Border color of boxes with a border
+	TitleColor         string `yaml:"titleColor,omitempty"`         // Box title color
+	TextColor          string `yaml:"textColor,omitempty"`          // Text color of text views and list items
+	BackgroundColor    string `yaml:"backgroundColor,omitempty"`    // Background color of every box
+	SelectionColor     string `yaml:"selectionColor,omitempty"`     // Background of the selected list item or table row
+	SelectionTextColor string `yaml:"selectionTextColor,omitempty"` // Text color of the selected list item or table row
+	HighlightColor     string `yaml:"highlightColor,omitempty"`     // Color of regions without their own color; tview highlights a region by swapping its text and background colors
+	// borderStyle: Lines of unfocused borders: "single" (default), "rounded", or "thick". tview has no
+	// per-box border characters, so this sets the process-wide tview.Borders from Build until
+	// Application.Stop restores them; other apps drawing meanwhile get the style too, and building
+	// while another app draws is a data race. Focused borders stay double.
+	BorderStyle string `yaml:"borderStyle,omitempty"`
 }
 
 // KeyBinding represents a global keyboard shortcut
 type KeyBinding struct {
-	Key    string `yaml:"key"`    // "Escape", "Ctrl+Q", "F1", etc.
-	Action string `yaml:"action"` // Template expression
+	Key         string `yaml:"key"`                   // "Escape", "Ctrl+Q", "F1", etc.
+	Action      string `yaml:"action"`                // Template expression
+	Passthrough bool   `yaml:"passthrough,omitempty"` // if true, the event still reaches the focused primitive after the action runs
+	Description string `yaml:"description,omitempty"` // shown by showKeyHelp (defaults to the action)
+	// caseSensitive: if true, character keys match only the exact case ("g" and "G" are distinct bindings)
+	CaseSensitive bool `yaml:"caseSensitive,omitempty"`
 }
 
 // RootElement contains the list of pages (or can be any view type in the future)
 type RootElement struct {
 	Type  string    `yaml:"type"` // "pages"
 	Pages []PageRef `yaml:"pages"`
+	// PagesGlob adds every page file matching this pattern (or every *.yaml file in this directory),
+	// relative to the config directory, named after the file without its extension. Pages already
+	// listed in pages (by name or ref) keep their explicit entry.
+	PagesGlob string `yaml:"pagesGlob,omitempty"`
+	// BackgroundColor fills the canvas behind the pages: the areas no primitive draws over, such
+	// as flex spacers. Only the root is colored; tview.Styles is left unchanged.
+	BackgroundColor string `yaml:"backgroundColor,omitempty"`
 }
 
-// PageRef references a page configuration file
+// PageRef references a page configuration file, or carries the page inline
 type PageRef struct {
-	Name string `yaml:"name"`
-	Ref  string `yaml:"ref"` // Path to YAML file
+	Name string      `yaml:"name"`
+	Ref  string      `yaml:"ref"`            // Path to YAML file
+	Page *PageConfig `yaml:"page,omitempty"` // Inline page definition (instead of ref)
 }
 
 // PageConfig represents a single page/screen configuration
@@ -45,16 +86,39 @@ type PageConfig struct {
 	FormItems  []FormItem             `yaml:"formItems,omitempty"`
 	OnSubmit   string                 `yaml:"onSubmit,omitempty"` // Template expression for runFormSubmit (e.g. Submit button)
 	OnCancel   string                 `yaml:"onCancel,omitempty"` // Template expression when form is cancelled (Escape); if unset and OnSubmit set, Escape runs OnSubmit
+	ButtonsAlign string               `yaml:"buttonsAlign,omitempty"` // Form button row alignment: "left" (default), "center", "right"
+	MarkRequired bool                 `yaml:"markRequired,omitempty"` // Append a red "*" to the labels of required form items
 	OnDone     string                 `yaml:"onDone,omitempty"`   // Template expression when user presses Enter/Escape (e.g. page-level table)
 	TableData  *TableData             `yaml:"tableData,omitempty"`
+	// onMouseCapture: Template expression run for mouse events while this page is the front page (after the app-level hook)
+	OnMouseCapture string `yaml:"onMouseCapture,omitempty"`
+	// onShow/onHide: Template expressions run when navigation (switchToPage, goBack, removePage) makes this page the front page or moves away from it
+	OnShow string `yaml:"onShow,omitempty"`
+	OnHide string `yaml:"onHide,omitempty"`
+	// onReady: Template expression run once at startup, right after this page is built and added (e.g. to seed state)
+	OnReady string `yaml:"onReady,omitempty"`
+	// responsiveDirection: Flex items side by side when the page is at least breakpointWidth wide, stacked (as direction: row) when narrower
+	ResponsiveDirection bool `yaml:"responsiveDirection,omitempty"`
+	BreakpointWidth     int  `yaml:"breakpointWidth,omitempty"`
+	// autoShortcuts: Give list items with onSelected but no shortcut the keys 1-9, then a-z, in order
+	AutoShortcuts bool `yaml:"autoShortcuts,omitempty"`
+	// focusOrder: Names of primitives on this page to visit, in order, with Tab (forward) and Backtab/Shift+Tab (backward)
+	FocusOrder []string `yaml:"focusOrder,omitempty"`
 	// TreeView-specific (for page-level type: treeView)
-	OnNodeSelected string     `yaml:"onNodeSelected,omitempty"` // Template expression when a node is selected (state: __selectedNodeText)
+	OnNodeSelected string     `yaml:"onNodeSelected,omitempty"` // Template expression when a node is selected (state: __selectedNodeText, __selectedNodeName, __selectedNodeData)
 	RootNode       string     `yaml:"rootNode,omitempty"`
 	CurrentNode    string     `yaml:"currentNode,omitempty"`
 	Nodes          []TreeNode `yaml:"nodes,omitempty"`
 	// Modal-specific (for page-level type: modal)
 	Text    string        `yaml:"text,omitempty"`    // Modal text content
 	Buttons []ModalButton `yaml:"buttons,omitempty"` // Modal buttons
+	// Modal colors (unset keeps tview's defaults)
+	BackgroundColor       string `yaml:"backgroundColor,omitempty"`
+	TextColor             string `yaml:"textColor,omitempty"`
+	ButtonBackgroundColor string `yaml:"buttonBackgroundColor,omitempty"`
+	ButtonTextColor       string `yaml:"buttonTextColor,omitempty"`
+	// Dialog-specific (type: dialog; also uses text, buttons, backgroundColor, textColor)
+	Width int `yaml:"width,omitempty"` // Outer width in cells including the border (default 50; shrinks to fit the screen)
 	Properties     map[string]interface{} `yaml:",inline"` // Catch-all for other properties
 }
 
@@ -64,26 +128,38 @@ type FlexItem struct {
 	Spacer     bool       `yaml:"spacer,omitempty"` // if true, treat as spacer (nil primitive)
 	FixedSize  int        `yaml:"fixedSize,omitempty"`
 	Proportion int        `yaml:"proportion,omitempty"`
+	MinSize    int        `yaml:"minSize,omitempty"` // Proportional items only: never shrink below this many cells
 	Focus      bool       `yaml:"focus,omitempty"`
 }
 
 // Primitive represents a tview primitive configuration
 type Primitive struct {
+	ID         string `yaml:"id,omitempty"` // stable identifier for the registry and builtins; defaults to name
 	Name       string `yaml:"name,omitempty"`
 	Type       string `yaml:"type"`
 	Border     bool   `yaml:"border,omitempty"`
 	Title      string `yaml:"title,omitempty"`
 	TitleAlign string `yaml:"titleAlign,omitempty"`
+	TitleRight string `yaml:"titleRight,omitempty"` // label right-aligned in the top border (requires border)
 	Text       string `yaml:"text,omitempty"`
 	TextAlign  string `yaml:"textAlign,omitempty"`
 	TextColor  string `yaml:"textColor,omitempty"`
+	// Box-specific properties
+	Drawer string `yaml:"drawer,omitempty"` // Name of a draw function registered with AppBuilder.WithDrawer
 	// TextView-specific properties
 	DynamicColors bool       `yaml:"dynamicColors,omitempty"` // Enable color tags in text
-	Regions       bool       `yaml:"regions,omitempty"`       // Enable region tags in text
+	Regions       Regions    `yaml:"regions,omitempty"`       // true enables region tags in text; a list of regions builds the tagged text
+	ToggleHighlights *bool `yaml:"toggleHighlights,omitempty"` // Selecting a highlighted region again clears it (unset: tview default, off)
+	MaxLines      int        `yaml:"maxLines,omitempty"`      // Discard oldest lines beyond this count (0 = unlimited)
+	Scrollable    *bool      `yaml:"scrollable,omitempty"`    // Allow scrolling (unset: tview default, on)
+	ScrollTo      int        `yaml:"scrollTo,omitempty"`      // Initial first visible line (0-based); requires scrollable
+	ScrollToEnd   bool       `yaml:"scrollToEnd,omitempty"`   // Start scrolled to the end and follow appended text; requires scrollable
+	MaxWidth      int        `yaml:"maxWidth,omitempty"`      // Word-wrap into a column at most this wide, centered horizontally (text centered unless textAlign is set)
+	FillLine      bool       `yaml:"fillLine,omitempty"`      // Pad each line with spaces to the full width so a line's background color spans the row
 	Label         string     `yaml:"label,omitempty"`
 	Checked       bool       `yaml:"checked,omitempty"`
 	OnSelected    string     `yaml:"onSelected,omitempty"` // Template expression
-	OnChanged     string     `yaml:"onChanged,omitempty"`  // Template expression
+	OnChanged     string     `yaml:"onChanged,omitempty"`  // Template expression (TextView: runs when content changes)
 	Items         []FlexItem `yaml:"items,omitempty"`
 	ListItems     []ListItem `yaml:"listItems,omitempty"`
 	Direction     string     `yaml:"direction,omitempty"`
@@ -93,30 +169,73 @@ type Primitive struct {
 	FormItems     []FormItem `yaml:"formItems,omitempty"`
 	OnSubmit      string     `yaml:"onSubmit,omitempty"` // Template expression for runFormSubmit (nested form)
 	OnCancel      string     `yaml:"onCancel,omitempty"` // Template expression when form is cancelled (Escape); if unset and OnSubmit set, Escape runs OnSubmit
+	ButtonsAlign  string     `yaml:"buttonsAlign,omitempty"` // Form button row alignment: "left" (default), "center", "right"
+	MarkRequired  bool       `yaml:"markRequired,omitempty"` // Append a red "*" to the labels of required form items
 	// onDone: Template expression when user presses Enter/Escape (TextView, InputField, Table)
 	OnDone string `yaml:"onDone,omitempty"`
 	// onHighlighted: Template expression when highlighted region changes (TextView with regions; state: __highlightedRegion)
 	OnHighlighted string `yaml:"onHighlighted,omitempty"`
+	// onFocus/onBlur: Template expressions when the primitive gains/loses focus (state: __focusedPrimitive / __blurredPrimitive = name).
+	// Containers (flex, grid, form) hand focus to a child and do not fire them.
+	OnFocus string `yaml:"onFocus,omitempty"`
+	OnBlur  string `yaml:"onBlur,omitempty"`
+	// Button-specific properties (label may bind state, e.g. '{{ bindState runLabel }}')
+	BackgroundColor string `yaml:"backgroundColor,omitempty"` // Button background color when not focused (also the modal background)
+	LabelColor      string `yaml:"labelColor,omitempty"`      // Button label color when not focused
+	Shortcut        string `yaml:"shortcut,omitempty"`        // Key (e.g. "Ctrl+S") that runs onSelected while focus is anywhere on the page
+	Disabled        *bool  `yaml:"disabled,omitempty"`        // Initial disabled state: ignores Enter, clicks, and shortcut; drawn dimmed
+	DisabledWhen    string `yaml:"disabledWhen,omitempty"`    // State key; disabled while its value is truthy (overrides disabled once the key is set)
+	// InputField-specific properties
+	FieldWidth int `yaml:"fieldWidth,omitempty"` // Input field width in cells; unset (0) spans the available width
+	// Flex-specific properties
+	ResponsiveDirection bool `yaml:"responsiveDirection,omitempty"` // Items side by side when the flex is at least breakpointWidth wide, stacked (as direction: row) when narrower
+	BreakpointWidth     int  `yaml:"breakpointWidth,omitempty"`     // Width in cells at which responsiveDirection switches
+	// DropDown-specific properties
+	MaxVisibleOptions int `yaml:"maxVisibleOptions,omitempty"` // Show at most this many options at once when open; the rest scroll
+	// List-specific properties
+	SelectedFocusOnly bool   `yaml:"selectedFocusOnly,omitempty"` // Highlight the selected item only while the list has focus
+	CurrentItem       int    `yaml:"currentItem,omitempty"`       // Index of the initially selected item (negative counts from the end)
+	Offset            int    `yaml:"offset,omitempty"`            // Number of items initially scrolled past
+	Filterable        bool   `yaml:"filterable,omitempty"`        // Filter items by case-insensitive substring as the user types
+	FilterInput       string `yaml:"filterInput,omitempty"`       // Name of an inputField on the page whose text filters the list (default: type into the list itself)
+	ShowShortcutHint  bool   `yaml:"showShortcutHint,omitempty"`  // Append each item's shortcut as "(x)" to its secondary text
+	AutoShortcuts     bool   `yaml:"autoShortcuts,omitempty"`     // Give items with onSelected but no shortcut the keys 1-9, then a-z, in order
 	// Table-specific properties
-	OnCellSelected string   `yaml:"onCellSelected,omitempty"` // Template expression when a cell is selected (state: __selectedCellText, __selectedRow, __selectedCol)
-	Borders        bool     `yaml:"borders,omitempty"`        // Show borders between cells
-	FixedRows      int      `yaml:"fixedRows,omitempty"`      // Number of fixed rows
-	FixedColumns   int      `yaml:"fixedColumns,omitempty"`   // Number of fixed columns
-	ColumnColors   []string `yaml:"columnColors,omitempty"`   // Colors for each column (cycles if fewer colors than columns)
+	OnCellSelected        string   `yaml:"onCellSelected,omitempty"`        // Template expression when a cell is selected (state: __selectedCellText, __selectedRow, __selectedCol)
+	Borders               bool     `yaml:"borders,omitempty"`               // Show borders between cells
+	FixedRows             int      `yaml:"fixedRows,omitempty"`             // Number of fixed rows
+	FixedColumns          int      `yaml:"fixedColumns,omitempty"`          // Number of fixed columns
+	ColumnColors          []string `yaml:"columnColors,omitempty"`          // Colors for each column (cycles if fewer colors than columns)
+	HeaderColor           string   `yaml:"headerColor,omitempty"`           // Header text color (default yellow)
+	HeaderAlign           string   `yaml:"headerAlign,omitempty"`           // Header alignment: "left", "center" (default), "right"
+	HeaderBackgroundColor string   `yaml:"headerBackgroundColor,omitempty"` // Header background color (default: the table's)
+	ShowHeaders           *bool    `yaml:"showHeaders,omitempty"`           // Write columns as a header row (default true); false starts data at row 0
+	MultilineCells        bool     `yaml:"multilineCells,omitempty"`        // Split cell text at newlines over extra table rows and expand tabs
 	// TreeView-specific properties
-	OnNodeSelected string     `yaml:"onNodeSelected,omitempty"` // Template expression when a node is selected (state: __selectedNodeText)
+	OnNodeSelected string     `yaml:"onNodeSelected,omitempty"` // Template expression when a node is selected (state: __selectedNodeText, __selectedNodeName, __selectedNodeData)
 	RootNode       string     `yaml:"rootNode,omitempty"`       // Name of the root node
 	CurrentNode    string     `yaml:"currentNode,omitempty"`    // Name of the initial current node
 	Nodes          []TreeNode `yaml:"nodes,omitempty"`          // List of tree nodes
 	// Grid-specific properties
-	GridRows    []int        `yaml:"gridRows,omitempty"`    // Row heights (0 = flexible)
-	GridColumns []int        `yaml:"gridColumns,omitempty"` // Column widths (0 = flexible)
+	GridRows    []int        `yaml:"gridRows,omitempty"`    // Row heights (positive = fixed, 0 = flexible, negative = proportional)
+	GridColumns []int        `yaml:"gridColumns,omitempty"` // Column widths (positive = fixed, 0 = flexible, negative = proportional)
 	GridBorders bool         `yaml:"gridBorders,omitempty"` // Show borders between grid cells
 	GridItems   []GridItem   `yaml:"gridItems,omitempty"`   // Items to place in grid
 	// Pages-specific properties (for nested pages containers)
 	Pages []PageRef `yaml:"pages,omitempty"` // List of pages for nested pages container
-	// Modal-specific properties
-	Buttons    []ModalButton          `yaml:"buttons,omitempty"` // Buttons with callbacks for modal dialogs
+	// Modal-specific properties (backgroundColor and textColor also apply to modals)
+	Buttons               []ModalButton `yaml:"buttons,omitempty"`               // Buttons with callbacks for modal dialogs
+	ButtonBackgroundColor string        `yaml:"buttonBackgroundColor,omitempty"` // Modal button background color
+	ButtonTextColor       string        `yaml:"buttonTextColor,omitempty"`       // Modal button label color
+	// Dialog-specific properties (type: dialog; also uses text, buttons, backgroundColor, textColor)
+	Width int `yaml:"width,omitempty"` // Outer width in cells including the border (default 50; shrinks to fit the screen)
+	// ProgressBar, sparkline and spinner properties
+	ValueFromState string `yaml:"valueFromState,omitempty"` // State key: progress value (0-100), sparkline series ("3,5,2") or spinner busy flag
+	FillColor      string `yaml:"fillColor,omitempty"`      // Progress bar filled part or sparkline bars (default: contrast background / primary text)
+	EmptyColor     string `yaml:"emptyColor,omitempty"`     // Color of the unfilled part (default: primitive background)
+	MaxHeight      int    `yaml:"maxHeight,omitempty"`      // Sparkline: rows used by the largest value (default: inner height)
+	Frames         []string `yaml:"frames,omitempty"`     // Spinner: frames to cycle through (default: braille dots)
+	Interval       string   `yaml:"interval,omitempty"`   // Spinner: time per frame as a Go duration (default "100ms")
 	Properties map[string]interface{} `yaml:",inline"`           // Catch-all for other properties
 }
 
@@ -127,6 +246,39 @@ type TreeNode struct {
 	Color      string   `yaml:"color,omitempty"`      // Text color
 	Selectable string   `yaml:"selectable,omitempty"` // "true" (always run onNodeSelected), "auto" (default behavior), "false" (not selectable). Defaults to "auto" if unset.
 	Children   []string `yaml:"children,omitempty"`   // Names of child nodes
+	Data       string   `yaml:"data,omitempty"`       // Payload exposed to onNodeSelected as __selectedNodeData
+}
+
+// Regions is a textView's regions setting. In YAML it is either a bool (`regions: true` enables
+// ["id"] region tags written in text) or a list of regions that the builder turns into tagged
+// text, which also enables regions and dynamic colors.
+type Regions struct {
+	Enabled bool     // Region tags are parsed (set by `regions: true` or a non-empty list)
+	Items   []Region // Structured regions, in display order
+}
+
+// Region is one entry of a structured regions list
+type Region struct {
+	ID    string `yaml:"id"`              // Region ID (used by highlights and __highlightedRegion)
+	Text  string `yaml:"text"`            // Displayed text (may bind state)
+	Color string `yaml:"color,omitempty"` // Text color
+}
+
+// UnmarshalYAML accepts either a bool or a list of regions.
+func (r *Regions) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind == yaml.SequenceNode {
+		if err := node.Decode(&r.Items); err != nil {
+			return err
+		}
+		r.Enabled = len(r.Items) > 0
+		return nil
+	}
+	return node.Decode(&r.Enabled)
+}
+
+// IsZero reports whether regions are neither enabled nor listed (so omitempty omits them).
+func (r Regions) IsZero() bool {
+	return !r.Enabled && len(r.Items) == 0
 }
 
 // ModalButton represents a button in a modal dialog
@@ -145,34 +297,49 @@ type GridItem struct {
 	MinHeight int        `yaml:"minHeight,omitempty"` // Minimum height
 	MinWidth  int        `yaml:"minWidth,omitempty"`  // Minimum width
 	Focus     bool       `yaml:"focus,omitempty"`     // Whether this item should receive focus
+	// hideBelowWidth/hideBelowHeight: Remove the item from the grid while the grid is narrower/shorter than this (e.g. a sidebar on narrow terminals)
+	HideBelowWidth  int `yaml:"hideBelowWidth,omitempty"`
+	HideBelowHeight int `yaml:"hideBelowHeight,omitempty"`
 }
 
 // ListItem represents an item in a list
 type ListItem struct {
-	MainText      string `yaml:"mainText"`
-	SecondaryText string `yaml:"secondaryText,omitempty"`
-	Shortcut      string `yaml:"shortcut,omitempty"`
-	OnSelected    string `yaml:"onSelected,omitempty"` // Template expression
+	MainText           string `yaml:"mainText"`
+	SecondaryText      string `yaml:"secondaryText,omitempty"`
+	Shortcut           string `yaml:"shortcut,omitempty"`           // Single key ("a", "ñ") or special/modified key ("F1", "Ctrl+N") active while the list has focus
+	OnSelected         string `yaml:"onSelected,omitempty"`         // Template expression
+	MainTextColor      string `yaml:"mainTextColor,omitempty"`      // Color of this item's main text (default: the list's)
+	SecondaryTextColor string `yaml:"secondaryTextColor,omitempty"` // Color of this item's secondary text (default: the list's)
 }
 
 // FormItem represents an item in a form
 type FormItem struct {
-	Type           string   `yaml:"type"` // "inputfield", "button", "checkbox", "dropdown", "textarea"
+	Type           string   `yaml:"type"` // "inputfield", "button", "spacer", "checkbox", "dropdown", "textarea"
 	Label          string   `yaml:"label"`
 	Value          string   `yaml:"value,omitempty"`
 	Options        []string `yaml:"options,omitempty"`
 	Checked        bool     `yaml:"checked,omitempty"`
 	OnSelected     string   `yaml:"onSelected,omitempty"` // Template expression
 	OnChanged      string   `yaml:"onChanged,omitempty"`  // Template expression
-	FieldWidth     int      `yaml:"fieldWidth,omitempty"`
+	FieldWidth     int      `yaml:"fieldWidth,omitempty"` // Input field width in cells; unset (0) spans the form row
 	PasswordMode   bool     `yaml:"passwordMode,omitempty"`
 	AcceptanceFunc string   `yaml:"acceptanceFunc,omitempty"` // "integer", "float", etc.
 	MaxLength      int      `yaml:"maxLength,omitempty"`
 	Placeholder    string   `yaml:"placeholder,omitempty"`
+	SubmitOnEnter  bool     `yaml:"submitOnEnter,omitempty"` // Inputfield: Enter runs the form's onSubmit (the form must have a name)
+	Width          int      `yaml:"width,omitempty"`         // Spacer: width of the blank gap it adds to the button row (default and minimum 4)
+	Disabled       *bool    `yaml:"disabled,omitempty"`     // Initial disabled state (buttons ignore selection; fields are read-only)
+	DisabledWhen   string   `yaml:"disabledWhen,omitempty"` // State key; disabled while its value is truthy (overrides disabled once the key is set)
+	Required       bool     `yaml:"required,omitempty"`     // Only adds the red "*" to the label when the form sets markRequired; submitting is not blocked
+	ReadOnly       bool     `yaml:"readOnly,omitempty"`     // Inputfield: shows value, stays focusable, but cannot be edited (unlike disabled, it is not dimmed or skipped)
 }
 
 // TableData represents data for a table
 type TableData struct {
-	Headers []string   `yaml:"headers"`
-	Rows    [][]string `yaml:"rows"`
+	Headers               []string   `yaml:"headers"`
+	Rows                  [][]string `yaml:"rows"`
+	HeaderColor           string     `yaml:"headerColor,omitempty"`           // Header text color (default yellow)
+	HeaderAlign           string     `yaml:"headerAlign,omitempty"`           // Header alignment: "left", "center" (default), "right"
+	HeaderBackgroundColor string     `yaml:"headerBackgroundColor,omitempty"` // Header background color (default: the table's)
+	ShowHeaders           *bool      `yaml:"showHeaders,omitempty"`           // Write headers as row 0 (default true); false starts rows at row 0
 }
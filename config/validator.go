@@ -2,18 +2,30 @@ package config
 
 import (
 	"fmt"
+	"strings"
+	"time"
+	"unicode"
 
 	"github.com/cassdeckard/tviewyaml/keys"
+	"github.com/gdamore/tcell/v2"
 )
 
 // Validator validates configuration structures
-type Validator struct{}
+type Validator struct {
+	globalKeyBindings []KeyBinding // checked against list shortcuts (SetGlobalKeyBindings)
+}
 
 // NewValidator creates a new validator
 func NewValidator() *Validator {
 	return &Validator{}
 }
 
+// SetGlobalKeyBindings sets the app's global key bindings. ValidatePage and ValidatePrimitive then
+// reject list item shortcuts that a binding would consume before the list sees the key.
+func (v *Validator) SetGlobalKeyBindings(bindings []KeyBinding) {
+	v.globalKeyBindings = bindings
+}
+
 // ValidateApp validates an application configuration
 func (v *Validator) ValidateApp(config *AppConfig) error {
 	// Root element validation (currently only supports "pages" type)
@@ -30,8 +42,38 @@ func (v *Validator) ValidateApp(config *AppConfig) error {
 		if page.Name == "" {
 			return fmt.Errorf("page %d is missing name", i)
 		}
-		if page.Ref == "" {
-			return fmt.Errorf("page %s is missing ref", page.Name)
+		if page.Ref == "" && page.Page == nil {
+			return fmt.Errorf("page %s is missing ref or page", page.Name)
+		}
+		if page.Ref != "" && page.Page != nil {
+			return fmt.Errorf("page %s sets both ref and page; use one", page.Name)
+		}
+	}
+
+	if theme := config.Application.Theme; theme != nil {
+		switch theme.BorderStyle {
+		case "", "single", "rounded", "thick":
+		default:
+			return fmt.Errorf("theme borderStyle must be single, rounded, or thick, got %q", theme.BorderStyle)
+		}
+	}
+
+	if app := config.Application; app.TickInterval != "" {
+		if d, err := time.ParseDuration(app.TickInterval); err != nil || d <= 0 {
+			return fmt.Errorf("tickInterval %q must be a positive duration (e.g. 5s)", app.TickInterval)
+		}
+	} else if app.OnTick != "" {
+		return fmt.Errorf("onTick requires tickInterval")
+	}
+
+	for group, ids := range config.Application.FocusGroups {
+		if len(ids) == 0 {
+			return fmt.Errorf("focus group %q has no primitives", group)
+		}
+		for i, id := range ids {
+			if id == "" {
+				return fmt.Errorf("focus group %q entry %d is empty", group, i)
+			}
 		}
 	}
 
@@ -63,6 +105,9 @@ func (v *Validator) ValidatePage(config *PageConfig) error {
 		if len(config.ListItems) == 0 && len(config.Items) == 0 {
 			return fmt.Errorf("list type requires listItems or items")
 		}
+		if err := v.validateListShortcuts(config.ListItems); err != nil {
+			return err
+		}
 	case "flex":
 		if len(config.Items) == 0 {
 			return fmt.Errorf("flex type requires items")
@@ -77,16 +122,39 @@ func (v *Validator) ValidatePage(config *PageConfig) error {
 		}
 	case "treeView":
 		// Nodes may be empty (empty tree is valid)
+		if err := validateTreeNodeRefs(config.Nodes, config.RootNode, config.CurrentNode); err != nil {
+			return err
+		}
+	}
+
+	if err := validateSingleFocus(flexFocusItems(config.Items)); err != nil {
+		return err
+	}
+	if err := validateMinSizes(config.Items); err != nil {
+		return err
+	}
+	if config.ResponsiveDirection && config.BreakpointWidth <= 0 {
+		return fmt.Errorf("responsiveDirection requires a positive breakpointWidth")
+	}
+
+	// Validate nested primitives
+	for i, item := range config.Items {
+		if item.Primitive == nil || item.Spacer {
+			continue
+		}
+		if err := v.ValidatePrimitive(item.Primitive); err != nil {
+			return fmt.Errorf("items[%d]: %w", i, err)
+		}
 	}
 
 	return nil
 }
 
-// ValidateAppRefs checks that each page ref exists under the loader's base path.
-// Call after ValidateApp when a loader is available.
+// ValidateAppRefs checks that each page ref exists under the loader's base path (inline pages
+// are skipped). Call after ValidateApp when a loader is available.
 func (v *Validator) ValidateAppRefs(config *AppConfig, loader *Loader) error {
 	for _, page := range config.Application.Root.Pages {
-		if !loader.RefExists(page.Ref) {
+		if page.Page == nil && !loader.RefExists(page.Ref) {
 			return fmt.Errorf("page %q ref %q: file does not exist", page.Name, page.Ref)
 		}
 	}
@@ -99,6 +167,241 @@ func (v *Validator) ValidatePrimitive(prim *Primitive) error {
 		return fmt.Errorf("primitive type is required")
 	}
 
-	// Add more validation as needed
+	if prim.Type == "grid" {
+		if err := v.validateGridItems(prim); err != nil {
+			return err
+		}
+	}
+
+	if prim.Type == "button" && prim.Shortcut != "" {
+		if _, _, _, err := keys.ParseKey(prim.Shortcut); err != nil {
+			return fmt.Errorf("button has invalid shortcut %q: %w", prim.Shortcut, err)
+		}
+		if prim.OnSelected == "" {
+			return fmt.Errorf("button shortcut %q requires onSelected", prim.Shortcut)
+		}
+	}
+
+	if (prim.Type == "progressBar" || prim.Type == "sparkline" || prim.Type == "spinner") && prim.ValueFromState == "" {
+		return fmt.Errorf("%s requires valueFromState", prim.Type)
+	}
+	if prim.Interval != "" {
+		if d, err := time.ParseDuration(prim.Interval); err != nil || d <= 0 {
+			return fmt.Errorf("interval %q must be a positive duration (e.g. 100ms)", prim.Interval)
+		}
+	}
+
+	if prim.TitleRight != "" && !prim.Border {
+		return fmt.Errorf("titleRight requires border: true")
+	}
+
+	if prim.MaxVisibleOptions < 0 {
+		return fmt.Errorf("maxVisibleOptions must not be negative, got %d", prim.MaxVisibleOptions)
+	}
+
+	if prim.Type == "list" {
+		if err := v.validateListShortcuts(prim.ListItems); err != nil {
+			return err
+		}
+	}
+
+	if prim.Type == "treeView" {
+		if err := validateTreeNodeRefs(prim.Nodes, prim.RootNode, prim.CurrentNode); err != nil {
+			return err
+		}
+	}
+
+	if prim.FilterInput != "" && !prim.Filterable {
+		return fmt.Errorf("filterInput %q requires filterable", prim.FilterInput)
+	}
+
+	if (prim.ScrollTo != 0 || prim.ScrollToEnd) && prim.Scrollable != nil && !*prim.Scrollable {
+		return fmt.Errorf("scrollTo and scrollToEnd require scrollable")
+	}
+	if prim.ScrollTo < 0 {
+		return fmt.Errorf("scrollTo must not be negative, got %d", prim.ScrollTo)
+	}
+
+	seenRegions := make(map[string]bool)
+	for i, region := range prim.Regions.Items {
+		if region.ID == "" {
+			return fmt.Errorf("regions[%d] is missing id", i)
+		}
+		if seenRegions[region.ID] {
+			return fmt.Errorf("regions[%d]: duplicate region id %q", i, region.ID)
+		}
+		seenRegions[region.ID] = true
+	}
+
+	if err := validateSingleFocus(flexFocusItems(prim.Items)); err != nil {
+		return err
+	}
+	if err := validateMinSizes(prim.Items); err != nil {
+		return err
+	}
+	if prim.ResponsiveDirection && prim.BreakpointWidth <= 0 {
+		return fmt.Errorf("responsiveDirection requires a positive breakpointWidth")
+	}
+	var gridFocus []string
+	for _, item := range prim.GridItems {
+		if item.Focus {
+			gridFocus = append(gridFocus, fmt.Sprintf("grid item at row %d, column %d", item.Row, item.Column))
+		}
+	}
+	if err := validateSingleFocus(gridFocus); err != nil {
+		return err
+	}
+
+	// Validate nested primitives
+	for i, item := range prim.Items {
+		if item.Primitive == nil || item.Spacer {
+			continue
+		}
+		if err := v.ValidatePrimitive(item.Primitive); err != nil {
+			return fmt.Errorf("items[%d]: %w", i, err)
+		}
+	}
+	for _, item := range prim.GridItems {
+		if item.Primitive == nil {
+			continue
+		}
+		if err := v.ValidatePrimitive(item.Primitive); err != nil {
+			return fmt.Errorf("grid item at row %d, column %d: %w", item.Row, item.Column, err)
+		}
+	}
+	return nil
+}
+
+// shortcutKey identifies the key a shortcut or key binding matches.
+type shortcutKey struct {
+	key tcell.Key
+	mod tcell.ModMask
+	ch  rune
+}
+
+// parseListShortcut parses a list item shortcut the way the builder binds it: a key string, or
+// else (legacy) the first character as a rune shortcut. Ctrl+letter matches either case.
+func parseListShortcut(shortcut string) shortcutKey {
+	key, mod, ch, err := keys.ParseKey(shortcut)
+	if err != nil {
+		return shortcutKey{key: tcell.KeyRune, ch: []rune(shortcut)[0]}
+	}
+	if key == tcell.KeyRune && mod&tcell.ModCtrl != 0 {
+		ch = unicode.ToLower(ch)
+	}
+	return shortcutKey{key: key, mod: mod, ch: ch}
+}
+
+// validateListShortcuts checks that no two list items share a shortcut, and that no global key
+// binding (without passthrough) takes an item's shortcut first. Rune shortcuts are case-sensitive,
+// as in tview; bindings match either case unless they set caseSensitive.
+func (v *Validator) validateListShortcuts(items []ListItem) error {
+	seen := make(map[shortcutKey]int)
+	for i, item := range items {
+		if item.Shortcut == "" {
+			continue
+		}
+		sk := parseListShortcut(item.Shortcut)
+		if j, ok := seen[sk]; ok {
+			return fmt.Errorf("listItems[%d] %q and listItems[%d] %q have the same shortcut %q",
+				j, items[j].MainText, i, item.MainText, item.Shortcut)
+		}
+		seen[sk] = i
+		for _, binding := range v.globalKeyBindings {
+			if !binding.Passthrough && bindingMatches(binding, sk) {
+				return fmt.Errorf("listItems[%d] %q: shortcut %q is taken by global key binding %q",
+					i, item.MainText, item.Shortcut, binding.Key)
+			}
+		}
+	}
+	return nil
+}
+
+// bindingMatches reports whether a global key binding catches the key of a list shortcut.
+func bindingMatches(binding KeyBinding, sk shortcutKey) bool {
+	key, mod, ch, err := keys.ParseKey(binding.Key)
+	if err != nil || key != sk.key || mod != sk.mod {
+		return false
+	}
+	if key != tcell.KeyRune || binding.CaseSensitive && mod&tcell.ModCtrl == 0 {
+		return ch == sk.ch
+	}
+	return unicode.ToLower(ch) == unicode.ToLower(sk.ch)
+}
+
+// validateTreeNodeRefs checks that rootNode and currentNode, when set, name declared nodes.
+func validateTreeNodeRefs(nodes []TreeNode, rootNode, currentNode string) error {
+	declared := make(map[string]bool, len(nodes))
+	for _, node := range nodes {
+		declared[node.Name] = true
+	}
+	if rootNode != "" && !declared[rootNode] {
+		return fmt.Errorf("rootNode %q is not a declared node", rootNode)
+	}
+	if currentNode != "" && !declared[currentNode] {
+		return fmt.Errorf("currentNode %q is not a declared node", currentNode)
+	}
+	return nil
+}
+
+// validateMinSizes checks the minSize of each flex item: it must not be negative, and spacers
+// cannot set it (the builder resizes items by primitive, and spacers have none).
+func validateMinSizes(items []FlexItem) error {
+	for i, item := range items {
+		if item.MinSize < 0 {
+			return fmt.Errorf("items[%d]: minSize must not be negative, got %d", i, item.MinSize)
+		}
+		if item.MinSize > 0 && (item.Primitive == nil || item.Spacer) {
+			return fmt.Errorf("items[%d]: minSize is not supported on spacers", i)
+		}
+	}
+	return nil
+}
+
+// flexFocusItems returns "items[i]" for each flex item that sets focus: true.
+func flexFocusItems(items []FlexItem) []string {
+	var focused []string
+	for i, item := range items {
+		if item.Focus {
+			focused = append(focused, fmt.Sprintf("items[%d]", i))
+		}
+	}
+	return focused
+}
+
+// validateSingleFocus returns an error if more than one item in a container requests focus.
+// tview silently gives initial focus to the first such item, so extra flags are almost always a mistake.
+func validateSingleFocus(focused []string) error {
+	if len(focused) <= 1 {
+		return nil
+	}
+	return fmt.Errorf("multiple items set focus: true (%s); only one item per container may request focus", strings.Join(focused, "; "))
+}
+
+// validateGridItems checks that grid items fit within the declared gridRows/gridColumns.
+// tview grows the grid with implicit rows and columns to fit such items, which is rarely what the
+// declared sizes intend, so they are rejected. Row and column sizes themselves are
+// passed through unchanged: positive = fixed cells, 0 = flexible, negative = proportional.
+// When gridRows or gridColumns is empty, that dimension is not checked.
+func (v *Validator) validateGridItems(prim *Primitive) error {
+	rows, cols := len(prim.GridRows), len(prim.GridColumns)
+	for _, item := range prim.GridItems {
+		if item.Row < 0 || item.Column < 0 {
+			return fmt.Errorf("grid item at row %d, column %d: row and column must not be negative", item.Row, item.Column)
+		}
+		if item.RowSpan < 0 || item.ColSpan < 0 {
+			return fmt.Errorf("grid item at row %d, column %d: rowSpan and colSpan must not be negative", item.Row, item.Column)
+		}
+		if item.HideBelowWidth < 0 || item.HideBelowHeight < 0 {
+			return fmt.Errorf("grid item at row %d, column %d: hideBelowWidth and hideBelowHeight must not be negative", item.Row, item.Column)
+		}
+		rowSpan, colSpan := max(item.RowSpan, 1), max(item.ColSpan, 1)
+		if rows > 0 && item.Row+rowSpan > rows {
+			return fmt.Errorf("grid item at row %d, column %d: rows %d-%d exceed the %d declared gridRows", item.Row, item.Column, item.Row, item.Row+rowSpan-1, rows)
+		}
+		if cols > 0 && item.Column+colSpan > cols {
+			return fmt.Errorf("grid item at row %d, column %d: columns %d-%d exceed the %d declared gridColumns", item.Row, item.Column, item.Column, item.Column+colSpan-1, cols)
+		}
+	}
 	return nil
 }
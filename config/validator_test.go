@@ -142,6 +142,120 @@ func TestValidateApp(t *testing.T) {
 			wantErr: true,
 			errContains: "page form is missing ref",
 		},
+		{
+			name: "page with both ref and inline page",
+			config: &AppConfig{
+				Application: ApplicationElement{
+					Root: RootElement{
+						Type: "pages",
+						Pages: []PageRef{
+							{Name: "main", Ref: "main.yaml", Page: &PageConfig{Type: "flex"}},
+						},
+					},
+				},
+			},
+			wantErr: true,
+			errContains: "page main sets both ref and page",
+		},
+		{
+			name: "inline page without ref",
+			config: &AppConfig{
+				Application: ApplicationElement{
+					Root: RootElement{
+						Type: "pages",
+						Pages: []PageRef{
+							{Name: "main", Page: &PageConfig{Type: "flex"}},
+						},
+					},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "theme with unknown border style",
+			config: &AppConfig{
+				Application: ApplicationElement{
+					Theme: &Theme{BorderStyle: "dotted"},
+					Root: RootElement{
+						Type:  "pages",
+						Pages: []PageRef{{Name: "main", Ref: "main.yaml"}},
+					},
+				},
+			},
+			wantErr:     true,
+			errContains: "theme borderStyle must be single, rounded, or thick",
+		},
+		{
+			name: "empty focus group",
+			config: &AppConfig{
+				Application: ApplicationElement{
+					FocusGroups: map[string][]string{"panels": {}},
+					Root: RootElement{
+						Type:  "pages",
+						Pages: []PageRef{{Name: "main", Ref: "main.yaml"}},
+					},
+				},
+			},
+			wantErr:     true,
+			errContains: `focus group "panels" has no primitives`,
+		},
+		{
+			name: "onTick with tickInterval",
+			config: &AppConfig{
+				Application: ApplicationElement{
+					OnTick:       `{{ noop }}`,
+					TickInterval: "5s",
+					Root: RootElement{
+						Type:  "pages",
+						Pages: []PageRef{{Name: "main", Ref: "main.yaml"}},
+					},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "onTick without tickInterval",
+			config: &AppConfig{
+				Application: ApplicationElement{
+					OnTick: `{{ noop }}`,
+					Root: RootElement{
+						Type:  "pages",
+						Pages: []PageRef{{Name: "main", Ref: "main.yaml"}},
+					},
+				},
+			},
+			wantErr:     true,
+			errContains: "onTick requires tickInterval",
+		},
+		{
+			name: "non-positive tickInterval",
+			config: &AppConfig{
+				Application: ApplicationElement{
+					OnTick:       `{{ noop }}`,
+					TickInterval: "0s",
+					Root: RootElement{
+						Type:  "pages",
+						Pages: []PageRef{{Name: "main", Ref: "main.yaml"}},
+					},
+				},
+			},
+			wantErr:     true,
+			errContains: `tickInterval "0s" must be a positive duration`,
+		},
+		{
+			name: "invalid tickInterval",
+			config: &AppConfig{
+				Application: ApplicationElement{
+					TickInterval: "often",
+					Root: RootElement{
+						Type:  "pages",
+						Pages: []PageRef{{Name: "main", Ref: "main.yaml"}},
+					},
+				},
+			},
+			wantErr:     true,
+			errContains: `tickInterval "often" must be a positive duration`,
+		},
 
 		// Key binding validation
 		{
@@ -256,6 +370,56 @@ func TestValidatePage(t *testing.T) {
 			},
 			wantErr: false,
 		},
+		{
+			name: "flex with out-of-bounds grid item",
+			config: &PageConfig{
+				Type: "flex",
+				Items: []FlexItem{
+					{Spacer: true},
+					{Primitive: &Primitive{
+						Type:      "grid",
+						GridRows:  []int{0, 0},
+						GridItems: []GridItem{{Primitive: &Primitive{Type: "textView"}, Row: 2, Column: 0}},
+					}},
+				},
+			},
+			wantErr:     true,
+			errContains: "items[1]: grid item at row 2, column 0",
+		},
+		{
+			name: "flex page with multiple focused items",
+			config: &PageConfig{
+				Type: "flex",
+				Items: []FlexItem{
+					{Primitive: &Primitive{Type: "textView"}, Focus: true},
+					{Primitive: &Primitive{Type: "textView"}, Focus: true},
+				},
+			},
+			wantErr:     true,
+			errContains: "multiple items set focus: true (items[0]; items[1])",
+		},
+		{
+			name: "flex page with minSize on a spacer",
+			config: &PageConfig{
+				Type: "flex",
+				Items: []FlexItem{
+					{Primitive: &Primitive{Type: "textView"}, Proportion: 1, MinSize: 20},
+					{Spacer: true, Proportion: 1, MinSize: 5},
+				},
+			},
+			wantErr:     true,
+			errContains: "items[1]: minSize is not supported on spacers",
+		},
+		{
+			name: "flex page with responsiveDirection but no breakpoint",
+			config: &PageConfig{
+				Type:                "flex",
+				ResponsiveDirection: true,
+				Items:               []FlexItem{{Primitive: &Primitive{Type: "textView"}, Proportion: 1}},
+			},
+			wantErr:     true,
+			errContains: "responsiveDirection requires a positive breakpointWidth",
+		},
 		{
 			name: "valid list with items",
 			config: &PageConfig{
@@ -315,6 +479,27 @@ func TestValidatePage(t *testing.T) {
 			},
 			wantErr: false,
 		},
+		{
+			name: "treeView with missing rootNode",
+			config: &PageConfig{
+				Type:     "treeView",
+				RootNode: "rot",
+				Nodes:    []TreeNode{{Name: "root", Text: "Root"}},
+			},
+			wantErr:     true,
+			errContains: `rootNode "rot" is not a declared node`,
+		},
+		{
+			name: "treeView with missing currentNode",
+			config: &PageConfig{
+				Type:        "treeView",
+				RootNode:    "root",
+				CurrentNode: "leaf",
+				Nodes:       []TreeNode{{Name: "root", Text: "Root"}},
+			},
+			wantErr:     true,
+			errContains: `currentNode "leaf" is not a declared node`,
+		},
 
 		// Missing type
 		{
@@ -337,6 +522,42 @@ func TestValidatePage(t *testing.T) {
 			wantErr: true,
 			errContains: "list type requires listItems or items",
 		},
+		{
+			name: "list with duplicate shortcuts",
+			config: &PageConfig{
+				Type: "list",
+				ListItems: []ListItem{
+					{MainText: "Save", Shortcut: "s"},
+					{MainText: "Open", Shortcut: "o"},
+					{MainText: "Search", Shortcut: "s"},
+				},
+			},
+			wantErr:     true,
+			errContains: `listItems[0] "Save" and listItems[2] "Search" have the same shortcut "s"`,
+		},
+		{
+			name: "list with duplicate key shortcuts",
+			config: &PageConfig{
+				Type: "list",
+				ListItems: []ListItem{
+					{MainText: "New", Shortcut: "Ctrl+N"},
+					{MainText: "Next", Shortcut: "ctrl+n"},
+				},
+			},
+			wantErr:     true,
+			errContains: `have the same shortcut "ctrl+n"`,
+		},
+		{
+			name: "list shortcuts differing in case",
+			config: &PageConfig{
+				Type: "list",
+				ListItems: []ListItem{
+					{MainText: "save", Shortcut: "s"},
+					{MainText: "Save all", Shortcut: "S"},
+				},
+			},
+			wantErr: false,
+		},
 
 		// Flex validation
 		{
@@ -391,6 +612,42 @@ func TestValidatePage(t *testing.T) {
 	}
 }
 
+func TestValidatePage_ListShortcutsAndGlobalKeyBindings(t *testing.T) {
+	validator := NewValidator()
+	validator.SetGlobalKeyBindings([]KeyBinding{
+		{Key: "Q", Action: "stopApp"},
+		{Key: "F1", Action: "showKeyHelp"},
+		{Key: "g", Action: "goBack", CaseSensitive: true},
+		{Key: "x", Action: "noop", Passthrough: true},
+	})
+	tests := []struct {
+		name     string
+		shortcut string
+		wantErr  string
+	}{
+		{"rune taken by a case-insensitive binding", "q", `listItems[0] "Item": shortcut "q" is taken by global key binding "Q"`},
+		{"special key", "F1", `shortcut "F1" is taken by global key binding "F1"`},
+		{"other case of a case-sensitive binding", "G", ""},
+		{"passthrough binding", "x", ""},
+		{"free key", "a", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			page := &PageConfig{Type: "list", ListItems: []ListItem{{MainText: "Item", Shortcut: tt.shortcut}}}
+			err := validator.ValidatePage(page)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("ValidatePage() error = %v, want nil", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("ValidatePage() error = %v, want error containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
 func TestValidateAppRefs(t *testing.T) {
 	// Create a temporary directory for test files
 	tmpDir := t.TempDir()
@@ -493,6 +750,27 @@ func TestValidatePrimitive(t *testing.T) {
 			},
 			wantErr: false,
 		},
+		{
+			name: "list with duplicate shortcuts",
+			prim: &Primitive{Type: "list", ListItems: []ListItem{
+				{MainText: "Start", Shortcut: "s"},
+				{MainText: "Stop", Shortcut: "s"},
+			}},
+			wantErr:     true,
+			errContains: `listItems[0] "Start" and listItems[1] "Stop" have the same shortcut "s"`,
+		},
+		{
+			name:        "treeView with missing rootNode",
+			prim:        &Primitive{Type: "treeView", RootNode: "rot", Nodes: []TreeNode{{Name: "root"}}},
+			wantErr:     true,
+			errContains: `rootNode "rot" is not a declared node`,
+		},
+		{
+			name:        "treeView with missing currentNode",
+			prim:        &Primitive{Type: "treeView", CurrentNode: "leaf", Nodes: []TreeNode{{Name: "root"}}},
+			wantErr:     true,
+			errContains: `currentNode "leaf" is not a declared node`,
+		},
 		{
 			name: "missing type",
 			prim: &Primitive{
@@ -501,6 +779,216 @@ func TestValidatePrimitive(t *testing.T) {
 			wantErr: true,
 			errContains: "primitive type is required",
 		},
+		{
+			name: "button shortcut with onSelected",
+			prim: &Primitive{
+				Type:       "button",
+				Shortcut:   "Ctrl+S",
+				OnSelected: `{{ showNotification "saved" }}`,
+			},
+			wantErr: false,
+		},
+		{
+			name: "button shortcut without onSelected",
+			prim: &Primitive{
+				Type:     "button",
+				Shortcut: "Ctrl+S",
+			},
+			wantErr:     true,
+			errContains: `button shortcut "Ctrl+S" requires onSelected`,
+		},
+		{
+			name: "button shortcut that is not a key",
+			prim: &Primitive{
+				Type:       "button",
+				Shortcut:   "Ctrl+Nope",
+				OnSelected: `{{ showNotification "saved" }}`,
+			},
+			wantErr:     true,
+			errContains: "button has invalid shortcut",
+		},
+		{
+			name: "titleRight without border",
+			prim: &Primitive{
+				Type:       "textView",
+				Title:      "Logs",
+				TitleRight: "tail -f",
+			},
+			wantErr:     true,
+			errContains: "titleRight requires border: true",
+		},
+		{
+			name: "progressBar without valueFromState",
+			prim: &Primitive{
+				Type: "progressBar",
+			},
+			wantErr:     true,
+			errContains: "progressBar requires valueFromState",
+		},
+		{
+			name: "sparkline without valueFromState",
+			prim: &Primitive{
+				Type: "sparkline",
+			},
+			wantErr:     true,
+			errContains: "sparkline requires valueFromState",
+		},
+		{
+			name: "spinner with invalid interval",
+			prim: &Primitive{
+				Type:           "spinner",
+				ValueFromState: "loading",
+				Interval:       "fast",
+			},
+			wantErr:     true,
+			errContains: `interval "fast" must be a positive duration`,
+		},
+		{
+			name: "spinner",
+			prim: &Primitive{
+				Type:           "spinner",
+				ValueFromState: "loading",
+				Frames:         []string{"-", "\\", "|", "/"},
+				Interval:       "80ms",
+			},
+			wantErr: false,
+		},
+		{
+			name: "filterInput without filterable",
+			prim: &Primitive{
+				Type:        "list",
+				FilterInput: "search",
+			},
+			wantErr:     true,
+			errContains: `filterInput "search" requires filterable`,
+		},
+		{
+			name: "region without id",
+			prim: &Primitive{
+				Type:    "textView",
+				Regions: Regions{Enabled: true, Items: []Region{{Text: "Home"}}},
+			},
+			wantErr:     true,
+			errContains: "regions[0] is missing id",
+		},
+		{
+			name: "duplicate region id",
+			prim: &Primitive{
+				Type:    "textView",
+				Regions: Regions{Enabled: true, Items: []Region{{ID: "a", Text: "A"}, {ID: "a", Text: "B"}}},
+			},
+			wantErr:     true,
+			errContains: `duplicate region id "a"`,
+		},
+		{
+			name: "scrollToEnd on a non-scrollable textView",
+			prim: &Primitive{
+				Type:        "textView",
+				Scrollable:  new(bool),
+				ScrollToEnd: true,
+			},
+			wantErr:     true,
+			errContains: "scrollTo and scrollToEnd require scrollable",
+		},
+		{
+			name: "grid items within declared size",
+			prim: &Primitive{
+				Type:        "grid",
+				GridRows:    []int{3, 0},
+				GridColumns: []int{-1, -2, 10},
+				GridItems: []GridItem{
+					{Primitive: &Primitive{Type: "textView"}, Row: 0, Column: 0, ColSpan: 3},
+					{Primitive: &Primitive{Type: "textView"}, Row: 1, Column: 2},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "grid without declared rows is not checked",
+			prim: &Primitive{
+				Type:      "grid",
+				GridItems: []GridItem{{Primitive: &Primitive{Type: "textView"}, Row: 5, Column: 5}},
+			},
+			wantErr: false,
+		},
+		{
+			name: "grid item row beyond declared rows",
+			prim: &Primitive{
+				Type:      "grid",
+				GridRows:  []int{1, 0},
+				GridItems: []GridItem{{Primitive: &Primitive{Type: "textView"}, Row: 3, Column: 0}},
+			},
+			wantErr:     true,
+			errContains: "grid item at row 3, column 0: rows 3-3 exceed the 2 declared gridRows",
+		},
+		{
+			name: "grid item column span beyond declared columns",
+			prim: &Primitive{
+				Type:        "grid",
+				GridColumns: []int{0, 0},
+				GridItems:   []GridItem{{Primitive: &Primitive{Type: "textView"}, Row: 0, Column: 1, ColSpan: 2}},
+			},
+			wantErr:     true,
+			errContains: "grid item at row 0, column 1: columns 1-2 exceed the 2 declared gridColumns",
+		},
+		{
+			name: "grid item negative row",
+			prim: &Primitive{
+				Type:      "grid",
+				GridItems: []GridItem{{Primitive: &Primitive{Type: "textView"}, Row: -1, Column: 0}},
+			},
+			wantErr:     true,
+			errContains: "must not be negative",
+		},
+		{
+			name: "flex with multiple focused items",
+			prim: &Primitive{
+				Type: "flex",
+				Items: []FlexItem{
+					{Primitive: &Primitive{Type: "textView"}, Focus: true},
+					{Primitive: &Primitive{Type: "textView"}},
+					{Primitive: &Primitive{Type: "inputField"}, Focus: true},
+				},
+			},
+			wantErr:     true,
+			errContains: "multiple items set focus: true (items[0]; items[2])",
+		},
+		{
+			name: "grid with multiple focused items",
+			prim: &Primitive{
+				Type: "grid",
+				GridItems: []GridItem{
+					{Primitive: &Primitive{Type: "textView"}, Row: 0, Column: 0, Focus: true},
+					{Primitive: &Primitive{Type: "textView"}, Row: 1, Column: 1, Focus: true},
+				},
+			},
+			wantErr:     true,
+			errContains: "(grid item at row 0, column 0; grid item at row 1, column 1)",
+		},
+		{
+			name: "focus in separate containers",
+			prim: &Primitive{
+				Type: "flex",
+				Items: []FlexItem{
+					{Primitive: &Primitive{Type: "flex", Items: []FlexItem{{Primitive: &Primitive{Type: "textView"}, Focus: true}}}, Focus: true},
+					{Primitive: &Primitive{Type: "flex", Items: []FlexItem{{Primitive: &Primitive{Type: "textView"}, Focus: true}}}},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "nested grid in flex",
+			prim: &Primitive{
+				Type: "flex",
+				Items: []FlexItem{{Primitive: &Primitive{
+					Type:      "grid",
+					GridRows:  []int{0},
+					GridItems: []GridItem{{Primitive: &Primitive{Type: "textView"}, Row: 1, Column: 0}},
+				}}},
+			},
+			wantErr:     true,
+			errContains: "items[0]: grid item at row 1, column 0",
+		},
 	}
 
 	validator := NewValidator()
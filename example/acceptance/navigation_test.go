@@ -53,36 +53,36 @@ func TestAcceptance_KeyNavigation(t *testing.T) {
 			if p.navKey != "" {
 				key = p.navKey
 			}
-			h.typeKey(key)
-			if !h.waitForContent(p.contains) {
+			h.TypeKey(key)
+			if !h.WaitForContent(p.contains) {
 				t.Fatalf("timeout waiting for %q after pressing %q; content snippet: %s",
-					p.contains, key, truncate(h.getContent(), 500))
+					p.contains, key, truncate(h.Content(), 500))
 			}
 			t.Run(p.subtest, func(t *testing.T) {
-				if !h.screenContains(p.contains) {
+				if !h.ScreenContains(p.contains) {
 					t.Errorf("after pressing %q, screen should contain %q; content snippet: %s",
-						key, p.contains, truncate(h.getContent(), 500))
+						key, p.contains, truncate(h.Content(), 500))
 				}
 				h.AssertSnapshot(t, "")
 			})
-			h.typeKey("Escape")
+			h.TypeKey("Escape")
 			if p.escapeExtra != "" {
-				h.typeKey(p.escapeExtra)
-				if !h.waitForDraw() {
+				h.TypeKey(p.escapeExtra)
+				if !h.WaitForDraw() {
 					t.Fatalf("timeout waiting for draw after %q from %s", p.escapeExtra, p.subtest)
 				}
 			}
-			if !h.waitForContent("Feature Demos") {
+			if !h.WaitForContent("Feature Demos") {
 				t.Fatalf("timeout waiting for main menu after Escape from %s; content snippet: %s",
-					p.subtest, truncate(h.getContent(), 500))
+					p.subtest, truncate(h.Content(), 500))
 			}
 		}
 
 		t.Run("BackToMain", func(t *testing.T) {
 			// At 40 cols the full title is truncated; "Feature Demos" is visible at all sizes.
-			if !h.screenContains("Feature Demos") {
+			if !h.ScreenContains("Feature Demos") {
 				t.Errorf("after Escape, screen should show main menu; content snippet: %s",
-					truncate(h.getContent(), 500))
+					truncate(h.Content(), 500))
 			}
 			h.AssertSnapshot(t, "")
 		})
@@ -16,10 +16,12 @@ var (
 // RegisterClock adds startClock and stopClock as custom template functions
 // so the clock demo works via {{ startClock }} / {{ stopClock }} in YAML.
 // All clock logic lives in the example, not in the core library.
+// The ticker is also stopped on shutdown, so quitting while the clock runs leaks no goroutine.
 func RegisterClock(b *tviewyaml.AppBuilder) *tviewyaml.AppBuilder {
 	maxZero := 0
 	b.WithTemplateFunction("startClock", 0, &maxZero, nil, startClock)
 	b.WithTemplateFunction("stopClock", 0, &maxZero, nil, stopClock)
+	b.WithShutdown(haltClock)
 	return b
 }
 
@@ -34,7 +36,7 @@ func startClock(ctx *template.Context) {
 	clockMu.Unlock()
 
 	// Show time immediately (we're on main goroutine in button handler)
-	ctx.SetStateDirect("clock", time.Now().Format("15:04:05"))
+	ctx.SetStateDirect("clock", ctx.Now().Format("15:04:05"))
 
 	go func() {
 		ticker := time.NewTicker(1 * time.Second)
@@ -43,20 +45,19 @@ func startClock(ctx *template.Context) {
 			select {
 			case <-done:
 				return
-			case t := <-ticker.C:
-				app := ctx.App
-				if app == nil {
-					return
-				}
-				app.QueueUpdateDraw(func() {
-					ctx.SetStateDirect("clock", t.Format("15:04:05"))
-				})
+			case <-ticker.C:
+				ctx.SetState("clock", ctx.Now().Format("15:04:05"))
 			}
 		}
 	}()
 }
 
 func stopClock(ctx *template.Context) {
+	haltClock()
+}
+
+// haltClock stops the ticker goroutine, if running.
+func haltClock() {
 	clockMu.Lock()
 	defer clockMu.Unlock()
 	if clockDone != nil {
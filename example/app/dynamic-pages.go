@@ -50,21 +50,21 @@ func createDetailPageFor(ctx *template.Context, itemID string) {
 	form.AddButton("Remove This Page & Return", func() {
 		if ctx.Pages != nil {
 			ctx.Pages.RemovePage("dynamic-detail")
-			ctx.Pages.SwitchToPage("dynamic-pages")
+			ctx.SwitchToPage("dynamic-pages")
 		}
 	})
 
 	form.AddButton("Create Another Page", func() {
 		// Switch back to dynamic-pages to select another item
 		if ctx.Pages != nil {
-			ctx.Pages.SwitchToPage("dynamic-pages")
+			ctx.SwitchToPage("dynamic-pages")
 		}
 	})
 
 	form.AddButton("Main Menu", func() {
 		if ctx.Pages != nil {
 			ctx.Pages.RemovePage("dynamic-detail")
-			ctx.Pages.SwitchToPage("main")
+			ctx.SwitchToPage("main")
 		}
 	})
 
@@ -75,6 +75,6 @@ func createDetailPageFor(ctx *template.Context, itemID string) {
 		// Check if page already exists and remove it first
 		ctx.Pages.RemovePage("dynamic-detail")
 		ctx.Pages.AddPage("dynamic-detail", flex, true, false)
-		ctx.Pages.SwitchToPage("dynamic-detail")
+		ctx.SwitchToPage("dynamic-detail")
 	}
 }
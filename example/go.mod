@@ -4,14 +4,16 @@ go 1.25.3
 
 replace github.com/cassdeckard/tviewyaml => ../
 
-require github.com/cassdeckard/tviewyaml v0.0.0-00010101000000-000000000000
+require (
+	github.com/cassdeckard/tviewyaml v0.0.0-00010101000000-000000000000
+	github.com/gdamore/tcell/v2 v2.7.4
+	github.com/rivo/tview v0.0.0-20240101144852-b3bd1aa5e9f2
+)
 
 require (
 	github.com/gdamore/encoding v1.0.0 // indirect
-	github.com/gdamore/tcell/v2 v2.7.4 // indirect
 	github.com/lucasb-eyer/go-colorful v1.2.0 // indirect
 	github.com/mattn/go-runewidth v0.0.15 // indirect
-	github.com/rivo/tview v0.0.0-20240101144852-b3bd1aa5e9f2 // indirect
 	github.com/rivo/uniseg v0.4.7 // indirect
 	golang.org/x/sys v0.17.0 // indirect
 	golang.org/x/term v0.17.0 // indirect
@@ -7,10 +7,21 @@ import (
 	"github.com/gdamore/tcell/v2"
 )
 
+// ctrlSymbolKeys maps the key part of Ctrl+symbol bindings to the control key terminals deliver.
+var ctrlSymbolKeys = map[string]tcell.Key{
+	"space": tcell.KeyCtrlSpace,
+	"@":     tcell.KeyCtrlSpace,
+	"\\":    tcell.KeyCtrlBackslash,
+	"]":     tcell.KeyCtrlRightSq,
+	"^":     tcell.KeyCtrlCarat,
+	"_":     tcell.KeyCtrlUnderscore,
+}
+
 // ParseKey converts a key string to tcell key, modifiers, and rune.
 // Supports formats like:
 //   - "Escape", "Enter", "Tab" (special keys)
 //   - "Ctrl+Q", "Alt+F1", "Shift+Tab", "Ctrl+Alt+Q" (modified keys)
+//   - "Ctrl+Space", "Ctrl+@", "Ctrl+\\", "Ctrl+]", "Ctrl+^", "Ctrl+_" (control symbols)
 //   - "a", "Q", "space" (character keys)
 func ParseKey(keyStr string) (tcell.Key, tcell.ModMask, rune, error) {
 	keyStr = strings.TrimSpace(keyStr)
@@ -59,6 +70,15 @@ func ParseKey(keyStr string) (tcell.Key, tcell.ModMask, rune, error) {
 		return k, modMask, 0, nil
 	}
 
+	// Ctrl+symbol combinations arrive as ASCII control codes, which tcell reports as special keys
+	// (with ModCtrl), not runes: Ctrl+Space and Ctrl+@ send NUL, Ctrl+\ FS, Ctrl+] GS, Ctrl+^ RS,
+	// and Ctrl+_ US. Ctrl+[ sends ESC and is indistinguishable from Escape, so it is not mapped.
+	if modMask&tcell.ModCtrl != 0 {
+		if k, ok := ctrlSymbolKeys[keyLower]; ok {
+			return k, modMask, 0, nil
+		}
+	}
+
 	// space key - returns rune ' '
 	if keyLower == "space" {
 		return tcell.KeyRune, modMask, ' ', nil
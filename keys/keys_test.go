@@ -62,6 +62,16 @@ func TestParseKey(t *testing.T) {
 		{"Ctrl+Alt+Q", "Ctrl+Alt+Q", tcell.KeyRune, tcell.ModCtrl|tcell.ModAlt, 'Q', false, ""},
 		{"Ctrl+Shift+Enter", "Ctrl+Shift+Enter", tcell.KeyEnter, tcell.ModCtrl|tcell.ModShift, 0, false, ""},
 
+		// Control symbols (delivered as ASCII control codes)
+		{"Ctrl+Space", "Ctrl+Space", tcell.KeyCtrlSpace, tcell.ModCtrl, 0, false, ""},
+		{"Ctrl+@", "Ctrl+@", tcell.KeyNUL, tcell.ModCtrl, 0, false, ""},
+		{"Ctrl+backslash", "Ctrl+\\", tcell.KeyCtrlBackslash, tcell.ModCtrl, 0, false, ""},
+		{"Ctrl+]", "Ctrl+]", tcell.KeyCtrlRightSq, tcell.ModCtrl, 0, false, ""},
+		{"Ctrl+^", "Ctrl+^", tcell.KeyCtrlCarat, tcell.ModCtrl, 0, false, ""},
+		{"Ctrl+_", "Ctrl+_", tcell.KeyCtrlUnderscore, tcell.ModCtrl, 0, false, ""},
+		{"Alt+Space stays rune", "Alt+Space", tcell.KeyRune, tcell.ModAlt, ' ', false, ""},
+		{"Ctrl+Alt+Space", "Ctrl+Alt+Space", tcell.KeyCtrlSpace, tcell.ModCtrl|tcell.ModAlt, 0, false, ""},
+
 		// Whitespace handling
 		{"trimmed spaces", "  Escape  ", tcell.KeyEscape, 0, 0, false, ""},
 		{"trimmed modifier spaces", " Ctrl + Q ", tcell.KeyRune, tcell.ModCtrl, 'Q', false, ""},
@@ -1,10 +1,14 @@
 package template
 
 import (
+	"encoding/base64"
 	"fmt"
+	"io"
 	"strconv"
+	"strings"
 	"time"
 
+	"github.com/cassdeckard/tviewyaml/config"
 	"github.com/rivo/tview"
 )
 
@@ -20,13 +24,33 @@ func registerBuiltinFunctions(registry *FunctionRegistry) {
 			return ""
 		}
 		return fmt.Sprint(v)
+	}, "key")
+
+	// formatState: evaluator that formats the typed state value with fmt.Sprintf, e.g.
+	// {{ formatState count "%03d" }} renders the int 7 as "007". Empty when the key is unset.
+	registry.RegisterEvaluator("formatState", 2, 2, func(ctx *Context, args []string) string {
+		v, ok := ctx.GetState(args[0])
+		if !ok {
+			return ""
+		}
+		return fmt.Sprintf(args[1], v)
+	}, "key", "format")
+
+	// currentPage: evaluator that returns the front page's name (e.g. for a breadcrumb). Views using
+	// it refresh on navigation through __currentPage, which is set whenever a page is shown.
+	registry.RegisterEvaluator("currentPage", 0, 0, func(ctx *Context, args []string) string {
+		if ctx.Pages == nil {
+			return ""
+		}
+		name, _ := ctx.Pages.GetFrontPage()
+		return name
 	})
 
 	// showNotification: sets notification state so bound TextViews display it.
 	// Uses SetStateDirect (not SetState) because it's called from event handlers.
 	registry.Register("showNotification", 1, intPtr(1), nil, func(ctx *Context, msg string) {
 		ctx.SetStateDirect("notification", msg)
-	})
+	}, "message")
 
 	// showHighlightedNotification: sets notification to "Region X selected" using __highlightedRegion.
 	// For use in onHighlighted callbacks to show which region was selected.
@@ -39,71 +63,129 @@ func registerBuiltinFunctions(registry *FunctionRegistry) {
 		ctx.SetStateDirect("notification", msg)
 	})
 
-	// switchToPage: switches to a different page
+	// switchToPage: switches to a different page, remembering the current one for goBack
 	registry.Register("switchToPage", 1, intPtr(1), nil, func(ctx *Context, pageName string) {
-		if ctx.Pages != nil {
-			ctx.Pages.SwitchToPage(pageName)
-		}
+		ctx.SwitchToPage(pageName)
+	}, "pageName")
+
+	// goBack: returns to the previous page in the navigation history (no-op when there is none)
+	registry.Register("goBack", 0, intPtr(0), nil, func(ctx *Context) {
+		ctx.GoBack()
 	})
 
 	// removePage: removes a page from the pages container
 	registry.Register("removePage", 1, intPtr(1), nil, func(ctx *Context, pageName string) {
-		if ctx.Pages != nil {
-			ctx.Pages.RemovePage(pageName)
-		}
-	})
+		ctx.RemovePage(pageName)
+	}, "pageName")
 
 	// stopApp: stops the tview application
 	registry.Register("stopApp", 0, intPtr(0), nil, func(ctx *Context) {
-		if ctx.App != nil {
-			ctx.App.Stop()
-		}
+		ctx.Stop()
 	})
 
+	// quitWithConfirm: asks before stopping the application; OK stops it, Cancel (or Escape) closes the modal.
+	// Args: [optional prompt]. Example: {{ quitWithConfirm "Quit without saving?" }}
+	// Registered variadic so the prompt can be omitted; the validator caps it at one argument.
+	registry.Register("quitWithConfirm", 0, nil, func(ctx *Context, args []string) error {
+		if len(args) > 1 {
+			return fmt.Errorf("quitWithConfirm accepts at most 1 argument (prompt), got %d", len(args))
+		}
+		return nil
+	}, func(ctx *Context, args []string) {
+		prompt := "Quit?"
+		if len(args) > 0 && args[0] != "" {
+			prompt = args[0]
+		}
+		showModal(ctx, prompt, []string{"OK", "Cancel"}, func(buttonIndex int) {
+			if buttonIndex == 0 {
+				ctx.Stop()
+			}
+		}, nil)
+	}, "prompt")
+
 	// showSimpleModal: displays a simple modal with text and buttons.
 	// Args: text, [button labels...], [optional onDone template]. Example: "Done!" "OK" "switchToPage \"main\""
 	// Uses a unique page name so multiple modals can be shown without overwriting.
 	registry.Register("showSimpleModal", 1, nil, nil, func(ctx *Context, args []string) {
-		text := args[0]
-		var buttons []string
-		var onDone string
-		if len(args) >= 3 {
-			onDone = args[len(args)-1]
-			buttons = args[1 : len(args)-1]
-		} else {
-			buttons = args[1:]
-		}
-		if len(buttons) == 0 {
-			buttons = []string{"OK"}
+		buttons, onDone := modalButtonArgs(args[1:])
+		showModal(ctx, args[0], buttons, runOnDone(ctx, onDone), nil)
+	}, "text", "buttons...")
+
+	// showStyledModal: like showSimpleModal with colors; "" keeps the default for that color.
+	// Args: text, background, textColor, buttonColor, [button labels...], [optional onDone template].
+	// Example: "Save failed" "red" "white" "navy" "OK"
+	registry.Register("showStyledModal", 4, nil, nil, func(ctx *Context, args []string) {
+		background, textColor, buttonColor := args[1], args[2], args[3]
+		buttons, onDone := modalButtonArgs(args[4:])
+		showModal(ctx, args[0], buttons, runOnDone(ctx, onDone), func(modal *tview.Modal) {
+			if background != "" {
+				modal.SetBackgroundColor(ctx.Colors.Parse(background))
+			}
+			if textColor != "" {
+				modal.SetTextColor(ctx.Colors.Parse(textColor))
+			}
+			if buttonColor != "" {
+				modal.SetButtonBackgroundColor(ctx.Colors.Parse(buttonColor))
+			}
+		})
+	}, "text", "background", "textColor", "buttonColor", "buttons...")
+
+	// confirm: shows an OK/Cancel modal; OK runs the action template, Cancel (or Escape) only closes it.
+	// Example: {{ confirm "Delete item?" "{{ deleteItem }}" }}
+	registry.Register("confirm", 2, intPtr(2), func(ctx *Context, args []string) error {
+		if err := ctx.CheckCallback(args[1]); err != nil {
+			return fmt.Errorf("confirm action: %w", err)
 		}
+		return nil
+	}, func(ctx *Context, prompt, action string) {
+		showModal(ctx, prompt, []string{"OK", "Cancel"}, func(buttonIndex int) {
+			if buttonIndex == 0 {
+				ctx.RunCallback(action)
+			}
+		}, nil)
+	}, "prompt", "action")
 
-		if ctx.Pages == nil {
-			return
+	// async: runs a template expression on a goroutine (see Context.Go) so slow actions do not block
+	// the UI. With a state key, the key is true while the action runs and false once it returns,
+	// e.g. to show a spinner or "Loading..." text bound to it.
+	// Args: action [busyKey]. Example: {{ async "{{ fetchData }}" "loading" }}
+	registry.Register("async", 1, nil, func(ctx *Context, args []string) error {
+		if len(args) > 2 {
+			return fmt.Errorf("async accepts at most 2 arguments (action, busyKey), got %d", len(args))
 		}
-		pageName := "simple-modal-" + strconv.FormatInt(time.Now().UnixNano(), 10)
-		modal := tview.NewModal().
-			SetText(text).
-			AddButtons(buttons).
-			SetDoneFunc(func(buttonIndex int, buttonLabel string) {
-				if onDone != "" {
-					ctx.RunCallback(onDone)
-				}
-				if ctx.Pages != nil {
-					ctx.Pages.RemovePage(pageName)
-				}
-			})
-		ctx.Pages.AddPage(pageName, modal, false, true)
-	})
+		if strings.TrimSpace(args[0]) == "" {
+			return fmt.Errorf("async requires a template expression to run")
+		}
+		if err := ctx.CheckCallback(args[0]); err != nil {
+			return fmt.Errorf("async action: %w", err)
+		}
+		return nil
+	}, func(ctx *Context, args []string) {
+		action := args[0]
+		busyKey := ""
+		if len(args) > 1 {
+			busyKey = args[1]
+		}
+		if busyKey != "" {
+			ctx.SetState(busyKey, true)
+		}
+		ctx.Go(func() {
+			if busyKey != "" {
+				defer ctx.SetState(busyKey, false)
+			}
+			ctx.RunCallback(action)
+		})
+	}, "action", "busyKey")
 
 	// runFormSubmit: runs the submit callback registered for the form name (e.g. from a Submit button).
 	registry.Register("runFormSubmit", 1, intPtr(1), nil, func(ctx *Context, formName string) {
 		ctx.RunFormSubmit(formName)
-	})
+	}, "formName")
 
 	// runFormCancel: runs the cancel callback registered for the form name (e.g. from a Cancel button).
 	registry.Register("runFormCancel", 1, intPtr(1), nil, func(ctx *Context, formName string) {
 		ctx.RunFormCancel(formName)
-	})
+	}, "formName")
 
 	// showSelectedCellModal: shows a modal with the currently selected table cell info (reads __selectedCellText, __selectedRow, __selectedCol from state).
 	registry.Register("showSelectedCellModal", 0, intPtr(0), nil, func(ctx *Context) {
@@ -145,8 +227,329 @@ func registerBuiltinFunctions(registry *FunctionRegistry) {
 		ctx.Pages.AddPage(pageName, modal, false, true)
 	})
 
+	// showKeyHelp: shows a modal listing the global key bindings from the app config with their descriptions.
+	registry.Register("showKeyHelp", 0, intPtr(0), nil, func(ctx *Context) {
+		if ctx.Pages == nil {
+			return
+		}
+		pageName := "key-help-modal-" + strconv.FormatInt(time.Now().UnixNano(), 10)
+		modal := tview.NewModal().
+			SetText(keyHelpText(ctx.KeyBindings())).
+			AddButtons([]string{"OK"}).
+			SetDoneFunc(func(buttonIndex int, buttonLabel string) {
+				if ctx.Pages != nil {
+					ctx.Pages.RemovePage(pageName)
+				}
+			})
+		ctx.Pages.AddPage(pageName, modal, false, true)
+	})
+
+	// appendText: appends a line to a named TextView and scrolls to the end (e.g. a log panel).
+	// The write is queued on the main goroutine; the TextView's maxLines cap is applied by tview on redraw.
+	registry.Register("appendText", 2, intPtr(2), nil, func(ctx *Context, name, line string) {
+		p, ok := ctx.GetPrimitive(name)
+		if !ok {
+			return
+		}
+		tv, ok := p.(*tview.TextView)
+		if !ok {
+			return
+		}
+		ctx.QueueUpdateDraw(func() {
+			if text := tv.GetText(false); text != "" && !strings.HasSuffix(text, "\n") {
+				line = "\n" + line
+			}
+			fmt.Fprint(tv, line)
+			tv.ScrollToEnd()
+		})
+	}, "name", "line")
+
+	// expandAllNodes / collapseAllNodes: expand or collapse every node of a named TreeView. The root
+	// stays expanded when collapsing so the top level remains visible.
+	registry.Register("expandAllNodes", 1, intPtr(1), nil, func(ctx *Context, name string) {
+		setTreeExpanded(ctx, name, true)
+	}, "name")
+	registry.Register("collapseAllNodes", 1, intPtr(1), nil, func(ctx *Context, name string) {
+		setTreeExpanded(ctx, name, false)
+	}, "name")
+
+	// focus: moves keyboard focus to a named primitive (e.g. from a search box to its results).
+	// Unknown names are logged and ignored.
+	registry.Register("focus", 1, intPtr(1), nil, func(ctx *Context, name string) {
+		p, ok := ctx.GetPrimitive(name)
+		if !ok {
+			ctx.Logf("focus: no primitive named %q", name)
+			return
+		}
+		ctx.QueueUpdateDraw(func() {
+			if ctx.App != nil {
+				ctx.App.SetFocus(p)
+			}
+		})
+	}, "name")
+
+	// resetForm: restore a named form's fields to their configured initial values (e.g. from a
+	// Reset button); unknown forms are logged
+	registry.Register("resetForm", 1, intPtr(1), nil, func(ctx *Context, formName string) {
+		ctx.QueueUpdateDraw(func() {
+			if !ctx.ResetForm(formName) {
+				ctx.Logf("resetForm: no form named %q", formName)
+			}
+		})
+	}, "formName")
+
+	// focusNext / focusPrev: move focus to the next (previous) primitive of a focus group
+	// (application.focusGroups), wrapping around; e.g. bind Tab to cycle a dashboard's panels
+	registry.Register("focusNext", 1, intPtr(1), nil, func(ctx *Context, group string) {
+		cycleFocus(ctx, "focusNext", group, 1)
+	}, "group")
+	registry.Register("focusPrev", 1, intPtr(1), nil, func(ctx *Context, group string) {
+		cycleFocus(ctx, "focusPrev", group, -1)
+	}, "group")
+
+	// clearInput: empties a named InputField or TextArea (e.g. a compose box after sending).
+	// Unknown names and other primitive types are ignored.
+	registry.Register("clearInput", 1, intPtr(1), nil, func(ctx *Context, name string) {
+		p, ok := ctx.GetPrimitive(name)
+		if !ok {
+			return
+		}
+		switch field := p.(type) {
+		case *tview.InputField:
+			ctx.QueueUpdateDraw(func() { field.SetText("") })
+		case *tview.TextArea:
+			ctx.QueueUpdateDraw(func() { field.SetText("", false) })
+		}
+	}, "name")
+
+	// copyToClipboard / copyStateToClipboard: copy text, or the value of a state key (e.g.
+	// __selectedCellText), to the system clipboard. The text is sent to the terminal as an OSC 52
+	// escape sequence, so it works over SSH without a clipboard library, in terminals that support it.
+	registry.Register("copyToClipboard", 1, intPtr(1), nil, func(ctx *Context, text string) {
+		copyToClipboard(ctx, text)
+	}, "text")
+	registry.Register("copyStateToClipboard", 1, intPtr(1), nil, func(ctx *Context, key string) {
+		v, _ := ctx.GetState(key)
+		if v == nil {
+			v = ""
+		}
+		copyToClipboard(ctx, fmt.Sprint(v))
+	}, "key")
+
+	// setTerminalTitle: set the terminal window title (OSC 2 escape sequence)
+	registry.Register("setTerminalTitle", 1, intPtr(1), nil, func(ctx *Context, title string) {
+		writeTerminal(ctx, "setTerminalTitle", "\x1b]2;"+title+"\a")
+	}, "title")
+
+	// bell: sound the terminal bell (e.g. on errors)
+	registry.Register("bell", 0, intPtr(0), nil, func(ctx *Context) {
+		screen := ctx.Screen()
+		if screen == nil {
+			ctx.Logf("bell: the application has not drawn to a screen yet")
+			return
+		}
+		ctx.QueueUpdateDraw(func() {
+			if err := screen.Beep(); err != nil {
+				ctx.Logf("bell: %v", err)
+			}
+		})
+	})
+
+	// listFunctions: fills a named TextView with every registered function and evaluator, one per
+	// line with its arguments and description (e.g. an "available actions" help page).
+	registry.Register("listFunctions", 1, intPtr(1), nil, func(ctx *Context, name string) {
+		p, ok := ctx.GetPrimitive(name)
+		if !ok {
+			ctx.Logf("listFunctions: no primitive named %q", name)
+			return
+		}
+		tv, ok := p.(*tview.TextView)
+		if !ok {
+			ctx.Logf("listFunctions: primitive %q is a %T, not a TextView", name, p)
+			return
+		}
+		var lines []string
+		for _, info := range registry.List() {
+			line := info.Usage()
+			if info.Description != "" {
+				line += " - " + info.Description
+			}
+			lines = append(lines, line)
+		}
+		ctx.QueueUpdateDraw(func() {
+			tv.SetText(strings.Join(lines, "\n"))
+		})
+	}, "name")
+
 	// noop: does nothing (useful for testing or placeholder actions)
 	registry.Register("noop", 0, intPtr(0), nil, func(ctx *Context) {
 		// Do nothing
 	})
+
+	for name, description := range builtinDescriptions {
+		registry.Describe(name, description)
+	}
+}
+
+// builtinDescriptions are the one-line summaries that List reports for the builtins.
+var builtinDescriptions = map[string]string{
+	"bindState":                   "Value of a state key",
+	"formatState":                 "Value of a state key formatted with fmt.Sprintf",
+	"currentPage":                 "Name of the front page",
+	"showNotification":            "Set the notification text",
+	"showHighlightedNotification": "Notify which text view region is highlighted",
+	"switchToPage":                "Show a page, remembering the current one for goBack",
+	"goBack":                      "Return to the previous page",
+	"removePage":                  "Remove a page",
+	"stopApp":                     "Stop the application",
+	"quitWithConfirm":             "Ask before stopping the application",
+	"showSimpleModal":             "Show a modal with text and buttons",
+	"showStyledModal":             "Show a modal with text, buttons, and colors",
+	"confirm":                     "Run an action after OK in an OK/Cancel modal",
+	"async":                       "Run an action in the background",
+	"runFormSubmit":               "Run a form's submit callback",
+	"runFormCancel":               "Run a form's cancel callback",
+	"showSelectedCellModal":       "Show the selected table cell in a modal",
+	"showSelectedNodeModal":       "Show the selected tree node in a modal",
+	"showKeyHelp":                 "Show the global key bindings in a modal",
+	"appendText":                  "Append a line to a text view",
+	"expandAllNodes":              "Expand every node of a tree view",
+	"collapseAllNodes":            "Collapse every node of a tree view",
+	"focus":                       "Focus a primitive",
+	"resetForm":                   "Restore a form's initial values",
+	"focusNext":                   "Focus the next primitive of a focus group",
+	"focusPrev":                   "Focus the previous primitive of a focus group",
+	"clearInput":                  "Empty an input field or text area",
+	"copyToClipboard":             "Copy text to the clipboard",
+	"copyStateToClipboard":        "Copy a state value to the clipboard",
+	"setTerminalTitle":            "Set the terminal window title",
+	"bell":                        "Sound the terminal bell",
+	"listFunctions":               "List the available functions in a text view",
+	"noop":                        "Do nothing",
+}
+
+// cycleFocus queues Context.CycleFocus on the main goroutine and logs unknown groups.
+func cycleFocus(ctx *Context, name, group string, step int) {
+	ctx.QueueUpdateDraw(func() {
+		if !ctx.CycleFocus(group, step) {
+			ctx.Logf("%s: no focus group named %q", name, group)
+		}
+	})
+}
+
+// copyToClipboard writes an OSC 52 sequence setting the clipboard to text to the app's terminal.
+func copyToClipboard(ctx *Context, text string) {
+	writeTerminal(ctx, "copyToClipboard", "\x1b]52;c;"+base64.StdEncoding.EncodeToString([]byte(text))+"\a")
+}
+
+// writeTerminal writes a raw escape sequence to the app's terminal. The write is queued on the
+// main goroutine so it does not interleave with a draw. Screens that are not terminals (e.g.
+// before the first draw) are logged under the builtin's name and ignored.
+func writeTerminal(ctx *Context, name, seq string) {
+	screen := ctx.Screen()
+	if screen == nil {
+		ctx.Logf("%s: the application has not drawn to a screen yet", name)
+		return
+	}
+	tty, ok := screen.Tty()
+	if !ok {
+		ctx.Logf("%s: the screen is not a terminal", name)
+		return
+	}
+	ctx.QueueUpdateDraw(func() {
+		if _, err := io.WriteString(tty, seq); err != nil {
+			ctx.Logf("%s: %v", name, err)
+		}
+	})
+}
+
+// setTreeExpanded sets the expanded state of every node below the named TreeView's root (and of
+// the root itself when expanding). Unknown names and other primitive types are ignored.
+func setTreeExpanded(ctx *Context, name string, expanded bool) {
+	p, ok := ctx.GetPrimitive(name)
+	if !ok {
+		return
+	}
+	tree, ok := p.(*tview.TreeView)
+	if !ok || tree.GetRoot() == nil {
+		return
+	}
+	ctx.QueueUpdateDraw(func() {
+		tree.GetRoot().Walk(func(node, parent *tview.TreeNode) bool {
+			if parent != nil || expanded {
+				node.SetExpanded(expanded)
+			}
+			return true
+		})
+	})
+}
+
+// keyHelpText formats key bindings as one "key  description" line each, with keys padded to a common width.
+// Bindings without a description show their action.
+func keyHelpText(bindings []config.KeyBinding) string {
+	if len(bindings) == 0 {
+		return "No key bindings"
+	}
+	width := 0
+	for _, binding := range bindings {
+		if len(binding.Key) > width {
+			width = len(binding.Key)
+		}
+	}
+	lines := make([]string, 0, len(bindings))
+	for _, binding := range bindings {
+		desc := binding.Description
+		if desc == "" {
+			desc = binding.Action
+		}
+		lines = append(lines, fmt.Sprintf("%-*s  %s", width, binding.Key, desc))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// modalButtonArgs splits the trailing modal args into button labels and an optional onDone
+// template: with two or more args the last is onDone. No labels defaults to a single "OK".
+func modalButtonArgs(args []string) (buttons []string, onDone string) {
+	if len(args) >= 2 {
+		onDone = args[len(args)-1]
+		buttons = args[:len(args)-1]
+	} else {
+		buttons = args
+	}
+	if len(buttons) == 0 {
+		buttons = []string{"OK"}
+	}
+	return buttons, onDone
+}
+
+// runOnDone returns a modal done func that runs the onDone template whichever button was pressed.
+func runOnDone(ctx *Context, onDone string) func(buttonIndex int) {
+	return func(int) {
+		if onDone != "" {
+			ctx.RunCallback(onDone)
+		}
+	}
+}
+
+// showModal adds a modal on a unique page (so multiple modals can be shown without overwriting)
+// and removes it when a button is pressed (or Escape, with index -1) after calling done.
+// style, if non-nil, customizes the modal before it is shown.
+func showModal(ctx *Context, text string, buttons []string, done func(buttonIndex int), style func(*tview.Modal)) {
+	if ctx.Pages == nil {
+		return
+	}
+	pageName := "simple-modal-" + strconv.FormatInt(time.Now().UnixNano(), 10)
+	modal := tview.NewModal().
+		SetText(text).
+		AddButtons(buttons).
+		SetDoneFunc(func(buttonIndex int, buttonLabel string) {
+			done(buttonIndex)
+			if ctx.Pages != nil {
+				ctx.Pages.RemovePage(pageName)
+			}
+		})
+	if style != nil {
+		style(modal)
+	}
+	ctx.Pages.AddPage(pageName, modal, false, true)
 }
@@ -0,0 +1,672 @@
+package template
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cassdeckard/tviewyaml/config"
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+// newRunningTestContext starts a tview application on a simulation screen with root as the
+// visible page, so builtins that queue updates on the main goroutine can be observed.
+func newRunningTestContext(t *testing.T, root tview.Primitive) *Context {
+	t.Helper()
+	sim := tcell.NewSimulationScreen("UTF-8")
+	if err := sim.Init(); err != nil {
+		t.Fatalf("SimulationScreen Init: %v", err)
+	}
+	sim.SetSize(40, 10)
+	app := tview.NewApplication().SetScreen(sim)
+	pages := tview.NewPages()
+	if root != nil {
+		pages.AddPage("root", root, true, true)
+	}
+	app.SetRoot(pages, true)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		_ = app.Run()
+	}()
+	t.Cleanup(func() {
+		app.Stop()
+		<-done
+	})
+	return NewContext(app, pages)
+}
+
+// textOnMain reads a TextView's text on the main goroutine (tview's GetText is not locked).
+func textOnMain(ctx *Context, tv *tview.TextView) string {
+	var text string
+	ctx.App.QueueUpdate(func() { text = tv.GetText(false) })
+	return text
+}
+
+// waitFor polls cond until it returns true or a second has passed.
+func waitFor(cond func() bool) bool {
+	deadline := time.Now().Add(time.Second)
+	for time.Now().Before(deadline) {
+		if cond() {
+			return true
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	return cond()
+}
+
+func TestBuiltin_AppendText(t *testing.T) {
+	tv := tview.NewTextView()
+	ctx := newRunningTestContext(t, tv)
+	ctx.RegisterPrimitive("log", tv)
+	executor := NewExecutor(ctx, NewFunctionRegistry())
+
+	for _, line := range []string{"connected", "ready", "done"} {
+		cb, err := executor.ExecuteCallback(`{{ appendText "log" "` + line + `" }}`)
+		if err != nil {
+			t.Fatalf("ExecuteCallback: %v", err)
+		}
+		cb()
+	}
+
+	want := "connected\nready\ndone"
+	if !waitFor(func() bool { return textOnMain(ctx, tv) == want }) {
+		t.Errorf("GetText() = %q, want %q", textOnMain(ctx, tv), want)
+	}
+}
+
+func TestBuiltin_ListFunctions(t *testing.T) {
+	tv := tview.NewTextView()
+	ctx := newRunningTestContext(t, tv)
+	ctx.RegisterPrimitive("help", tv)
+	registry := NewFunctionRegistry()
+	one := 1
+	if err := registry.Register("openFile", 1, &one, nil, func(ctx *Context, path string) {}, "path"); err != nil {
+		t.Fatalf("Register: %v", err)
+	}
+	if err := registry.Describe("openFile", "Open a file"); err != nil {
+		t.Fatalf("Describe: %v", err)
+	}
+	executor := NewExecutor(ctx, registry)
+
+	cb, err := executor.ExecuteCallback(`{{ listFunctions "help" }}`)
+	if err != nil {
+		t.Fatalf("ExecuteCallback: %v", err)
+	}
+	cb()
+	if !waitFor(func() bool { return textOnMain(ctx, tv) != "" }) {
+		t.Fatal("help text view was not filled")
+	}
+	text := textOnMain(ctx, tv)
+	for _, want := range []string{
+		"openFile path - Open a file",
+		"async action [busyKey] - Run an action in the background",
+		"bindState key - Value of a state key",
+		"goBack - Return to the previous page",
+	} {
+		if !strings.Contains(text, want) {
+			t.Errorf("help text missing %q; got:\n%s", want, text)
+		}
+	}
+}
+
+func TestBuiltin_AppendTextMaxLines(t *testing.T) {
+	tv := tview.NewTextView().SetMaxLines(2)
+	ctx := newRunningTestContext(t, tv)
+	ctx.RegisterPrimitive("log", tv)
+	executor := NewExecutor(ctx, NewFunctionRegistry())
+
+	for _, line := range []string{"one", "two", "three", "four"} {
+		cb, err := executor.ExecuteCallback(`{{ appendText "log" "` + line + `" }}`)
+		if err != nil {
+			t.Fatalf("ExecuteCallback: %v", err)
+		}
+		cb()
+	}
+
+	want := "three\nfour"
+	if !waitFor(func() bool { return textOnMain(ctx, tv) == want }) {
+		t.Errorf("GetText() = %q, want %q", textOnMain(ctx, tv), want)
+	}
+}
+
+func TestBuiltin_AppendTextUnknownPrimitive(t *testing.T) {
+	ctx := newTestContext()
+	executor := NewExecutor(ctx, NewFunctionRegistry())
+	cb, err := executor.ExecuteCallback(`{{ appendText "missing" "line" }}`)
+	if err != nil {
+		t.Fatalf("ExecuteCallback: %v", err)
+	}
+	cb() // no-op, must not panic
+}
+
+func TestBuiltin_ExpandCollapseAllNodes(t *testing.T) {
+	root := tview.NewTreeNode("root")
+	child := tview.NewTreeNode("child").SetExpanded(false)
+	grandchild := tview.NewTreeNode("grandchild").SetExpanded(false)
+	child.AddChild(grandchild).AddChild(tview.NewTreeNode("leaf").SetExpanded(false))
+	root.AddChild(child).AddChild(tview.NewTreeNode("sibling").SetExpanded(false))
+	tree := tview.NewTreeView().SetRoot(root)
+
+	ctx := newTestContext()
+	ctx.App = nil // run queued updates synchronously
+	ctx.RegisterPrimitive("browser", tree)
+	executor := NewExecutor(ctx, NewFunctionRegistry())
+	run := func(expr string) {
+		t.Helper()
+		cb, err := executor.ExecuteCallback(expr)
+		if err != nil {
+			t.Fatalf("ExecuteCallback: %v", err)
+		}
+		cb()
+	}
+	expandedStates := func() []bool {
+		var states []bool
+		root.Walk(func(node, parent *tview.TreeNode) bool {
+			states = append(states, node.IsExpanded())
+			return true
+		})
+		return states
+	}
+
+	run(`{{ expandAllNodes "browser" }}`)
+	for i, expanded := range expandedStates() {
+		if !expanded {
+			t.Errorf("node %d collapsed after expandAllNodes", i)
+		}
+	}
+
+	run(`{{ collapseAllNodes "browser" }}`)
+	for i, expanded := range expandedStates() {
+		if want := i == 0; expanded != want {
+			t.Errorf("node %d expanded = %v after collapseAllNodes, want %v", i, expanded, want)
+		}
+	}
+
+	run(`{{ expandAllNodes "missing" }}`) // no-op, must not panic
+}
+
+func TestBuiltin_Focus(t *testing.T) {
+	search := tview.NewInputField()
+	results := tview.NewList().AddItem("first", "", 0, nil)
+	ctx := newRunningTestContext(t, tview.NewFlex().AddItem(search, 1, 0, true).AddItem(results, 0, 1, false))
+	ctx.RegisterPrimitive("search", search)
+	ctx.RegisterPrimitive("results", results)
+	var logged []string
+	ctx.SetLogger(func(format string, args ...interface{}) { logged = append(logged, fmt.Sprintf(format, args...)) })
+	executor := NewExecutor(ctx, NewFunctionRegistry())
+	run := func(expr string) {
+		t.Helper()
+		cb, err := executor.ExecuteCallback(expr)
+		if err != nil {
+			t.Fatalf("ExecuteCallback: %v", err)
+		}
+		cb()
+	}
+	focused := func() tview.Primitive {
+		var p tview.Primitive
+		ctx.App.QueueUpdate(func() { p = ctx.App.GetFocus() })
+		return p
+	}
+
+	run(`{{ focus "results" }}`)
+	if !waitFor(func() bool { return focused() == results }) {
+		t.Fatalf("focus = %T, want the results list", focused())
+	}
+
+	run(`{{ focus "missing" }}`)
+	if len(logged) != 1 || !strings.Contains(logged[0], `"missing"`) {
+		t.Errorf("logged %q, want one message naming the missing primitive", logged)
+	}
+	if focused() != results {
+		t.Error("focus with an unknown name should leave focus unchanged")
+	}
+
+	if _, err := executor.ExecuteCallback(`{{ focus }}`); err == nil {
+		t.Error("focus without arguments should fail validation")
+	}
+}
+
+func TestBuiltin_FocusNextPrev(t *testing.T) {
+	left, middle, right := tview.NewTextView(), tview.NewList(), tview.NewTable()
+	ctx := newRunningTestContext(t, tview.NewFlex().AddItem(left, 0, 1, true).AddItem(middle, 0, 1, false).AddItem(right, 0, 1, false))
+	ctx.RegisterPrimitive("left", left)
+	ctx.RegisterPrimitive("middle", middle)
+	ctx.RegisterPrimitive("right", right)
+	ctx.SetFocusGroups(map[string][]string{"panels": {"left", "missing", "middle", "right"}})
+	var logged []string
+	ctx.SetLogger(func(format string, args ...interface{}) { logged = append(logged, fmt.Sprintf(format, args...)) })
+	executor := NewExecutor(ctx, NewFunctionRegistry())
+	focused := func() tview.Primitive {
+		var p tview.Primitive
+		ctx.App.QueueUpdate(func() { p = ctx.App.GetFocus() })
+		return p
+	}
+	step := func(expr string, want tview.Primitive) {
+		t.Helper()
+		cb, err := executor.ExecuteCallback(expr)
+		if err != nil {
+			t.Fatalf("ExecuteCallback: %v", err)
+		}
+		cb()
+		if !waitFor(func() bool { return focused() == want }) {
+			t.Fatalf("after %s focus = %T, want %T", expr, focused(), want)
+		}
+	}
+
+	// Focus starts on left, a member, so focusNext moves on from there; "missing" is skipped
+	step(`{{ focusNext "panels" }}`, middle)
+	step(`{{ focusNext "panels" }}`, right)
+	step(`{{ focusNext "panels" }}`, left) // wraps
+	step(`{{ focusPrev "panels" }}`, right)
+	step(`{{ focusPrev "panels" }}`, middle)
+
+	// Focus moved by other means (e.g. a click) is where the group continues from
+	ctx.App.QueueUpdate(func() { ctx.App.SetFocus(left) })
+	step(`{{ focusNext "panels" }}`, middle)
+
+	cb, err := executor.ExecuteCallback(`{{ focusNext "nope" }}`)
+	if err != nil {
+		t.Fatalf("ExecuteCallback: %v", err)
+	}
+	cb()
+	if !waitFor(func() bool { var n int; ctx.App.QueueUpdate(func() { n = len(logged) }); return n == 1 }) {
+		t.Errorf("logged %q, want one message for the unknown group", logged)
+	}
+}
+
+func TestBuiltin_ResetForm(t *testing.T) {
+	ctx := newTestContext()
+	ctx.App = nil // run queued updates synchronously
+	var logged []string
+	ctx.SetLogger(func(format string, args ...interface{}) { logged = append(logged, fmt.Sprintf(format, args...)) })
+	form := tview.NewForm().
+		AddInputField("Name", "Ada", 20, nil, nil).
+		AddCheckbox("Notify", true, nil).
+		AddDropDown("Theme", []string{"dark", "light"}, 0, nil).
+		AddTextArea("Bio", "Mathematician", 20, 3, 0, nil).
+		AddButton("Reset", nil)
+	// tview's text fields only edit text reliably once they have been laid out, so the form is drawn first.
+	screen := tcell.NewSimulationScreen("")
+	if err := screen.Init(); err != nil {
+		t.Fatalf("screen.Init: %v", err)
+	}
+	screen.SetSize(40, 12)
+	form.SetRect(0, 0, 40, 12)
+	form.Draw(screen)
+	ctx.RecordFormInitialValues("settings", form)
+
+	form.GetFormItem(0).(*tview.InputField).SetText("Grace")
+	form.GetFormItem(1).(*tview.Checkbox).SetChecked(false)
+	form.GetFormItem(2).(*tview.DropDown).SetCurrentOption(1)
+	form.GetFormItem(3).(*tview.TextArea).SetText("Admiral", false)
+
+	executor := NewExecutor(ctx, NewFunctionRegistry())
+	for _, expr := range []string{`{{ resetForm "settings" }}`, `{{ resetForm "missing" }}`} {
+		cb, err := executor.ExecuteCallback(expr)
+		if err != nil {
+			t.Fatalf("ExecuteCallback: %v", err)
+		}
+		cb()
+	}
+
+	if got := form.GetFormItem(0).(*tview.InputField).GetText(); got != "Ada" {
+		t.Errorf("input = %q, want Ada", got)
+	}
+	if !form.GetFormItem(1).(*tview.Checkbox).IsChecked() {
+		t.Error("checkbox should be checked again")
+	}
+	if got, _ := form.GetFormItem(2).(*tview.DropDown).GetCurrentOption(); got != 0 {
+		t.Errorf("dropdown option = %d, want 0", got)
+	}
+	if got := form.GetFormItem(3).(*tview.TextArea).GetText(); got != "Mathematician" {
+		t.Errorf("textarea = %q, want Mathematician", got)
+	}
+	if len(logged) != 1 || !strings.Contains(logged[0], `"missing"`) {
+		t.Errorf("logged %q, want one message naming the unknown form", logged)
+	}
+}
+
+func TestBuiltin_ClearInput(t *testing.T) {
+	input := tview.NewInputField().SetText("hello")
+	area := tview.NewTextArea().SetText("line one\nline two", true)
+	// tview's text fields only edit text reliably once they have been laid out, so they are drawn first.
+	ctx := newRunningTestContext(t, tview.NewFlex().SetDirection(tview.FlexRow).AddItem(input, 1, 0, true).AddItem(area, 0, 1, false))
+	ctx.RegisterPrimitive("composeBox", input)
+	ctx.RegisterPrimitive("notes", area)
+	ctx.RegisterPrimitive("label", tview.NewTextView().SetText("keep"))
+	executor := NewExecutor(ctx, NewFunctionRegistry())
+	ctx.App.Draw()
+	for _, name := range []string{"composeBox", "notes", "label", "missing"} {
+		cb, err := executor.ExecuteCallback(`{{ clearInput "` + name + `" }}`)
+		if err != nil {
+			t.Fatalf("ExecuteCallback: %v", err)
+		}
+		cb() // other types and unknown names are no-ops
+	}
+
+	texts := func() (string, string) {
+		var inputText, areaText string
+		ctx.App.QueueUpdate(func() { inputText, areaText = input.GetText(), area.GetText() })
+		return inputText, areaText
+	}
+	if !waitFor(func() bool { i, a := texts(); return i == "" && a == "" }) {
+		i, a := texts()
+		t.Errorf("input/text area text = %q/%q after clearInput, want both empty", i, a)
+	}
+	if _, err := executor.ExecuteCallback(`{{ clearInput "a" "b" }}`); err == nil {
+		t.Error("clearInput with two arguments should fail validation")
+	}
+}
+
+// ttyScreen is a simulation screen with a terminal that records what is written to it and
+// counts bells.
+type ttyScreen struct {
+	tcell.SimulationScreen
+	tty   *recordingTty
+	beeps int
+}
+
+func (s *ttyScreen) Tty() (tcell.Tty, bool) { return s.tty, true }
+func (s *ttyScreen) Beep() error            { s.beeps++; return nil }
+
+type recordingTty struct{ strings.Builder }
+
+func (*recordingTty) Start() error                          { return nil }
+func (*recordingTty) Stop() error                           { return nil }
+func (*recordingTty) Drain() error                          { return nil }
+func (*recordingTty) NotifyResize(func())                   {}
+func (*recordingTty) WindowSize() (tcell.WindowSize, error) { return tcell.WindowSize{}, nil }
+func (*recordingTty) Read([]byte) (int, error)              { return 0, nil }
+func (*recordingTty) Close() error                          { return nil }
+
+func TestBuiltin_CopyToClipboard(t *testing.T) {
+	ctx := newTestContext()
+	ctx.App = nil // run queued updates synchronously
+	var logged []string
+	ctx.SetLogger(func(format string, args ...interface{}) { logged = append(logged, fmt.Sprintf(format, args...)) })
+	executor := NewExecutor(ctx, NewFunctionRegistry())
+	run := func(expr string) {
+		t.Helper()
+		cb, err := executor.ExecuteCallback(expr)
+		if err != nil {
+			t.Fatalf("ExecuteCallback: %v", err)
+		}
+		cb()
+	}
+
+	run(`{{ copyToClipboard "before any draw" }}`)
+	if len(logged) != 1 {
+		t.Errorf("logged %q, want one message when there is no screen yet", logged)
+	}
+
+	screen := &ttyScreen{SimulationScreen: tcell.NewSimulationScreen(""), tty: &recordingTty{}}
+	ctx.SetScreen(screen)
+	run(`{{ copyToClipboard "hello" }}`)
+	ctx.SetStateDirect("__selectedCellText", "42 Main St")
+	run(`{{ copyStateToClipboard "__selectedCellText" }}`)
+
+	// base64("hello") = aGVsbG8=, base64("42 Main St") = NDIgTWFpbiBTdA==
+	want := "\x1b]52;c;aGVsbG8=\a\x1b]52;c;NDIgTWFpbiBTdA==\a"
+	if got := screen.tty.String(); got != want {
+		t.Errorf("terminal received %q, want %q", got, want)
+	}
+}
+
+func TestBuiltin_TerminalTitleAndBell(t *testing.T) {
+	ctx := newTestContext()
+	ctx.App = nil // run queued updates synchronously
+	var logged []string
+	ctx.SetLogger(func(format string, args ...interface{}) { logged = append(logged, fmt.Sprintf(format, args...)) })
+	executor := NewExecutor(ctx, NewFunctionRegistry())
+	run := func(expr string) {
+		t.Helper()
+		cb, err := executor.ExecuteCallback(expr)
+		if err != nil {
+			t.Fatalf("ExecuteCallback: %v", err)
+		}
+		cb()
+	}
+
+	run(`{{ bell }}`)
+	if len(logged) != 1 {
+		t.Errorf("logged %q, want one message when there is no screen yet", logged)
+	}
+
+	screen := &ttyScreen{SimulationScreen: tcell.NewSimulationScreen(""), tty: &recordingTty{}}
+	ctx.SetScreen(screen)
+	run(`{{ bell }}`)
+	run(`{{ bell }}`)
+	if screen.beeps != 2 {
+		t.Errorf("beeps = %d, want 2", screen.beeps)
+	}
+
+	run(`{{ setTerminalTitle "Inbox (3)" }}`)
+	if got, want := screen.tty.String(), "\x1b]2;Inbox (3)\a"; got != want {
+		t.Errorf("terminal received %q, want %q", got, want)
+	}
+}
+
+func TestBuiltin_QuitWithConfirm(t *testing.T) {
+	ctx := newTestContext()
+	ctx.Pages.AddPage("main", tview.NewBox(), true, true)
+	stops := 0
+	ctx.SetStopFunc(func() { stops++ })
+	executor := NewExecutor(ctx, NewFunctionRegistry())
+
+	// quit shows the modal and presses its buttons (focus is delegated by hand; the app is not running)
+	quit := func(expr string, keys ...tcell.Key) *tview.Modal {
+		t.Helper()
+		cb, err := executor.ExecuteCallback(expr)
+		if err != nil {
+			t.Fatalf("ExecuteCallback: %v", err)
+		}
+		cb()
+		_, front := ctx.Pages.GetFrontPage()
+		modal, ok := front.(*tview.Modal)
+		if !ok {
+			t.Fatalf("front page is %T, want *tview.Modal", front)
+		}
+		var focused tview.Primitive
+		var focus func(p tview.Primitive)
+		focus = func(p tview.Primitive) {
+			if focused != nil {
+				focused.Blur()
+			}
+			focused = p
+			p.Focus(focus)
+		}
+		focus(modal)
+		for _, key := range keys {
+			modal.InputHandler()(tcell.NewEventKey(key, 0, tcell.ModNone), focus)
+		}
+		return modal
+	}
+
+	quit(`{{ quitWithConfirm }}`, tcell.KeyTab, tcell.KeyEnter)
+	if stops != 0 {
+		t.Fatalf("Cancel stopped the app")
+	}
+	if name, _ := ctx.Pages.GetFrontPage(); name != "main" {
+		t.Errorf("front page after Cancel = %q, want main", name)
+	}
+
+	quit(`{{ quitWithConfirm "Quit without saving?" }}`, tcell.KeyEnter)
+	if stops != 1 {
+		t.Errorf("OK called the stop func %d times, want 1", stops)
+	}
+
+	if _, err := executor.ExecuteCallback(`{{ quitWithConfirm "a" "b" }}`); err == nil {
+		t.Error("quitWithConfirm with two arguments should fail validation")
+	}
+}
+
+func TestBuiltin_ConfirmChecksAction(t *testing.T) {
+	ctx := newTestContext()
+	registry := NewFunctionRegistry()
+	zero := 0
+	registry.Register("deleteItem", 0, &zero, nil, func(ctx *Context) {})
+	executor := NewExecutor(ctx, registry)
+	ctx.SetExecutor(executor)
+
+	if _, err := executor.ExecuteCallback(`{{ confirm "Delete?" "{{ deleteItem }}" }}`); err != nil {
+		t.Errorf("confirm with a valid action: %v", err)
+	}
+	_, err := executor.ExecuteCallback(`{{ confirm "Delete?" "{{ deleteItm }}" }}`)
+	if err == nil || !strings.Contains(err.Error(), "unknown function: deleteItm") {
+		t.Errorf("confirm with an unknown action: error = %v, want unknown function", err)
+	}
+	_, err = executor.ExecuteCallback(`{{ confirm "Delete?" "{{ deleteItem \"now\" }}" }}`)
+	if err == nil || !strings.Contains(err.Error(), "at most 0 argument(s)") {
+		t.Errorf("confirm with a bad argument count: error = %v, want argument count error", err)
+	}
+}
+
+func TestRunCallback_LogsErrors(t *testing.T) {
+	ctx := newTestContext()
+	ctx.SetExecutor(NewExecutor(ctx, NewFunctionRegistry()))
+	var logged []string
+	ctx.SetLogger(func(format string, args ...interface{}) {
+		logged = append(logged, fmt.Sprintf(format, args...))
+	})
+	ctx.RunCallback(`{{ deleteItm }}`)
+	if len(logged) != 1 || !strings.Contains(logged[0], "unknown function: deleteItm") {
+		t.Errorf("logged = %q, want the unknown function error", logged)
+	}
+}
+
+func TestBuiltin_Async(t *testing.T) {
+	ctx := newTestContext()
+	registry := NewFunctionRegistry()
+	release := make(chan struct{})
+	ran := make(chan struct{})
+	zero := 0
+	registry.Register("slowFetch", 0, &zero, nil, func(ctx *Context) {
+		<-release // would deadlock below if async ran the action on the calling goroutine
+		ctx.SetState("result", "fetched")
+		close(ran)
+	})
+	executor := NewExecutor(ctx, registry)
+	ctx.SetExecutor(executor)
+
+	cb, err := executor.ExecuteCallback(`{{ async "{{ slowFetch }}" "loading" }}`)
+	if err != nil {
+		t.Fatalf("ExecuteCallback: %v", err)
+	}
+	cb()
+	if v, _ := ctx.GetState("loading"); v != true {
+		t.Errorf("loading = %v while the action runs, want true", v)
+	}
+	close(release)
+	select {
+	case <-ran:
+	case <-time.After(time.Second):
+		t.Fatal("async action did not run")
+	}
+	if !waitFor(func() bool { v, _ := ctx.GetState("loading"); return v == false }) {
+		t.Error("loading was not reset to false after the action returned")
+	}
+	if v, _ := ctx.GetState("result"); v != "fetched" {
+		t.Errorf("result = %v, want fetched", v)
+	}
+
+	if _, err := executor.ExecuteCallback(`{{ async "{{ slowFetch }}" "loading" "extra" }}`); err == nil {
+		t.Error("async with three arguments should fail validation")
+	}
+	_, err = executor.ExecuteCallback(`{{ async "{{ slowFetsh }}" "loading" }}`)
+	if err == nil || !strings.Contains(err.Error(), "unknown function: slowFetsh") {
+		t.Errorf("async with an unknown action: error = %v, want unknown function", err)
+	}
+}
+
+func TestContextGo_NotStartedAfterDone(t *testing.T) {
+	ctx := newTestContext()
+	done := make(chan struct{})
+	ctx.SetDone(done)
+	close(done)
+	started := make(chan struct{}, 1)
+	ctx.Go(func() { started <- struct{}{} })
+	select {
+	case <-started:
+		t.Error("Go started a goroutine after the application stopped")
+	case <-time.After(20 * time.Millisecond):
+	}
+}
+
+func TestEvaluator_FormatState(t *testing.T) {
+	ctx := newTestContext()
+	executor := NewExecutor(ctx, NewFunctionRegistry())
+	ctx.SetStateDirect("count", 7)
+	ctx.SetStateDirect("ratio", 3.14159)
+
+	tests := []struct {
+		template string
+		want     string
+	}{
+		{`Item {{ formatState count "%03d" }}`, "Item 007"},
+		{`{{ formatState "ratio" "%.2f" }}`, "3.14"},
+		{`{{ formatState ratio "%6.1f%%" }}`, "   3.1%"},
+		{`[{{ formatState missing "%d" }}]`, "[]"},
+	}
+	for _, tt := range tests {
+		got, err := executor.EvaluateToString(tt.template)
+		if err != nil {
+			t.Errorf("EvaluateToString(%q) error = %v", tt.template, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("EvaluateToString(%q) = %q, want %q", tt.template, got, tt.want)
+		}
+	}
+
+	keys := executor.ExtractBindStateKeys(`{{ formatState count "%03d" }} of {{ bindState total }}`)
+	if strings.Join(keys, ",") != "count,total" {
+		t.Errorf("ExtractBindStateKeys() = %v, want formatState keys to refresh too", keys)
+	}
+}
+
+func TestEvaluator_CurrentPage(t *testing.T) {
+	ctx := newTestContext()
+	ctx.Pages.AddPage("main", tview.NewBox(), true, true)
+	ctx.Pages.AddPage("settings", tview.NewBox(), true, false)
+	executor := NewExecutor(ctx, NewFunctionRegistry())
+
+	crumb := func() string {
+		t.Helper()
+		got, err := executor.EvaluateToString(`Home > {{ currentPage }}`)
+		if err != nil {
+			t.Fatalf("EvaluateToString: %v", err)
+		}
+		return got
+	}
+	if got := crumb(); got != "Home > main" {
+		t.Errorf("initially = %q, want %q", got, "Home > main")
+	}
+
+	ctx.SwitchToPage("settings")
+	if got := crumb(); got != "Home > settings" {
+		t.Errorf("after SwitchToPage = %q, want %q", got, "Home > settings")
+	}
+	if v, _ := ctx.GetState("__currentPage"); v != "settings" {
+		t.Errorf("__currentPage = %v, want settings", v)
+	}
+	if keys := executor.ExtractBindStateKeys(`{{ currentPage }}`); strings.Join(keys, ",") != "__currentPage" {
+		t.Errorf("ExtractBindStateKeys() = %v, want [__currentPage] so views refresh on navigation", keys)
+	}
+}
+
+func TestKeyHelpText(t *testing.T) {
+	bindings := []config.KeyBinding{
+		{Key: "?", Action: `{{ showKeyHelp }}`, Description: "Show this help"},
+		{Key: "Ctrl+Q", Action: `{{ stopApp }}`},
+	}
+	want := "?       Show this help\nCtrl+Q  {{ stopApp }}"
+	if got := keyHelpText(bindings); got != want {
+		t.Errorf("keyHelpText() = %q, want %q", got, want)
+	}
+	if got := keyHelpText(nil); got != "No key bindings" {
+		t.Errorf("keyHelpText(nil) = %q", got)
+	}
+}
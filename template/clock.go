@@ -0,0 +1,20 @@
+package template
+
+import "time"
+
+// Clock supplies the current time to template functions. Inject a fixed clock
+// (e.g. via AppBuilder.WithClock) to make time-based UIs deterministic in tests.
+type Clock interface {
+	Now() time.Time
+}
+
+// ClockFunc adapts an ordinary function to the Clock interface.
+type ClockFunc func() time.Time
+
+// Now returns f().
+func (f ClockFunc) Now() time.Time {
+	return f()
+}
+
+// WallClock returns the system time.
+var WallClock Clock = ClockFunc(time.Now)
@@ -1,8 +1,12 @@
 package template
 
 import (
+	"fmt"
 	"sync"
+	"time"
 
+	"github.com/cassdeckard/tviewyaml/config"
+	"github.com/cassdeckard/tviewyaml/keys"
 	"github.com/gdamore/tcell/v2"
 	"github.com/rivo/tview"
 )
@@ -23,15 +27,38 @@ type Context struct {
 	subscribers         map[string][]func(interface{})
 	boundViews          map[string][]BoundView // key -> views to refresh when key changes
 	dirtyKeys           map[string]bool
-	formSubmitCallbacks map[string]func() // form name -> callback (e.g. onSubmit)
-	formCancelCallbacks map[string]func() // form name -> callback (e.g. onCancel)
-	executor            *Executor         // set by app builder so RunCallback can execute templates
+	dirtyNotify         chan struct{}                            // receives a value when a key becomes dirty (buffered; coalesces bursts)
+	frameInterval       time.Duration                            // minimum time between refresh draws in RunRefreshLoop
+	queueDraw           func(func())                             // schedules a refresh on the main goroutine; App.QueueUpdateDraw by default
+	formSubmitCallbacks map[string]func()                        // form name -> callback (e.g. onSubmit)
+	formCancelCallbacks map[string]func()                        // form name -> callback (e.g. onCancel)
+	formInitialValues   map[string]formSnapshot                  // form name -> its fields' initial values (for ResetForm)
+	primitives          map[string]tview.Primitive               // primitive name -> built primitive (for builtins that target views by name)
+	listAppenders       map[*tview.List]ListAppender             // lists that keep their own items (filterable lists); used by AppendListItem
+	cancelableForms     []*tview.Form                            // forms with a cancel func; Escape passes through while one has focus
+	keyBindings         []config.KeyBinding                      // global key bindings from app config (for showKeyHelp)
+	theme               *config.Theme                            // app theme; default colors the builder applies to every primitive
+	screen              tcell.Screen                             // screen the app last drew to (for builtins that write to the terminal)
+	focusGroups         map[string][]string                      // group name -> primitive ids cycled by focusNext/focusPrev
+	focusIndex          map[string]int                           // group name -> index of the member CycleFocus focused last
+	pendingUpdates      []func()                                 // updates queued by QueueUpdateDraw, run in order on the main goroutine
+	history             []string                                 // previous front pages, most recent last (for GoBack)
+	pageLifecycle       map[string]pageLifecycle                 // page name -> onShow/onHide expressions
+	stop                func()                                   // stops the application (set by the app builder); App.Stop if nil
+	done                <-chan struct{}                          // closed when the application stops (see Done); nil never closes
+	executor            *Executor                                // set by app builder so RunCallback can execute templates
+	clock               Clock                                    // time source for Now(); WallClock unless set via SetClock
+	logf                func(format string, args ...interface{}) // optional logger (e.g. for recovered panics); nil discards
+	noRecover           bool                                     // let handler panics propagate instead of recovering them
 	mu                  sync.RWMutex
 }
 
+// DefaultFrameInterval is the minimum time between two refresh draws (about 60 per second).
+const DefaultFrameInterval = 16 * time.Millisecond
+
 // NewContext creates a new template context
 func NewContext(app *tview.Application, pages *tview.Pages) *Context {
-	return &Context{
+	c := &Context{
 		App:                 app,
 		Pages:               pages,
 		Colors:              &ColorHelper{},
@@ -39,20 +66,29 @@ func NewContext(app *tview.Application, pages *tview.Pages) *Context {
 		subscribers:         make(map[string][]func(interface{})),
 		boundViews:          make(map[string][]BoundView),
 		dirtyKeys:           make(map[string]bool),
+		dirtyNotify:         make(chan struct{}, 1),
+		frameInterval:       DefaultFrameInterval,
 		formSubmitCallbacks: make(map[string]func()),
 		formCancelCallbacks: make(map[string]func()),
+		formInitialValues:   make(map[string]formSnapshot),
+		primitives:          make(map[string]tview.Primitive),
+		listAppenders:       make(map[*tview.List]ListAppender),
+		pageLifecycle:       make(map[string]pageLifecycle),
+		clock:               WallClock,
+	}
+	c.queueDraw = func(fn func()) {
+		if c.App != nil {
+			c.App.QueueUpdateDraw(fn)
+		}
 	}
+	return c
 }
 
 // SetState updates the view model state and notifies subscribers.
-// Safe to call from goroutines; notifications run on main via App.QueueUpdateDraw.
+// Safe to call from goroutines; bound views and subscribers run on main from RunRefreshLoop,
+// which coalesces rapid changes into at most one draw per frame interval.
 func (c *Context) SetState(key string, value interface{}) {
-	if c.App == nil {
-		return
-	}
-	c.App.QueueUpdateDraw(func() {
-		c.setStateInternal(key, value)
-	})
+	c.setStateInternal(key, value)
 }
 
 // SetStateDirect updates state and marks the key dirty. Bound views are refreshed
@@ -62,6 +98,96 @@ func (c *Context) SetStateDirect(key string, value interface{}) {
 	c.state[key] = value
 	c.dirtyKeys[key] = true
 	c.mu.Unlock()
+	c.notifyDirty()
+}
+
+// DirtyNotify returns a channel that receives a value after a state key is marked dirty, so a
+// refresh loop can sleep until there is work instead of polling HasDirtyKeys. Several changes in
+// quick succession may produce a single notification; check HasDirtyKeys after waking.
+func (c *Context) DirtyNotify() <-chan struct{} {
+	return c.dirtyNotify
+}
+
+// notifyDirty signals DirtyNotify without blocking; a pending signal already covers this change.
+func (c *Context) notifyDirty() {
+	select {
+	case c.dirtyNotify <- struct{}{}:
+	default:
+	}
+}
+
+// SetFrameInterval sets the minimum time between refresh draws in RunRefreshLoop.
+// Zero or negative restores DefaultFrameInterval.
+func (c *Context) SetFrameInterval(d time.Duration) {
+	if d <= 0 {
+		d = DefaultFrameInterval
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.frameInterval = d
+}
+
+// RunRefreshLoop refreshes dirty bound views until stop is closed. It sleeps until a key becomes
+// dirty, queues one RefreshDirtyBoundViews draw on the main goroutine, then waits out the frame
+// interval so changes made in the meantime share the next draw. Run it in its own goroutine.
+func (c *Context) RunRefreshLoop(stop <-chan struct{}) {
+	for {
+		select {
+		case <-stop:
+			return
+		case <-c.dirtyNotify:
+		}
+		if !c.HasDirtyKeys() {
+			continue
+		}
+		c.queueDraw(c.RefreshDirtyBoundViews)
+
+		c.mu.RLock()
+		interval := c.frameInterval
+		c.mu.RUnlock()
+		timer := time.NewTimer(interval)
+		select {
+		case <-stop:
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+	}
+}
+
+// QueueUpdateDraw queues fn to run on the main goroutine followed by a redraw, without blocking
+// the caller. Unlike App.QueueUpdateDraw it is safe to call from event handlers (which already run
+// on main), and queued functions run in the order they were queued. Runs fn directly if App is nil,
+// and drops it once the application has stopped (Done is closed), as nothing would run it.
+func (c *Context) QueueUpdateDraw(fn func()) {
+	if c.App == nil {
+		fn()
+		return
+	}
+	select {
+	case <-c.Done():
+		return
+	default:
+	}
+	c.mu.Lock()
+	c.pendingUpdates = append(c.pendingUpdates, fn)
+	// Only the first pending update starts a goroutine; it runs everything queued until main picks it up.
+	first := len(c.pendingUpdates) == 1
+	c.mu.Unlock()
+	if first {
+		go c.App.QueueUpdateDraw(c.runPendingUpdates)
+	}
+}
+
+// runPendingUpdates runs all queued updates in order. Must be run on the main goroutine.
+func (c *Context) runPendingUpdates() {
+	c.mu.Lock()
+	updates := c.pendingUpdates
+	c.pendingUpdates = nil
+	c.mu.Unlock()
+	for _, fn := range updates {
+		fn()
+	}
 }
 
 func (c *Context) setStateInternal(key string, value interface{}) {
@@ -69,6 +195,7 @@ func (c *Context) setStateInternal(key string, value interface{}) {
 	c.state[key] = value
 	c.dirtyKeys[key] = true
 	c.mu.Unlock()
+	c.notifyDirty()
 }
 
 // RegisterBoundView registers a view that displays state for key. It will be
@@ -178,6 +305,435 @@ func (c *Context) RunFormCancel(name string) {
 	}
 }
 
+// formSnapshot is a form and the values its fields had when it was recorded, by item index: the
+// text of an InputField or TextArea, whether a Checkbox is checked, or the option index of a
+// DropDown (nil for other items).
+type formSnapshot struct {
+	form   *tview.Form
+	values []interface{}
+}
+
+// RecordFormInitialValues records the current values of the fields of the form named name, so
+// ResetForm can restore them. The builder calls it once a form's items are added, which makes the
+// configured values (value, checked, the first dropdown option) the ones restored.
+func (c *Context) RecordFormInitialValues(name string, form *tview.Form) {
+	if name == "" || form == nil {
+		return
+	}
+	snapshot := formSnapshot{form: form, values: make([]interface{}, form.GetFormItemCount())}
+	for i := range snapshot.values {
+		switch item := form.GetFormItem(i).(type) {
+		case *tview.InputField:
+			snapshot.values[i] = item.GetText()
+		case *tview.TextArea:
+			snapshot.values[i] = item.GetText()
+		case *tview.Checkbox:
+			snapshot.values[i] = item.IsChecked()
+		case *tview.DropDown:
+			option, _ := item.GetCurrentOption()
+			snapshot.values[i] = option
+		}
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.formInitialValues[name] = snapshot
+}
+
+// ResetForm restores the fields of the form named name to the values recorded with
+// RecordFormInitialValues and reports whether the form is known. Fields' changed handlers run as
+// for any edit, so state bound to them follows. Must run on the main goroutine (e.g. from
+// QueueUpdateDraw).
+func (c *Context) ResetForm(name string) bool {
+	c.mu.RLock()
+	snapshot, ok := c.formInitialValues[name]
+	c.mu.RUnlock()
+	if !ok {
+		return false
+	}
+	for i, value := range snapshot.values {
+		if i >= snapshot.form.GetFormItemCount() {
+			break
+		}
+		switch item := snapshot.form.GetFormItem(i).(type) {
+		case *tview.InputField:
+			item.SetText(value.(string))
+		case *tview.TextArea:
+			item.SetText(value.(string), false)
+		case *tview.Checkbox:
+			item.SetChecked(value.(bool))
+		case *tview.DropDown:
+			item.SetCurrentOption(value.(int))
+		}
+	}
+	return true
+}
+
+// RegisterCancelableForm records a form that handles Escape itself (its cancel func is set),
+// so the global Escape binding can pass Escape through while the form has focus.
+func (c *Context) RegisterCancelableForm(form *tview.Form) {
+	if form == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.cancelableForms = append(c.cancelableForms, form)
+}
+
+// CancelableFormHasFocus reports whether any registered cancelable form (or one of its items) has focus.
+// Must be called on the main goroutine.
+func (c *Context) CancelableFormHasFocus() bool {
+	c.mu.RLock()
+	forms := c.cancelableForms
+	c.mu.RUnlock()
+	for _, form := range forms {
+		if form.HasFocus() {
+			return true
+		}
+	}
+	return false
+}
+
+// SetKeyBindings stores the app's global key bindings so template functions (e.g. showKeyHelp) can list them.
+// Called by the app builder after loading the app config.
+func (c *Context) SetKeyBindings(bindings []config.KeyBinding) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.keyBindings = append([]config.KeyBinding(nil), bindings...)
+}
+
+// KeyBindings returns a copy of the global key bindings set with SetKeyBindings.
+func (c *Context) KeyBindings() []config.KeyBinding {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return append([]config.KeyBinding(nil), c.keyBindings...)
+}
+
+// SetTheme stores the app's theme so the builder can apply its colors as defaults.
+// Called by the app builder after loading the app config; nil means no theme.
+func (c *Context) SetTheme(theme *config.Theme) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.theme = theme
+}
+
+// Theme returns the theme set with SetTheme, or nil if there is none.
+func (c *Context) Theme() *config.Theme {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.theme
+}
+
+// SetFocusGroups stores the app's focus groups (group name -> primitive ids) for CycleFocus and
+// forgets each group's position. Called by the app builder after loading the app config.
+func (c *Context) SetFocusGroups(groups map[string][]string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.focusGroups = groups
+	c.focusIndex = make(map[string]int)
+}
+
+// CycleFocus moves focus step members forward (or backward, if negative) through the named focus
+// group, wrapping at either end, and reports whether the group exists. Each group's position is
+// tracked here: when one of its primitives (or a primitive inside it) has focus, focus moves on
+// from that member, so clicking a panel is respected; otherwise it moves on from the member the
+// group focused last, and the first call focuses the first member (or the last, for a negative
+// step). Ids that are not registered primitives are skipped. Must run on the main goroutine (e.g.
+// from QueueUpdateDraw).
+func (c *Context) CycleFocus(group string, step int) bool {
+	c.mu.RLock()
+	ids, ok := c.focusGroups[group]
+	index, tracked := c.focusIndex[group]
+	c.mu.RUnlock()
+	if !ok || len(ids) == 0 {
+		return false
+	}
+	members := make([]tview.Primitive, len(ids))
+	for i, id := range ids {
+		members[i], _ = c.GetPrimitive(id)
+	}
+	if !tracked {
+		index = -1
+		if step < 0 {
+			index = len(ids)
+		}
+	}
+	for i, p := range members {
+		if p != nil && p.HasFocus() {
+			index = i
+			break
+		}
+	}
+	for range members {
+		index = ((index+step)%len(ids) + len(ids)) % len(ids)
+		if members[index] != nil {
+			break
+		}
+	}
+	if members[index] == nil {
+		return true
+	}
+	c.mu.Lock()
+	c.focusIndex[group] = index
+	c.mu.Unlock()
+	if c.App != nil {
+		c.App.SetFocus(members[index])
+	}
+	return true
+}
+
+// SetScreen stores the screen the application draws to. Called by the app builder before each draw.
+func (c *Context) SetScreen(screen tcell.Screen) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.screen = screen
+}
+
+// Screen returns the screen set with SetScreen, or nil before the first draw.
+func (c *Context) Screen() tcell.Screen {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.screen
+}
+
+// RegisterPrimitive registers a built primitive by name so template functions can target it (e.g. appendText).
+// Later registrations with the same name replace earlier ones.
+func (c *Context) RegisterPrimitive(name string, p tview.Primitive) {
+	if name == "" || p == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.primitives[name] = p
+}
+
+// GetPrimitive returns the primitive registered under name.
+func (c *Context) GetPrimitive(name string) (tview.Primitive, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	p, ok := c.primitives[name]
+	return p, ok
+}
+
+// SetStopFunc sets the function Stop calls to shut the application down. The app builder sets it so
+// builtins also stop background goroutines; tests can set a fake.
+func (c *Context) SetStopFunc(stop func()) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.stop = stop
+}
+
+// Stop shuts the application down via the stop func, or App.Stop when none is set.
+func (c *Context) Stop() {
+	c.mu.RLock()
+	stop := c.stop
+	c.mu.RUnlock()
+	if stop != nil {
+		stop()
+	} else if c.App != nil {
+		c.App.Stop()
+	}
+}
+
+// SetDone sets the channel Done returns. The app builder passes a channel that Application.Stop closes.
+func (c *Context) SetDone(done <-chan struct{}) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.done = done
+}
+
+// Done returns a channel that is closed when the application stops. Long-running work started
+// from a template function (e.g. with Go) should select on it and return early. Returns nil
+// (never closed) when no app builder set it.
+func (c *Context) Done() <-chan struct{} {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.done
+}
+
+// Go runs fn on a new goroutine so slow work (network calls, file IO) does not block the UI.
+// fn must not touch primitives directly: use SetState (safe from any goroutine) or QueueUpdateDraw.
+// Nothing is started once the application has stopped; fn is not interrupted by Stop, so it should
+// watch Done if it can run for long.
+func (c *Context) Go(fn func()) {
+	select {
+	case <-c.Done():
+		return
+	default:
+	}
+	go fn()
+}
+
+// pageLifecycle holds a page's onShow and onHide template expressions.
+type pageLifecycle struct {
+	onShow, onHide string
+}
+
+// RegisterPageLifecycle sets the template expressions run when navigation shows or hides the named
+// page (the page's onShow and onHide). Empty expressions are skipped. Called by the app builder.
+func (c *Context) RegisterPageLifecycle(name, onShow, onHide string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if onShow == "" && onHide == "" {
+		delete(c.pageLifecycle, name)
+		return
+	}
+	c.pageLifecycle[name] = pageLifecycle{onShow: onShow, onHide: onHide}
+}
+
+// RunPageShow records name as state __currentPage and runs the page's onShow expression, if any.
+// The app builder calls it for the initial page; navigation through the Context methods calls it
+// for every page it brings forward.
+func (c *Context) RunPageShow(name string) {
+	c.SetStateDirect("__currentPage", name)
+	c.mu.RLock()
+	expr := c.pageLifecycle[name].onShow
+	c.mu.RUnlock()
+	if expr != "" {
+		c.RunCallback(expr)
+	}
+}
+
+// runPageHide runs the named page's onHide expression, if any.
+func (c *Context) runPageHide(name string) {
+	c.mu.RLock()
+	expr := c.pageLifecycle[name].onHide
+	c.mu.RUnlock()
+	if expr != "" {
+		c.RunCallback(expr)
+	}
+}
+
+// navigate runs change (which alters the visible pages) and, if the front page changed, runs the
+// old front page's onHide before the change and the new one's onShow after it.
+func (c *Context) navigate(change func()) {
+	before, _ := c.Pages.GetFrontPage()
+	change()
+	after, _ := c.Pages.GetFrontPage()
+	if after == before {
+		return
+	}
+	if before != "" {
+		c.runPageHide(before)
+	}
+	if after != "" {
+		c.RunPageShow(after)
+	}
+}
+
+// SwitchToPage shows the named page and records the current front page in the navigation
+// history so GoBack can return to it. Navigation builtins use this instead of Pages.SwitchToPage,
+// so the pages' onHide and onShow run. A page is in the history at most once: recording it again
+// drops its older entry, so going back and forth between pages does not grow the history.
+func (c *Context) SwitchToPage(name string) {
+	if c.Pages == nil {
+		return
+	}
+	if front, _ := c.Pages.GetFrontPage(); front != "" && front != name {
+		c.mu.Lock()
+		c.dropFromHistory(front)
+		c.history = append(c.history, front)
+		c.mu.Unlock()
+	}
+	c.navigate(func() { c.Pages.SwitchToPage(name) })
+}
+
+// RemovePage removes the named page, running onHide/onShow if that changes the front page.
+// The page is also dropped from the navigation history.
+func (c *Context) RemovePage(name string) {
+	if c.Pages == nil {
+		return
+	}
+	c.mu.Lock()
+	c.dropFromHistory(name)
+	c.mu.Unlock()
+	c.navigate(func() { c.Pages.RemovePage(name) })
+}
+
+// dropFromHistory removes every history entry for the named page. Caller must hold c.mu.
+func (c *Context) dropFromHistory(name string) {
+	kept := c.history[:0]
+	for _, n := range c.history {
+		if n != name {
+			kept = append(kept, n)
+		}
+	}
+	c.history = kept
+}
+
+// GoBack switches to the most recent page in the navigation history, skipping pages that have
+// since been removed. Returns false (and does nothing) when there is no page to go back to.
+func (c *Context) GoBack() bool {
+	if c.Pages == nil {
+		return false
+	}
+	for {
+		c.mu.Lock()
+		if len(c.history) == 0 {
+			c.mu.Unlock()
+			return false
+		}
+		name := c.history[len(c.history)-1]
+		c.history = c.history[:len(c.history)-1]
+		c.mu.Unlock()
+		if c.Pages.HasPage(name) {
+			c.navigate(func() { c.Pages.SwitchToPage(name) })
+			return true
+		}
+	}
+}
+
+// SetClock sets the time source returned by Now. A nil clock restores WallClock.
+func (c *Context) SetClock(clock Clock) {
+	if clock == nil {
+		clock = WallClock
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.clock = clock
+}
+
+// Now returns the current time from the context's clock. Template functions should use this
+// instead of time.Now so tests can inject a fixed clock.
+func (c *Context) Now() time.Time {
+	c.mu.RLock()
+	clock := c.clock
+	c.mu.RUnlock()
+	return clock.Now()
+}
+
+// SetLogger sets the function Logf writes to (e.g. log.Printf). A nil logger discards messages.
+func (c *Context) SetLogger(logf func(format string, args ...interface{})) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.logf = logf
+}
+
+// Logf writes a message to the logger set with SetLogger, if any.
+func (c *Context) Logf(format string, args ...interface{}) {
+	c.mu.RLock()
+	logf := c.logf
+	c.mu.RUnlock()
+	if logf != nil {
+		logf(format, args...)
+	}
+}
+
+// SetRecoverPanics sets whether callbacks recover from a panicking handler (the default).
+// A recovered panic is logged via Logf and shown in an error modal, and the app keeps running.
+// Disable it to let panics propagate, e.g. to get a stack trace while debugging a custom function.
+func (c *Context) SetRecoverPanics(recoverPanics bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.noRecover = !recoverPanics
+}
+
+// RecoverPanics reports whether callbacks recover from a panicking handler.
+func (c *Context) RecoverPanics() bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return !c.noRecover
+}
+
 // SetExecutor sets the template executor so RunCallback can execute template expressions (e.g. from modal onDone).
 // Called by the app builder after creating the executor.
 func (c *Context) SetExecutor(e *Executor) {
@@ -187,7 +743,7 @@ func (c *Context) SetExecutor(e *Executor) {
 }
 
 // RunCallback executes a template expression (e.g. "switchToPage \"main\"") and runs the resulting callback.
-// No-op if executor is not set or execution fails.
+// No-op if executor is not set; if execution fails, the error is written to Logf.
 func (c *Context) RunCallback(templateStr string) {
 	c.mu.RLock()
 	e := c.executor
@@ -196,9 +752,89 @@ func (c *Context) RunCallback(templateStr string) {
 		return
 	}
 	cb, err := e.ExecuteCallback(templateStr)
-	if err == nil {
-		cb()
+	if err != nil {
+		c.Logf("callback %q: %v", templateStr, err)
+		return
+	}
+	cb()
+}
+
+// CheckCallback checks a template expression without running it (see Executor.CheckCallback).
+// Returns nil if executor is not set.
+func (c *Context) CheckCallback(templateStr string) error {
+	c.mu.RLock()
+	e := c.executor
+	c.mu.RUnlock()
+	if e == nil {
+		return nil
+	}
+	return e.CheckCallback(templateStr)
+}
+
+// AppendListItem adds an item to the List registered under name (see RegisterPrimitive), e.g. to show
+// search results computed in Go. shortcut is a single key ("a", "ñ") or empty; action is a template
+// expression run when the item is selected, or empty for none. Safe to call from goroutines: the item is
+// added on the main goroutine. Returns an error if the list, shortcut, or action is invalid.
+func (c *Context) AppendListItem(name, mainText, secondaryText, shortcut, action string) error {
+	p, ok := c.GetPrimitive(name)
+	if !ok {
+		return fmt.Errorf("no primitive named %q", name)
 	}
+	list, ok := p.(*tview.List)
+	if !ok {
+		return fmt.Errorf("primitive %q is %T, not a list", name, p)
+	}
+
+	var shortcutRune rune
+	if shortcut != "" {
+		key, mod, ch, err := keys.ParseKey(shortcut)
+		if err != nil {
+			return fmt.Errorf("invalid shortcut %q: %w", shortcut, err)
+		}
+		if key != tcell.KeyRune || mod != 0 {
+			return fmt.Errorf("invalid shortcut %q: must be a single unmodified key", shortcut)
+		}
+		shortcutRune = ch
+	}
+
+	var callback func()
+	if action != "" {
+		c.mu.RLock()
+		e := c.executor
+		c.mu.RUnlock()
+		if e == nil {
+			return fmt.Errorf("cannot parse action for list item %q: no executor", mainText)
+		}
+		cb, err := e.ExecuteCallback(action)
+		if err != nil {
+			return fmt.Errorf("invalid action for list item %q: %w", mainText, err)
+		}
+		callback = cb
+	}
+
+	c.mu.RLock()
+	appender := c.listAppenders[list]
+	c.mu.RUnlock()
+	c.QueueUpdateDraw(func() {
+		if appender != nil {
+			appender(mainText, secondaryText, shortcutRune, callback)
+			return
+		}
+		list.AddItem(mainText, secondaryText, shortcutRune, callback)
+	})
+	return nil
+}
+
+// ListAppender adds an item to a list that keeps its own copy of its items. It runs on the main goroutine.
+type ListAppender func(mainText, secondaryText string, shortcut rune, selected func())
+
+// SetListAppender makes AppendListItem add items to list through add instead of List.AddItem. The
+// builder sets it for filterable lists, which repopulate the list from their own entries whenever
+// the query changes, so items added straight to the list would disappear.
+func (c *Context) SetListAppender(list *tview.List, add ListAppender) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.listAppenders[list] = add
 }
 
 // ColorHelper provides color parsing utilities
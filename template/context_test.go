@@ -1,8 +1,13 @@
 package template
 
 import (
+	"runtime"
 	"sync"
 	"testing"
+	"time"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
 )
 
 // TestContextConcurrency verifies that concurrent reads and writes to context state
@@ -211,3 +216,311 @@ func TestContextConcurrentExecutor(t *testing.T) {
 
 	wg.Wait()
 }
+
+func TestContextClock(t *testing.T) {
+	ctx := newTestContext()
+	if ctx.Now().IsZero() {
+		t.Fatal("default clock should return wall time")
+	}
+
+	fixed := time.Date(2024, 1, 2, 15, 4, 5, 0, time.UTC)
+	ctx.SetClock(ClockFunc(func() time.Time { return fixed }))
+	if got := ctx.Now(); !got.Equal(fixed) {
+		t.Errorf("Now() = %v, want %v", got, fixed)
+	}
+
+	ctx.SetClock(nil)
+	if got := ctx.Now(); got.Equal(fixed) {
+		t.Error("SetClock(nil) should restore the wall clock")
+	}
+}
+
+func TestContextAppendListItem(t *testing.T) {
+	list := tview.NewList()
+	ctx := newRunningTestContext(t, list)
+	ctx.RegisterPrimitive("results", list)
+	ctx.SetExecutor(NewExecutor(ctx, NewFunctionRegistry()))
+
+	if err := ctx.AppendListItem("results", "First", "one", "a", `{{ showNotification "first" }}`); err != nil {
+		t.Fatalf("AppendListItem: %v", err)
+	}
+	if err := ctx.AppendListItem("results", "Second", "", "ñ", ""); err != nil {
+		t.Fatalf("AppendListItem: %v", err)
+	}
+
+	itemCount := func() int {
+		var n int
+		ctx.App.QueueUpdate(func() { n = list.GetItemCount() })
+		return n
+	}
+	if !waitFor(func() bool { return itemCount() == 2 }) {
+		t.Fatalf("GetItemCount() = %d, want 2", itemCount())
+	}
+	var main, secondary string
+	ctx.App.QueueUpdate(func() { main, secondary = list.GetItemText(0) })
+	if main != "First" || secondary != "one" {
+		t.Errorf("GetItemText(0) = %q, %q; want %q, %q", main, secondary, "First", "one")
+	}
+}
+
+func TestContextAppendListItemErrors(t *testing.T) {
+	ctx := newTestContext()
+	ctx.RegisterPrimitive("results", tview.NewList())
+	ctx.RegisterPrimitive("log", tview.NewTextView())
+	ctx.SetExecutor(NewExecutor(ctx, NewFunctionRegistry()))
+
+	tests := []struct {
+		name     string
+		list     string
+		shortcut string
+		action   string
+	}{
+		{"unknown primitive", "missing", "", ""},
+		{"not a list", "log", "", ""},
+		{"modified shortcut", "results", "Ctrl+A", ""},
+		{"invalid shortcut", "results", "ab", ""},
+		{"unknown function", "results", "", `{{ noSuchFunction }}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := ctx.AppendListItem(tt.list, "Item", "", tt.shortcut, tt.action); err == nil {
+				t.Error("AppendListItem() error = nil, want error")
+			}
+		})
+	}
+}
+
+func TestContextDirtyNotify(t *testing.T) {
+	ctx := newTestContext()
+	select {
+	case <-ctx.DirtyNotify():
+		t.Fatal("no notification expected before any state change")
+	default:
+	}
+
+	// A burst of changes coalesces into one pending notification.
+	ctx.SetStateDirect("a", 1)
+	ctx.SetStateDirect("b", 2)
+	select {
+	case <-ctx.DirtyNotify():
+	default:
+		t.Fatal("SetStateDirect should notify")
+	}
+	select {
+	case <-ctx.DirtyNotify():
+		t.Fatal("changes before the first wake-up should share one notification")
+	default:
+	}
+	if !ctx.HasDirtyKeys() {
+		t.Error("both keys should still be dirty after the notification")
+	}
+
+	ctx.RefreshDirtyBoundViews()
+	ctx.SetStateDirect("a", 3)
+	select {
+	case <-ctx.DirtyNotify():
+	default:
+		t.Fatal("a change after a refresh should notify again")
+	}
+}
+
+func TestContextRefreshLoopCoalescesDraws(t *testing.T) {
+	ctx := newTestContext()
+	ctx.SetFrameInterval(20 * time.Millisecond)
+	var mu sync.Mutex
+	draws := 0
+	ctx.queueDraw = func(fn func()) {
+		mu.Lock()
+		draws++
+		mu.Unlock()
+		fn()
+	}
+	var last interface{}
+	ctx.OnStateChange("progress", func(v interface{}) {
+		mu.Lock()
+		last = v
+		mu.Unlock()
+	})
+
+	stop := make(chan struct{})
+	defer close(stop)
+	go ctx.RunRefreshLoop(stop)
+
+	const sets = 200
+	start := time.Now()
+	for i := 1; i <= sets; i++ {
+		ctx.SetState("progress", i)
+		if i%10 == 0 {
+			time.Sleep(time.Millisecond)
+		}
+	}
+	elapsed := time.Since(start)
+
+	deadline := time.Now().Add(time.Second)
+	for {
+		mu.Lock()
+		done := last == sets
+		mu.Unlock()
+		if done {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("last refreshed value = %v, want %d", last, sets)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	// One draw per frame interval while setting, plus the trailing draw for the final value.
+	if limit := int(elapsed/(20*time.Millisecond)) + 2; draws > limit {
+		t.Errorf("%d SetState calls produced %d draws, want at most %d", sets, draws, limit)
+	}
+}
+
+func TestContextNavigationHistory(t *testing.T) {
+	ctx := newTestContext()
+	for _, name := range []string{"main", "list", "detail", "help"} {
+		ctx.Pages.AddPage(name, tview.NewBox(), true, name == "main")
+	}
+	front := func() string {
+		name, _ := ctx.Pages.GetFrontPage()
+		return name
+	}
+
+	ctx.SwitchToPage("list")
+	ctx.SwitchToPage("detail")
+	ctx.SwitchToPage("detail") // switching to the current page is not recorded
+	ctx.SwitchToPage("help")
+
+	// help -> detail -> list -> main, then nothing left
+	for _, want := range []string{"detail", "list", "main"} {
+		if !ctx.GoBack() {
+			t.Fatalf("GoBack() = false, want back to %q", want)
+		}
+		if got := front(); got != want {
+			t.Errorf("front page after GoBack = %q, want %q", got, want)
+		}
+	}
+	if ctx.GoBack() {
+		t.Error("GoBack() with empty history = true, want false")
+	}
+	if got := front(); got != "main" {
+		t.Errorf("front page after GoBack on empty history = %q, want main", got)
+	}
+
+	// Removed pages are skipped
+	ctx.SwitchToPage("list")
+	ctx.SwitchToPage("detail")
+	ctx.Pages.RemovePage("list")
+	ctx.GoBack()
+	if got := front(); got != "main" {
+		t.Errorf("front page after going back past a removed page = %q, want main", got)
+	}
+}
+
+func TestContextNavigationHistory_Bounded(t *testing.T) {
+	ctx := newTestContext()
+	for _, name := range []string{"main", "list", "detail"} {
+		ctx.Pages.AddPage(name, tview.NewBox(), true, name == "main")
+	}
+	front := func() string {
+		name, _ := ctx.Pages.GetFrontPage()
+		return name
+	}
+
+	// Going back and forth keeps one entry per page
+	for i := 0; i < 50; i++ {
+		ctx.SwitchToPage("list")
+		ctx.SwitchToPage("main")
+	}
+	ctx.SwitchToPage("detail")
+	ctx.mu.RLock()
+	n := len(ctx.history)
+	ctx.mu.RUnlock()
+	if n != 2 {
+		t.Errorf("history has %d entries after flipping between two pages, want 2", n)
+	}
+	for _, want := range []string{"main", "list"} {
+		if !ctx.GoBack() || front() != want {
+			t.Fatalf("front page after GoBack = %q, want %q", front(), want)
+		}
+	}
+
+	// RemovePage drops the page from the history
+	ctx.SwitchToPage("detail")
+	ctx.SwitchToPage("main")
+	ctx.RemovePage("detail")
+	ctx.mu.RLock()
+	defer ctx.mu.RUnlock()
+	for _, name := range ctx.history {
+		if name == "detail" {
+			t.Errorf("history %v still has the removed page", ctx.history)
+		}
+	}
+}
+
+func TestQueueUpdateDraw_OneGoroutineWhilePending(t *testing.T) {
+	sim := tcell.NewSimulationScreen("UTF-8")
+	if err := sim.Init(); err != nil {
+		t.Fatalf("SimulationScreen Init: %v", err)
+	}
+	app := tview.NewApplication().SetScreen(sim).SetRoot(tview.NewBox(), true)
+	ctx := NewContext(app, tview.NewPages())
+
+	// The app is not running yet, so every update stays pending
+	var mu sync.Mutex
+	var ran []int
+	before := runtime.NumGoroutine()
+	for i := 0; i < 100; i++ {
+		i := i
+		ctx.QueueUpdateDraw(func() {
+			mu.Lock()
+			ran = append(ran, i)
+			mu.Unlock()
+		})
+	}
+	if started := runtime.NumGoroutine() - before; started > 1 {
+		t.Errorf("100 pending updates started %d goroutines, want 1", started)
+	}
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		_ = app.Run()
+	}()
+	defer func() {
+		app.Stop()
+		<-done
+	}()
+	if !waitFor(func() bool { mu.Lock(); defer mu.Unlock(); return len(ran) == 100 }) {
+		t.Fatal("not all queued updates ran")
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	for i, v := range ran {
+		if v != i {
+			t.Fatalf("updates ran out of order: %v", ran)
+		}
+	}
+}
+
+func TestQueueUpdateDraw_AfterStop(t *testing.T) {
+	ctx := newTestContext()
+	done := make(chan struct{})
+	ctx.SetDone(done)
+	close(done)
+
+	before := runtime.NumGoroutine()
+	for i := 0; i < 100; i++ {
+		ctx.QueueUpdateDraw(func() { t.Error("update ran after the app stopped") })
+	}
+	if started := runtime.NumGoroutine() - before; started > 0 {
+		t.Errorf("QueueUpdateDraw started %d goroutines after the app stopped", started)
+	}
+	ctx.mu.RLock()
+	defer ctx.mu.RUnlock()
+	if n := len(ctx.pendingUpdates); n != 0 {
+		t.Errorf("%d updates left pending after the app stopped", n)
+	}
+}
@@ -5,12 +5,26 @@ import (
 	"reflect"
 	"regexp"
 	"strings"
+	"sync"
 )
 
+// callExprPattern matches "functionName rest-of-args" in a template expression.
+var callExprPattern = regexp.MustCompile(`^(\w+)\s*(.*)$`)
+
 // Executor handles template execution
 type Executor struct {
 	ctx      *Context
 	registry *FunctionRegistry
+
+	mu    sync.Mutex
+	calls map[string]*parsedCall // expression -> parsed call, filled by ExecuteCallback
+}
+
+// parsedCall is a callback expression parsed into its registered function and arguments.
+type parsedCall struct {
+	name string
+	fn   *TemplateFunction
+	args []string
 }
 
 // NewExecutor creates a new template executor
@@ -18,6 +32,7 @@ func NewExecutor(ctx *Context, registry *FunctionRegistry) *Executor {
 	return &Executor{
 		ctx:      ctx,
 		registry: registry,
+		calls:    make(map[string]*parsedCall),
 	}
 }
 
@@ -30,16 +45,27 @@ func (e *Executor) EvaluateToString(templateStr string) (string, error) {
 	return e.evaluateTemplateString(templateStr)
 }
 
-// ExtractBindStateKeys returns all state keys referenced by bindState in the template string.
-// Used to subscribe to state changes for re-evaluation.
+// stateEvaluators are the evaluators whose first argument is a state key they display.
+var stateEvaluators = map[string]bool{"bindState": true, "formatState": true}
+
+// keyedEvaluators are evaluators without a key argument that change with a fixed state key.
+var keyedEvaluators = map[string]string{"currentPage": "__currentPage"}
+
+// ExtractBindStateKeys returns all state keys referenced by bindState (or formatState) in the
+// template string, plus the keys behind evaluators such as currentPage. Used to subscribe to
+// state changes for re-evaluation.
 func (e *Executor) ExtractBindStateKeys(templateStr string) []string {
 	var keys []string
 	seen := make(map[string]bool)
 	for _, expr := range extractTemplateExpressions(templateStr) {
 		name, args := parseEvaluatorExpr(expr)
-		if name == "bindState" && len(args) > 0 && !seen[args[0]] {
-			keys = append(keys, args[0])
-			seen[args[0]] = true
+		key := keyedEvaluators[name]
+		if stateEvaluators[name] && len(args) > 0 {
+			key = args[0]
+		}
+		if key != "" && !seen[key] {
+			keys = append(keys, key)
+			seen[key] = true
 		}
 	}
 	return keys
@@ -64,8 +90,8 @@ func (e *Executor) evaluateTemplateString(s string) (string, error) {
 		if !ok {
 			return "", fmt.Errorf("unknown evaluator: %s", name)
 		}
-		if len(args) < ev.MinArgs || len(args) > ev.MaxArgs {
-			return "", fmt.Errorf("evaluator %q expects %d-%d args, got %d", name, ev.MinArgs, ev.MaxArgs, len(args))
+		if err := checkEvaluatorArgs(ev, len(args)); err != nil {
+			return "", err
 		}
 		result.WriteString(ev.Handler(e.ctx, args))
 	}
@@ -113,14 +139,14 @@ func extractTemplateExpressions(s string) []string {
 	return exprs
 }
 
-// parseEvaluatorExpr parses "funcName arg1 arg2" into name and args (supports unquoted identifiers)
+// parseEvaluatorExpr parses "funcName arg1 arg2" into name and args. Arguments may be quoted
+// strings or unquoted words, mixed freely (e.g. formatState count "%03d").
 func parseEvaluatorExpr(expr string) (string, []string) {
 	expr = strings.TrimSpace(expr)
 	if expr == "" {
 		return "", nil
 	}
-	re := regexp.MustCompile(`^(\w+)\s*(.*)$`)
-	matches := re.FindStringSubmatch(expr)
+	matches := callExprPattern.FindStringSubmatch(expr)
 	if len(matches) < 2 {
 		return "", nil
 	}
@@ -129,12 +155,36 @@ func parseEvaluatorExpr(expr string) (string, []string) {
 	if rest == "" {
 		return name, nil
 	}
-	// Try quoted args first; if none, use unquoted words
-	args := parseArguments(rest)
-	if len(args) == 0 {
-		args = strings.Fields(rest)
+	return name, splitEvaluatorArgs(rest)
+}
+
+// splitEvaluatorArgs splits evaluator arguments into quoted strings (with backslash escapes) and
+// whitespace-separated unquoted words.
+func splitEvaluatorArgs(s string) []string {
+	var args []string
+	for i := 0; i < len(s); {
+		switch {
+		case s[i] == ' ' || s[i] == '\t':
+			i++
+		case s[i] == '"':
+			var arg strings.Builder
+			for i++; i < len(s) && s[i] != '"'; i++ {
+				if s[i] == '\\' && i+1 < len(s) {
+					i++
+				}
+				arg.WriteByte(s[i])
+			}
+			i++ // closing quote
+			args = append(args, arg.String())
+		default:
+			start := i
+			for i < len(s) && s[i] != ' ' && s[i] != '\t' {
+				i++
+			}
+			args = append(args, s[start:i])
+		}
 	}
-	return name, args
+	return args
 }
 
 // ExecuteCallback parses and executes a template expression to create a callback function
@@ -157,12 +207,65 @@ func (e *Executor) ExecuteCallback(templateStr string) (func(), error) {
 	return e.parseAndCreateCallback(templateStr)
 }
 
-// parseAndCreateCallback parses the template string and creates the appropriate callback
+// CheckCallback parses a template expression as ExecuteCallback does and checks that it calls a
+// registered function with an accepted number of arguments, without creating the callback.
+// Builtins whose arguments are actions (confirm, async) use it to reject bad actions when built.
+func (e *Executor) CheckCallback(templateStr string) error {
+	templateStr = strings.TrimSpace(templateStr)
+	templateStr = strings.TrimPrefix(templateStr, "{{")
+	templateStr = strings.TrimSuffix(templateStr, "}}")
+	templateStr = strings.TrimSpace(templateStr)
+	if templateStr == "" {
+		return nil
+	}
+	_, err := e.lookupCall(templateStr)
+	return err
+}
+
+// parseAndCreateCallback parses the template string and creates the appropriate callback.
+// Parsed calls are cached per expression, so callbacks compiled repeatedly (e.g. from a
+// per-keystroke onChanged) skip the parsing; the function's validator still runs every time.
 func (e *Executor) parseAndCreateCallback(expr string) (func(), error) {
+	call, err := e.lookupCall(expr)
+	if err != nil {
+		return nil, err
+	}
+
+	// Call validator if present (only called after argument count validation)
+	if call.fn.Validator != nil {
+		if err := call.fn.Validator(e.ctx, call.args); err != nil {
+			return nil, fmt.Errorf("validation failed for function %q: %w", call.name, err)
+		}
+	}
+
+	// Create callback that invokes the handler
+	return e.createCallbackFromHandler(call.fn, call.args)
+}
+
+// lookupCall returns the cached parse of expr, parsing and caching it on first use.
+// Errors are not cached, so an expression naming a function registered later still resolves.
+func (e *Executor) lookupCall(expr string) (*parsedCall, error) {
+	e.mu.Lock()
+	call, ok := e.calls[expr]
+	e.mu.Unlock()
+	if ok {
+		return call, nil
+	}
+	call, err := e.parseCall(expr)
+	if err != nil {
+		return nil, err
+	}
+	e.mu.Lock()
+	e.calls[expr] = call
+	e.mu.Unlock()
+	return call, nil
+}
+
+// parseCall parses "functionName "arg1" "arg2" ..." and resolves the function, checking the argument count.
+func (e *Executor) parseCall(expr string) (*parsedCall, error) {
 	// Match function calls with arguments
 	// Pattern: functionName "arg1" "arg2" ...
-	re := regexp.MustCompile(`^(\w+)\s*(.*)$`)
-	matches := re.FindStringSubmatch(expr)
+	matches := callExprPattern.FindStringSubmatch(expr)
 	if len(matches) < 2 {
 		return nil, fmt.Errorf("invalid template expression: %s", expr)
 	}
@@ -180,22 +283,10 @@ func (e *Executor) parseAndCreateCallback(expr string) (func(), error) {
 	}
 
 	// Validate argument count
-	if len(args) < fn.MinArgs {
-		return nil, fmt.Errorf("function %q requires at least %d argument(s), got %d", funcName, fn.MinArgs, len(args))
-	}
-	if fn.MaxArgs != nil && len(args) > *fn.MaxArgs {
-		return nil, fmt.Errorf("function %q accepts at most %d argument(s), got %d", funcName, *fn.MaxArgs, len(args))
+	if err := checkFunctionArgs(fn, len(args)); err != nil {
+		return nil, err
 	}
-
-	// Call validator if present (only called after argument count validation)
-	if fn.Validator != nil {
-		if err := fn.Validator(e.ctx, args); err != nil {
-			return nil, fmt.Errorf("validation failed for function %q: %w", funcName, err)
-		}
-	}
-
-	// Create callback that invokes the handler
-	return e.createCallbackFromHandler(fn, args)
+	return &parsedCall{name: funcName, fn: fn, args: args}, nil
 }
 
 // parseArguments extracts string arguments from a function call
@@ -250,6 +341,10 @@ func (e *Executor) createCallbackFromHandler(fn *TemplateFunction, args []string
 	contextValue := reflect.ValueOf(e.ctx)
 
 	return func() {
+		if e.ctx.RecoverPanics() {
+			defer e.recoverHandlerPanic(fn.Name)
+		}
+
 		// Prepare arguments for the handler call
 		var callArgs []reflect.Value
 		callArgs = append(callArgs, contextValue)
@@ -260,11 +355,26 @@ func (e *Executor) createCallbackFromHandler(fn *TemplateFunction, args []string
 				callArgs = append(callArgs, reflect.ValueOf(arg))
 			}
 		} else {
-			// Variadic: pass args as a slice
-			callArgs = append(callArgs, reflect.ValueOf(args))
+			// Variadic: pass a copy of args so a handler cannot modify the cached parse
+			callArgs = append(callArgs, reflect.ValueOf(append([]string(nil), args...)))
 		}
 
 		// Call the handler
 		handlerValue.Call(callArgs)
 	}, nil
 }
+
+// recoverHandlerPanic recovers a panic from the named handler, logs it and shows it in an error modal.
+// It must be deferred directly by the callback. The modal is queued, since the callback may be
+// running on a goroutine (see the async builtin).
+func (e *Executor) recoverHandlerPanic(name string) {
+	r := recover()
+	if r == nil {
+		return
+	}
+	e.ctx.Logf("template function %q panicked: %v", name, r)
+	text := fmt.Sprintf("Error in %s: %v", name, r)
+	e.ctx.QueueUpdateDraw(func() {
+		showModal(e.ctx, text, []string{"OK"}, func(int) {}, nil)
+	})
+}
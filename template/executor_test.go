@@ -1,6 +1,8 @@
 package template
 
 import (
+	"fmt"
+	"sort"
 	"strings"
 	"testing"
 
@@ -262,6 +264,214 @@ func TestExecuteCallback(t *testing.T) {
 	}
 }
 
+func TestExecuteCallback_Cache(t *testing.T) {
+	executor, ctx := newTestExecutor()
+
+	// Repeated compiles of one expression reuse the parse and still run the handler each time.
+	for _, want := range []string{"a", "a"} {
+		cb, err := executor.ExecuteCallback(`{{ testFuncOneArg "a" }}`)
+		if err != nil {
+			t.Fatalf("ExecuteCallback: %v", err)
+		}
+		ctx.SetStateDirect("testFuncOneArg", "")
+		cb()
+		if v, _ := ctx.GetState("testFuncOneArg"); v != want {
+			t.Errorf("testFuncOneArg = %v, want %q", v, want)
+		}
+	}
+	if len(executor.calls) != 1 {
+		t.Errorf("cached calls = %d, want 1", len(executor.calls))
+	}
+
+	// Expressions that differ only in their arguments are cached separately.
+	cb, err := executor.ExecuteCallback(`{{ testFuncOneArg "b" }}`)
+	if err != nil {
+		t.Fatalf("ExecuteCallback: %v", err)
+	}
+	cb()
+	if v, _ := ctx.GetState("testFuncOneArg"); v != "b" {
+		t.Errorf("testFuncOneArg = %v, want %q", v, "b")
+	}
+
+	// Validators run on every compile, not just the first.
+	if _, err := executor.ExecuteCallback(`{{ testFuncWithValidator "invalid" }}`); err == nil {
+		t.Error("expected validation error")
+	}
+	if _, err := executor.ExecuteCallback(`{{ testFuncWithValidator "invalid" }}`); err == nil {
+		t.Error("expected validation error on the cached expression")
+	}
+
+	// Errors are not cached: a function registered after a failed lookup resolves.
+	if _, err := executor.ExecuteCallback(`{{ lateFunc }}`); err == nil {
+		t.Fatal("expected unknown function error")
+	}
+	zero := 0
+	if err := executor.registry.Register("lateFunc", 0, &zero, nil, func(ctx *Context) {}); err != nil {
+		t.Fatalf("Register: %v", err)
+	}
+	if _, err := executor.ExecuteCallback(`{{ lateFunc }}`); err != nil {
+		t.Errorf("ExecuteCallback after registering: %v", err)
+	}
+}
+
+func TestParamNamesInArgCountErrors(t *testing.T) {
+	executor, _ := newTestExecutor()
+	two := 2
+	if err := executor.registry.Register("moveItem", 2, &two, nil, func(ctx *Context, from, to string) {}, "from", "to"); err != nil {
+		t.Fatalf("Register: %v", err)
+	}
+	if err := executor.registry.RegisterEvaluator("lookup", 1, 2, func(ctx *Context, args []string) string { return "" }, "key", "fallback"); err != nil {
+		t.Fatalf("RegisterEvaluator: %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		run     func() error
+		wantErr string
+	}{
+		{"function too few", func() error {
+			_, err := executor.ExecuteCallback(`{{ moveItem "a" }}`)
+			return err
+		}, `function "moveItem" expects from to, got 1 argument(s)`},
+		{"variadic builtin with optional argument", func() error {
+			_, err := executor.ExecuteCallback(`{{ async }}`)
+			return err
+		}, `function "async" expects action [busyKey], got 0 argument(s)`},
+		{"evaluator too many", func() error {
+			_, err := executor.EvaluateToString(`{{ lookup a b c }}`)
+			return err
+		}, `evaluator "lookup" expects key [fallback], got 3 args`},
+		{"builtin evaluator", func() error {
+			_, err := executor.EvaluateToString(`{{ bindState }}`)
+			return err
+		}, `evaluator "bindState" expects key, got 0 args`},
+		{"check call", func() error {
+			return executor.registry.CheckCall(`switchToPage`)
+		}, `function "switchToPage" expects pageName, got 0 argument(s)`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.run(); err == nil || err.Error() != tt.wantErr {
+				t.Errorf("error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+
+	// Names must match the arguments
+	if err := executor.registry.Register("badNames", 2, &two, nil, func(ctx *Context, a, b string) {}, "a"); err == nil ||
+		!strings.Contains(err.Error(), "has 1 parameter name(s), want 2") {
+		t.Errorf("Register with too few names: err = %v", err)
+	}
+	if err := executor.registry.RegisterEvaluator("badEval", 1, 1, func(ctx *Context, args []string) string { return "" }, "a", "b"); err == nil ||
+		!strings.Contains(err.Error(), "has 2 parameter name(s), want 1") {
+		t.Errorf("RegisterEvaluator with too many names: err = %v", err)
+	}
+}
+
+func TestFunctionRegistry_ListAndDescribe(t *testing.T) {
+	registry := NewFunctionRegistry()
+	two := 2
+	if err := registry.Register("moveItem", 2, &two, nil, func(ctx *Context, from, to string) {}, "from", "to"); err != nil {
+		t.Fatalf("Register: %v", err)
+	}
+	if err := registry.Describe("moveItem", "Move an item"); err != nil {
+		t.Fatalf("Describe: %v", err)
+	}
+	if err := registry.Describe("missing", "Nothing"); err == nil {
+		t.Error("Describe of an unregistered name should fail")
+	}
+
+	infos := registry.List()
+	if !sort.SliceIsSorted(infos, func(i, j int) bool { return infos[i].Name < infos[j].Name }) {
+		t.Error("List() is not sorted by name")
+	}
+	byName := make(map[string]FunctionInfo)
+	for _, info := range infos {
+		byName[info.Name] = info
+		if info.Description == "" {
+			t.Errorf("%s has no description", info.Name)
+		}
+	}
+
+	tests := []struct {
+		name      string
+		evaluator bool
+		usage     string
+		desc      string
+	}{
+		{"moveItem", false, "moveItem from to", "Move an item"},
+		{"bindState", true, "bindState key", "Value of a state key"},
+		{"showSimpleModal", false, "showSimpleModal text [buttons...]", "Show a modal with text and buttons"},
+		{"stopApp", false, "stopApp", "Stop the application"},
+	}
+	for _, tt := range tests {
+		info, ok := byName[tt.name]
+		if !ok {
+			t.Errorf("List() has no %s", tt.name)
+			continue
+		}
+		if info.Evaluator != tt.evaluator || info.Usage() != tt.usage || info.Description != tt.desc {
+			t.Errorf("%s: evaluator/usage/description = %v/%q/%q, want %v/%q/%q",
+				tt.name, info.Evaluator, info.Usage(), info.Description, tt.evaluator, tt.usage, tt.desc)
+		}
+	}
+	if info := byName["showSimpleModal"]; info.MinArgs != 1 || info.MaxArgs != nil {
+		t.Errorf("showSimpleModal args = %d/%v, want 1/unlimited", info.MinArgs, info.MaxArgs)
+	}
+	if got := (FunctionInfo{Name: "log", MinArgs: 1, MaxArgs: &two}).Usage(); got != "log (1-2 args)" {
+		t.Errorf("Usage() without parameter names = %q", got)
+	}
+
+	// Changing a listed entry does not change the registry
+	moved, bound := byName["moveItem"], byName["bindState"]
+	*moved.MaxArgs = 5
+	moved.ParamNames[0] = "changed"
+	bound.ParamNames[0] = "changed"
+	for _, info := range registry.List() {
+		if (info.Name == "moveItem" || info.Name == "bindState") && info.ParamNames[0] == "changed" {
+			t.Errorf("List() shares ParamNames of %s with the registry", info.Name)
+		}
+		if info.Name == "moveItem" && *info.MaxArgs != 2 {
+			t.Errorf("List() shares MaxArgs with the registry: moveItem max is now %d", *info.MaxArgs)
+		}
+	}
+}
+
+func TestExecuteCallback_RecoversPanic(t *testing.T) {
+	executor, ctx := newTestExecutor()
+	ctx.App = nil // no running app: QueueUpdateDraw shows the error modal immediately
+	var logged []string
+	ctx.SetLogger(func(format string, args ...interface{}) {
+		logged = append(logged, fmt.Sprintf(format, args...))
+	})
+	if err := executor.registry.Register("explode", 0, nil, nil, func(ctx *Context, args []string) {
+		_ = args[3]
+	}); err != nil {
+		t.Fatalf("Register: %v", err)
+	}
+	cb, err := executor.ExecuteCallback(`{{ explode }}`)
+	if err != nil {
+		t.Fatalf("ExecuteCallback: %v", err)
+	}
+
+	cb() // must not panic
+	if len(logged) != 1 || !strings.Contains(logged[0], `"explode" panicked`) {
+		t.Errorf("logged = %q, want one panic message", logged)
+	}
+	_, front := ctx.Pages.GetFrontPage()
+	if _, ok := front.(*tview.Modal); !ok {
+		t.Errorf("front page is %T, want error *tview.Modal", front)
+	}
+
+	ctx.SetRecoverPanics(false)
+	defer func() {
+		if recover() == nil {
+			t.Error("callback did not panic with recovery disabled")
+		}
+	}()
+	cb()
+}
+
 func TestExtractBindStateKeys(t *testing.T) {
 	executor, _ := newTestExecutor()
 
@@ -354,3 +564,26 @@ func BenchmarkEvaluateToString_Long(b *testing.B) {
 		_, _ = executor.EvaluateToString(template)
 	}
 }
+
+// Benchmark compiling a callback whose expression was already parsed by this executor
+func BenchmarkExecuteCallback_Cached(b *testing.B) {
+	executor, _ := newTestExecutor()
+	expr := `{{ testFuncTwoArgs "first" "second" }}`
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_, _ = executor.ExecuteCallback(expr)
+	}
+}
+
+// Benchmark compiling a callback with a fresh executor each time, so every call parses
+func BenchmarkExecuteCallback_Uncached(b *testing.B) {
+	ctx := newTestContext()
+	registry := newTestRegistry()
+	expr := `{{ testFuncTwoArgs "first" "second" }}`
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_, _ = NewExecutor(ctx, registry).ExecuteCallback(expr)
+	}
+}
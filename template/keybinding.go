@@ -10,6 +10,7 @@ import (
 
 // MatchesKeyBinding returns true if the given key event matches the binding.
 // The binding.Key string supports formats like "Escape", "Enter", "Ctrl+Q", "Ctrl+C", "F1", "Alt+a".
+// Runes match case-insensitively unless binding.CaseSensitive is set.
 func MatchesKeyBinding(event *tcell.EventKey, binding config.KeyBinding) bool {
 	key, mod, ch, err := keys.ParseKey(binding.Key)
 	if err != nil {
@@ -23,6 +24,23 @@ func MatchesKeyBinding(event *tcell.EventKey, binding config.KeyBinding) bool {
 	eventKey := event.Key()
 	eventRune := event.Rune()
 
+	// Case-sensitive rune bindings: "g" and "G" are distinct. Shift is carried by the rune's case
+	// ("Shift+g" means "G"), so the Shift modifier itself is ignored since terminals report it inconsistently.
+	if binding.CaseSensitive && key == tcell.KeyRune && mod&tcell.ModCtrl == 0 {
+		want := ch
+		if mod&tcell.ModShift != 0 {
+			want = unicode.ToUpper(ch)
+		}
+		wantMod := mod & (tcell.ModAlt | tcell.ModMeta)
+		gotMod := eventMod & (tcell.ModCtrl | tcell.ModAlt | tcell.ModMeta)
+		return eventKey == tcell.KeyRune && wantMod == gotMod && eventRune == want
+	}
+
+	// Ctrl+Space may also arrive as a space rune with Ctrl (terminals with extended key reporting).
+	if key == tcell.KeyCtrlSpace && eventKey == tcell.KeyRune && eventRune == ' ' {
+		return eventMod&(tcell.ModCtrl|tcell.ModAlt|tcell.ModShift|tcell.ModMeta) == mod&(tcell.ModCtrl|tcell.ModAlt|tcell.ModShift|tcell.ModMeta)
+	}
+
 	// Modifiers must match (only check the ones we care about - masked for resilience)
 	wantMod := mod & (tcell.ModCtrl | tcell.ModAlt | tcell.ModShift | tcell.ModMeta)
 	gotMod := eventMod & (tcell.ModCtrl | tcell.ModAlt | tcell.ModShift | tcell.ModMeta)
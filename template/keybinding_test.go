@@ -55,6 +55,14 @@ func TestMatchesKeyBinding(t *testing.T) {
 		{"Ctrl+Q no modifier", config.KeyBinding{Key: "Ctrl+Q"}, tcell.NewEventKey(tcell.KeyRune, 'q', 0), false, "Ctrl+Q binding without Ctrl modifier"},
 		{"Ctrl+Q wrong key", config.KeyBinding{Key: "Ctrl+Q"}, tcell.NewEventKey(tcell.KeyRune, 'w', tcell.ModCtrl), false, "Ctrl+Q binding with Ctrl+W event"},
 
+		// Control symbols
+		{"Ctrl+Space NUL", config.KeyBinding{Key: "Ctrl+Space"}, tcell.NewEventKey(tcell.KeyRune, 0, 0), true, "Ctrl+Space with NUL control code"},
+		{"Ctrl+Space rune", config.KeyBinding{Key: "Ctrl+Space"}, tcell.NewEventKey(tcell.KeyRune, ' ', tcell.ModCtrl), true, "Ctrl+Space reported as space rune with Ctrl"},
+		{"Ctrl+Space plain space", config.KeyBinding{Key: "Ctrl+Space"}, tcell.NewEventKey(tcell.KeyRune, ' ', 0), false, "Ctrl+Space binding with plain space"},
+		{"space with NUL", config.KeyBinding{Key: "space"}, tcell.NewEventKey(tcell.KeyRune, 0, 0), false, "space binding with Ctrl+Space event"},
+		{"Ctrl+]", config.KeyBinding{Key: "Ctrl+]"}, tcell.NewEventKey(tcell.KeyRune, 0x1d, 0), true, "Ctrl+] with GS control code"},
+		{"Ctrl+_ mismatch", config.KeyBinding{Key: "Ctrl+_"}, tcell.NewEventKey(tcell.KeyRune, 0x1d, 0), false, "Ctrl+_ binding with Ctrl+] event"},
+
 		// Modifiers - Alt
 		{"Alt+F1", config.KeyBinding{Key: "Alt+F1"}, tcell.NewEventKey(tcell.KeyF1, 0, tcell.ModAlt), true, "Alt+F1"},
 		{"Alt+a", config.KeyBinding{Key: "Alt+a"}, tcell.NewEventKey(tcell.KeyRune, 'a', tcell.ModAlt), true, "Alt+a"},
@@ -99,6 +107,38 @@ func TestMatchesKeyBinding(t *testing.T) {
 	}
 }
 
+func TestMatchesKeyBinding_CaseSensitive(t *testing.T) {
+	tests := []struct {
+		name          string
+		key           string
+		caseSensitive bool
+		event         *tcell.EventKey
+		want          bool
+	}{
+		{"insensitive g matches G", "g", false, tcell.NewEventKey(tcell.KeyRune, 'G', 0), true},
+		{"insensitive G matches g", "G", false, tcell.NewEventKey(tcell.KeyRune, 'g', 0), true},
+		{"sensitive g matches g", "g", true, tcell.NewEventKey(tcell.KeyRune, 'g', 0), true},
+		{"sensitive g rejects G", "g", true, tcell.NewEventKey(tcell.KeyRune, 'G', 0), false},
+		{"sensitive G matches G", "G", true, tcell.NewEventKey(tcell.KeyRune, 'G', 0), true},
+		{"sensitive G matches G with Shift", "G", true, tcell.NewEventKey(tcell.KeyRune, 'G', tcell.ModShift), true},
+		{"sensitive G rejects g", "G", true, tcell.NewEventKey(tcell.KeyRune, 'g', 0), false},
+		{"sensitive Shift+g matches G", "Shift+g", true, tcell.NewEventKey(tcell.KeyRune, 'G', 0), true},
+		{"sensitive Shift+g rejects g", "Shift+g", true, tcell.NewEventKey(tcell.KeyRune, 'g', 0), false},
+		{"sensitive Alt+G matches Alt+G", "Alt+G", true, tcell.NewEventKey(tcell.KeyRune, 'G', tcell.ModAlt), true},
+		{"sensitive Alt+G rejects G", "Alt+G", true, tcell.NewEventKey(tcell.KeyRune, 'G', 0), false},
+		{"sensitive Ctrl+G still insensitive", "Ctrl+G", true, tcell.NewEventKey(tcell.KeyRune, 'g', tcell.ModCtrl), true},
+		{"sensitive special key unaffected", "F1", true, tcell.NewEventKey(tcell.KeyF1, 0, 0), true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			binding := config.KeyBinding{Key: tt.key, CaseSensitive: tt.caseSensitive}
+			if got := MatchesKeyBinding(tt.event, binding); got != tt.want {
+				t.Errorf("MatchesKeyBinding(%q, caseSensitive=%v) = %v, want %v", tt.key, tt.caseSensitive, got, tt.want)
+			}
+		})
+	}
+}
+
 // TestMatchesKeyBindingWithControlCodes tests Ctrl+letter combinations that produce ASCII control codes
 func TestMatchesKeyBindingWithControlCodes(t *testing.T) {
 	tests := []struct {
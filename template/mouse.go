@@ -0,0 +1,30 @@
+package template
+
+import "github.com/rivo/tview"
+
+// mouseActionNames maps tview mouse actions to the names stored in __mouseAction.
+var mouseActionNames = map[tview.MouseAction]string{
+	tview.MouseMove:              "move",
+	tview.MouseLeftDown:          "leftDown",
+	tview.MouseLeftUp:            "leftUp",
+	tview.MouseLeftClick:         "leftClick",
+	tview.MouseLeftDoubleClick:   "leftDoubleClick",
+	tview.MouseMiddleDown:        "middleDown",
+	tview.MouseMiddleUp:          "middleUp",
+	tview.MouseMiddleClick:       "middleClick",
+	tview.MouseMiddleDoubleClick: "middleDoubleClick",
+	tview.MouseRightDown:         "rightDown",
+	tview.MouseRightUp:           "rightUp",
+	tview.MouseRightClick:        "rightClick",
+	tview.MouseRightDoubleClick:  "rightDoubleClick",
+	tview.MouseScrollUp:          "scrollUp",
+	tview.MouseScrollDown:        "scrollDown",
+	tview.MouseScrollLeft:        "scrollLeft",
+	tview.MouseScrollRight:       "scrollRight",
+}
+
+// MouseActionName returns the name of a tview mouse action (e.g. "leftClick", "scrollUp").
+// Returns an empty string for unknown actions.
+func MouseActionName(action tview.MouseAction) string {
+	return mouseActionNames[action]
+}
@@ -0,0 +1,25 @@
+package template
+
+import (
+	"testing"
+
+	"github.com/rivo/tview"
+)
+
+func TestMouseActionName(t *testing.T) {
+	tests := []struct {
+		action tview.MouseAction
+		want   string
+	}{
+		{tview.MouseMove, "move"},
+		{tview.MouseLeftClick, "leftClick"},
+		{tview.MouseRightDoubleClick, "rightDoubleClick"},
+		{tview.MouseScrollDown, "scrollDown"},
+		{tview.MouseAction(-1), ""},
+	}
+	for _, tt := range tests {
+		if got := MouseActionName(tt.action); got != tt.want {
+			t.Errorf("MouseActionName(%d) = %q, want %q", tt.action, got, tt.want)
+		}
+	}
+}
@@ -3,23 +3,39 @@ package template
 import (
 	"fmt"
 	"reflect"
+	"sort"
+	"strings"
 )
 
 // TemplateFunction defines a registered template function
 type TemplateFunction struct {
-	Name      string
-	MinArgs   int
-	MaxArgs   *int // nil means unlimited (variadic)
-	Validator func(*Context, []string) error
-	Handler   interface{} // Function that executes the template logic
+	Name        string
+	MinArgs     int
+	MaxArgs     *int // nil means unlimited (variadic)
+	Validator   func(*Context, []string) error
+	Handler     interface{} // Function that executes the template logic
+	ParamNames  []string    // Optional names of the arguments, used in error messages
+	Description string      // Optional one-line summary, set with Describe
 }
 
 // TemplateEvaluator defines a value-returning template function (e.g. bindState)
 type TemplateEvaluator struct {
-	Name    string
-	MinArgs int
-	MaxArgs int // evaluators use fixed arg count
-	Handler func(*Context, []string) string
+	Name        string
+	MinArgs     int
+	MaxArgs     int // evaluators use fixed arg count
+	Handler     func(*Context, []string) string
+	ParamNames  []string // Optional names of the arguments, used in error messages
+	Description string   // Optional one-line summary, set with Describe
+}
+
+// FunctionInfo describes a registered function or evaluator, as returned by List.
+type FunctionInfo struct {
+	Name        string
+	Evaluator   bool // true for a value-returning evaluator used in {{ }} text
+	MinArgs     int
+	MaxArgs     *int // nil means unlimited (variadic)
+	ParamNames  []string
+	Description string
 }
 
 // FunctionRegistry manages registered template functions
@@ -38,19 +54,24 @@ func NewFunctionRegistry() *FunctionRegistry {
 	return registry
 }
 
-// RegisterEvaluator adds a value-returning template function (e.g. bindState)
-func (r *FunctionRegistry) RegisterEvaluator(name string, minArgs, maxArgs int, handler func(*Context, []string) string) error {
+// RegisterEvaluator adds a value-returning template function (e.g. bindState). paramNames
+// optionally names each argument (one per argument, up to maxArgs) so errors say what is expected.
+func (r *FunctionRegistry) RegisterEvaluator(name string, minArgs, maxArgs int, handler func(*Context, []string) string, paramNames ...string) error {
 	if minArgs < 0 || maxArgs < minArgs {
 		return fmt.Errorf("invalid evaluator args: minArgs=%d maxArgs=%d", minArgs, maxArgs)
 	}
 	if _, exists := r.evaluators[name]; exists {
 		return fmt.Errorf("evaluator %q is already registered", name)
 	}
+	if len(paramNames) > 0 && len(paramNames) != maxArgs {
+		return fmt.Errorf("evaluator %q has %d parameter name(s), want %d", name, len(paramNames), maxArgs)
+	}
 	r.evaluators[name] = &TemplateEvaluator{
-		Name:    name,
-		MinArgs: minArgs,
-		MaxArgs: maxArgs,
-		Handler: handler,
+		Name:       name,
+		MinArgs:    minArgs,
+		MaxArgs:    maxArgs,
+		Handler:    handler,
+		ParamNames: paramNames,
 	}
 	return nil
 }
@@ -61,8 +82,10 @@ func (r *FunctionRegistry) GetEvaluator(name string) (*TemplateEvaluator, bool)
 	return ev, ok
 }
 
-// Register adds a new template function to the registry
-func (r *FunctionRegistry) Register(name string, minArgs int, maxArgs *int, validator func(*Context, []string) error, handler interface{}) error {
+// Register adds a new template function to the registry. paramNames optionally names the
+// arguments so errors say what is expected: one per argument, up to maxArgs, or for a variadic
+// function at least minArgs (e.g. "text", "buttons...").
+func (r *FunctionRegistry) Register(name string, minArgs int, maxArgs *int, validator func(*Context, []string) error, handler interface{}, paramNames ...string) error {
 	// Validate minArgs
 	if minArgs < 0 {
 		return fmt.Errorf("minArgs must be non-negative, got %d", minArgs)
@@ -85,12 +108,23 @@ func (r *FunctionRegistry) Register(name string, minArgs int, maxArgs *int, vali
 		return fmt.Errorf("invalid handler signature for function %q: %w", name, err)
 	}
 
+	// Validate parameter names, if any
+	if len(paramNames) > 0 {
+		if maxArgs != nil && len(paramNames) != *maxArgs {
+			return fmt.Errorf("function %q has %d parameter name(s), want %d", name, len(paramNames), *maxArgs)
+		}
+		if maxArgs == nil && len(paramNames) < minArgs {
+			return fmt.Errorf("function %q has %d parameter name(s), want at least %d", name, len(paramNames), minArgs)
+		}
+	}
+
 	r.functions[name] = &TemplateFunction{
-		Name:      name,
-		MinArgs:   minArgs,
-		MaxArgs:   maxArgs,
-		Validator: validator,
-		Handler:   handler,
+		Name:       name,
+		MinArgs:    minArgs,
+		MaxArgs:    maxArgs,
+		Validator:  validator,
+		Handler:    handler,
+		ParamNames: paramNames,
 	}
 
 	return nil
@@ -102,10 +136,162 @@ func (r *FunctionRegistry) Get(name string) (*TemplateFunction, bool) {
 	return fn, ok
 }
 
+// Describe sets the description of a registered function or evaluator (both, if the name is
+// registered as each), e.g. for a generated help page. Call it after registering.
+func (r *FunctionRegistry) Describe(name, description string) error {
+	fn, isFunc := r.functions[name]
+	ev, isEval := r.evaluators[name]
+	if !isFunc && !isEval {
+		return fmt.Errorf("unknown function/evaluator %q", name)
+	}
+	if isFunc {
+		fn.Description = description
+	}
+	if isEval {
+		ev.Description = description
+	}
+	return nil
+}
+
+// List returns every registered function and evaluator, sorted by name (a function before an
+// evaluator of the same name).
+func (r *FunctionRegistry) List() []FunctionInfo {
+	infos := make([]FunctionInfo, 0, len(r.functions)+len(r.evaluators))
+	for _, fn := range r.functions {
+		// Copy the pointer and slice so callers cannot change the registered function
+		var maxArgs *int
+		if fn.MaxArgs != nil {
+			n := *fn.MaxArgs
+			maxArgs = &n
+		}
+		infos = append(infos, FunctionInfo{
+			Name:        fn.Name,
+			MinArgs:     fn.MinArgs,
+			MaxArgs:     maxArgs,
+			ParamNames:  append([]string(nil), fn.ParamNames...),
+			Description: fn.Description,
+		})
+	}
+	for _, ev := range r.evaluators {
+		maxArgs := ev.MaxArgs
+		infos = append(infos, FunctionInfo{
+			Name:        ev.Name,
+			Evaluator:   true,
+			MinArgs:     ev.MinArgs,
+			MaxArgs:     &maxArgs,
+			ParamNames:  append([]string(nil), ev.ParamNames...),
+			Description: ev.Description,
+		})
+	}
+	sort.Slice(infos, func(i, j int) bool {
+		if infos[i].Name != infos[j].Name {
+			return infos[i].Name < infos[j].Name
+		}
+		return !infos[i].Evaluator && infos[j].Evaluator
+	})
+	return infos
+}
+
+// Usage returns the call as written in a template: the name followed by the parameter names,
+// optional ones in brackets, e.g. "async action [busyKey]". Without parameter names it gives
+// the argument counts instead, e.g. "appendText (2 args)".
+func (info FunctionInfo) Usage() string {
+	if len(info.ParamNames) > 0 {
+		return info.Name + " " + paramUsage(info.ParamNames, info.MinArgs)
+	}
+	switch {
+	case info.MaxArgs == nil:
+		return fmt.Sprintf("%s (%d+ args)", info.Name, info.MinArgs)
+	case *info.MaxArgs == 0:
+		return info.Name
+	case *info.MaxArgs == info.MinArgs:
+		return fmt.Sprintf("%s (%d args)", info.Name, info.MinArgs)
+	default:
+		return fmt.Sprintf("%s (%d-%d args)", info.Name, info.MinArgs, *info.MaxArgs)
+	}
+}
+
+// CheckCall checks a callback expression ("name args...", without delimiters) without running it:
+// name must be a registered function, or an evaluator, and receive an accepted number of
+// arguments. Function validators are not run, since they may depend on state.
+func (r *FunctionRegistry) CheckCall(expr string) error {
+	matches := callExprPattern.FindStringSubmatch(strings.TrimSpace(expr))
+	if len(matches) < 2 {
+		return fmt.Errorf("invalid template expression: %s", expr)
+	}
+	if fn, ok := r.functions[matches[1]]; ok {
+		return checkFunctionArgs(fn, len(parseArguments(strings.TrimSpace(matches[2]))))
+	}
+	if _, ok := r.evaluators[matches[1]]; !ok {
+		return fmt.Errorf("unknown function/evaluator %q", matches[1])
+	}
+	return r.CheckEvaluator(expr)
+}
+
+// CheckEvaluator checks the content of a {{ }} block in text: it must call a registered evaluator
+// with an accepted number of arguments.
+func (r *FunctionRegistry) CheckEvaluator(expr string) error {
+	name, args := parseEvaluatorExpr(expr)
+	ev, ok := r.evaluators[name]
+	if !ok {
+		return fmt.Errorf("unknown evaluator: %s", name)
+	}
+	return checkEvaluatorArgs(ev, len(args))
+}
+
+// CheckText checks every {{ }} block in display text (e.g. a TextView's text) with CheckEvaluator,
+// returning the first error.
+func (r *FunctionRegistry) CheckText(text string) error {
+	for _, expr := range extractTemplateExpressions(text) {
+		if err := r.CheckEvaluator(expr); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// checkFunctionArgs reports whether fn accepts n arguments.
+func checkFunctionArgs(fn *TemplateFunction, n int) error {
+	if len(fn.ParamNames) > 0 && (n < fn.MinArgs || fn.MaxArgs != nil && n > *fn.MaxArgs) {
+		return fmt.Errorf("function %q expects %s, got %d argument(s)", fn.Name, paramUsage(fn.ParamNames, fn.MinArgs), n)
+	}
+	if n < fn.MinArgs {
+		return fmt.Errorf("function %q requires at least %d argument(s), got %d", fn.Name, fn.MinArgs, n)
+	}
+	if fn.MaxArgs != nil && n > *fn.MaxArgs {
+		return fmt.Errorf("function %q accepts at most %d argument(s), got %d", fn.Name, *fn.MaxArgs, n)
+	}
+	return nil
+}
+
+// checkEvaluatorArgs reports whether ev accepts n arguments.
+func checkEvaluatorArgs(ev *TemplateEvaluator, n int) error {
+	if n < ev.MinArgs || n > ev.MaxArgs {
+		if len(ev.ParamNames) > 0 {
+			return fmt.Errorf("evaluator %q expects %s, got %d args", ev.Name, paramUsage(ev.ParamNames, ev.MinArgs), n)
+		}
+		return fmt.Errorf("evaluator %q expects %d-%d args, got %d", ev.Name, ev.MinArgs, ev.MaxArgs, n)
+	}
+	return nil
+}
+
+// paramUsage lists parameter names as in a call, optional ones (past minArgs) in brackets:
+// "action [busyKey]".
+func paramUsage(names []string, minArgs int) string {
+	parts := make([]string, len(names))
+	for i, name := range names {
+		if i >= minArgs {
+			name = "[" + name + "]"
+		}
+		parts[i] = name
+	}
+	return strings.Join(parts, " ")
+}
+
 // validateHandlerSignature checks if the handler function has the correct signature
 func (r *FunctionRegistry) validateHandlerSignature(handler interface{}, maxArgs *int) error {
 	handlerType := reflect.TypeOf(handler)
-	
+
 	// Must be a function
 	if handlerType.Kind() != reflect.Func {
 		return fmt.Errorf("handler must be a function, got %s", handlerType.Kind())
@@ -0,0 +1,248 @@
+// Package testutil provides a test harness for tviewyaml applications. It runs a built
+// Application on a tcell SimulationScreen and offers helpers to wait for draws, inject
+// key events, and compare the rendered terminal against golden snapshots.
+package testutil
+
+import (
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/cassdeckard/tviewyaml"
+	"github.com/cassdeckard/tviewyaml/keys"
+	"github.com/gdamore/tcell/v2"
+)
+
+// DrawTimeout is how long the harness waits for a draw before giving up.
+var DrawTimeout = 3 * time.Second
+
+// BuildFunc builds an application that draws to the given screen.
+type BuildFunc func(screen tcell.Screen) (*tviewyaml.Application, []error, error)
+
+// FromBuilder returns a BuildFunc that builds b with the harness screen.
+func FromBuilder(b *tviewyaml.AppBuilder) BuildFunc {
+	return func(screen tcell.Screen) (*tviewyaml.Application, []error, error) {
+		return b.WithScreen(screen).Build()
+	}
+}
+
+// Harness runs an application on a SimulationScreen and provides helpers to wait for
+// draws, inject input, and assert on the rendered content.
+type Harness struct {
+	app       *tviewyaml.Application
+	screen    tcell.SimulationScreen
+	drawDone  chan struct{}
+	contentMu sync.Mutex
+	content   string
+	cells     [][]cell
+	lastCols  int
+	lastRows  int
+	runDone   chan struct{}
+}
+
+// New builds the application with a simulation screen of the given size, starts Run() in a
+// goroutine, and waits for the first draw. Non-fatal page errors are logged. The application
+// is stopped automatically when the test finishes.
+func New(t testing.TB, build BuildFunc, cols, rows int) *Harness {
+	t.Helper()
+	sim := tcell.NewSimulationScreen("UTF-8")
+	if err := sim.Init(); err != nil {
+		t.Fatalf("SimulationScreen Init: %v", err)
+	}
+	app, pageErrors, err := build(sim)
+	if err != nil {
+		sim.Fini()
+		t.Fatalf("Build: %v", err)
+	}
+	if len(pageErrors) > 0 {
+		t.Logf("Build had %d page errors (non-fatal): %v", len(pageErrors), pageErrors)
+	}
+
+	// Set simulation screen size so the first draw uses the correct dimensions.
+	sim.SetSize(cols, rows)
+
+	h := &Harness{
+		app:      app,
+		screen:   sim,
+		drawDone: make(chan struct{}, 1),
+		runDone:  make(chan struct{}),
+	}
+	app.SetAfterDrawFunc(h.afterDraw)
+
+	go func() {
+		defer close(h.runDone)
+		_ = app.Run()
+	}()
+
+	// Queue resize so the app's layout runs at desired size; screen is already SetSize'd above.
+	h.Resize(cols, rows)
+	if !h.WaitForDraw() {
+		app.Stop()
+		<-h.runDone
+		t.Fatal("timeout waiting for initial draw")
+	}
+	t.Cleanup(h.Stop)
+	return h
+}
+
+// cell is one screen position as of the last draw.
+type cell struct {
+	mainc rune
+	style tcell.Style
+}
+
+// afterDraw renders the screen to text (with SGR styling), captures the cell grid, and signals waiters.
+func (h *Harness) afterDraw(screen tcell.Screen) {
+	content, w, hi := renderScreen(screen)
+	cells := make([][]cell, hi)
+	for y := range cells {
+		cells[y] = make([]cell, w)
+		for x := range cells[y] {
+			mainc, _, style, _ := screen.GetContent(x, y)
+			cells[y][x] = cell{mainc: mainc, style: style}
+		}
+	}
+	h.contentMu.Lock()
+	h.content = content
+	h.cells = cells
+	h.lastCols = w
+	h.lastRows = hi
+	h.contentMu.Unlock()
+	select {
+	case h.drawDone <- struct{}{}:
+	default:
+		// already one pending
+	}
+}
+
+// App returns the application under test.
+func (h *Harness) App() *tviewyaml.Application {
+	return h.app
+}
+
+// Screen returns the simulation screen the application draws to.
+func (h *Harness) Screen() tcell.SimulationScreen {
+	return h.screen
+}
+
+// WaitForDraw waits for the next draw. Returns false on timeout.
+func (h *Harness) WaitForDraw() bool {
+	select {
+	case <-h.drawDone:
+		return true
+	case <-time.After(DrawTimeout):
+		return false
+	}
+}
+
+// WaitForDraws waits for n draws (use after injecting input to see the resulting screen).
+func (h *Harness) WaitForDraws(n int) bool {
+	for i := 0; i < n; i++ {
+		if !h.WaitForDraw() {
+			return false
+		}
+	}
+	return true
+}
+
+// WaitForContent waits until the screen contains substr or the timeout is reached.
+func (h *Harness) WaitForContent(substr string) bool {
+	deadline := time.Now().Add(DrawTimeout)
+	for time.Now().Before(deadline) {
+		if h.ScreenContains(substr) {
+			return true
+		}
+		if !h.WaitForDraw() {
+			return false
+		}
+	}
+	return h.ScreenContains(substr)
+}
+
+// Content returns the most recently drawn screen, including SGR style sequences.
+func (h *Harness) Content() string {
+	h.contentMu.Lock()
+	defer h.contentMu.Unlock()
+	return h.content
+}
+
+// ScreenContains reports whether the most recently drawn screen contains substr.
+func (h *Harness) ScreenContains(substr string) bool {
+	return strings.Contains(h.Content(), substr)
+}
+
+// CellAt returns the rune and style at column x, row y as of the last draw.
+// Empty cells are reported as ' '. Out-of-range positions return 0 and tcell.StyleDefault.
+func (h *Harness) CellAt(x, y int) (rune, tcell.Style) {
+	h.contentMu.Lock()
+	defer h.contentMu.Unlock()
+	if y < 0 || y >= len(h.cells) || x < 0 || x >= len(h.cells[y]) {
+		return 0, tcell.StyleDefault
+	}
+	c := h.cells[y][x]
+	if c.mainc == 0 {
+		return ' ', c.style
+	}
+	return c.mainc, c.style
+}
+
+// RegionText returns the plain text (without style sequences) of the rectangle at column x,
+// row y with the given width and height, one line per row. Parts outside the screen are omitted.
+func (h *Harness) RegionText(x, y, width, height int) string {
+	h.contentMu.Lock()
+	defer h.contentMu.Unlock()
+	var lines []string
+	for row := y; row < y+height; row++ {
+		if row < 0 || row >= len(h.cells) {
+			continue
+		}
+		var b strings.Builder
+		for col := x; col < x+width; col++ {
+			if col < 0 || col >= len(h.cells[row]) {
+				continue
+			}
+			if r := h.cells[row][col].mainc; r != 0 {
+				b.WriteRune(r)
+			} else {
+				b.WriteByte(' ')
+			}
+		}
+		lines = append(lines, b.String())
+	}
+	return strings.Join(lines, "\n")
+}
+
+// TakeSnapshot returns the current terminal content and dimensions.
+// Call WaitForDraw() first if a fresh frame is needed.
+func (h *Harness) TakeSnapshot() Snapshot {
+	h.contentMu.Lock()
+	defer h.contentMu.Unlock()
+	return Snapshot{Content: h.content, Cols: h.lastCols, Rows: h.lastRows}
+}
+
+// Resize queues a resize event so the application lays out at the new size.
+func (h *Harness) Resize(cols, rows int) {
+	h.app.QueueEvent(tcell.NewEventResize(cols, rows))
+}
+
+// TypeKey queues a key event parsed with keys.ParseKey (e.g. "Enter", "Ctrl+S", "a").
+// Panics if keyStr cannot be parsed, since that is a mistake in the test itself.
+func (h *Harness) TypeKey(keyStr string) {
+	tcellKey, mod, r, err := keys.ParseKey(keyStr)
+	if err != nil {
+		panic("TypeKey: " + err.Error())
+	}
+	h.app.QueueEvent(tcell.NewEventKey(tcellKey, r, mod))
+}
+
+// Stop stops the application and waits for Run() to return. Safe to call more than once.
+func (h *Harness) Stop() {
+	select {
+	case <-h.runDone:
+		return
+	default:
+	}
+	h.app.Stop()
+	<-h.runDone
+}
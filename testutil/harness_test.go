@@ -0,0 +1,193 @@
+package testutil
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/cassdeckard/tviewyaml"
+	"github.com/gdamore/tcell/v2"
+)
+
+const testAppYAML = `application:
+  name: "Harness Test"
+  globalKeyBindings:
+    - key: "Escape"
+      action: '{{ switchToPage "main" }}'
+  root:
+    type: pages
+    pages:
+      - name: main
+        ref: main.yaml
+      - name: about
+        ref: about.yaml
+`
+
+const testMainYAML = `type: list
+listItems:
+  - mainText: "Go to about"
+    shortcut: "a"
+    onSelected: '{{ switchToPage "about" }}'
+`
+
+const testAboutYAML = `type: flex
+items:
+  - primitive:
+      type: textView
+      text: "About Page"
+    proportion: 1
+`
+
+// writeTestConfig writes a two-page config to a temp dir and returns its path.
+// mainYAML replaces the default main page when non-empty.
+func writeTestConfig(t *testing.T, mainYAML string) string {
+	t.Helper()
+	if mainYAML == "" {
+		mainYAML = testMainYAML
+	}
+	dir := t.TempDir()
+	files := map[string]string{
+		"app.yaml":   testAppYAML,
+		"main.yaml":  mainYAML,
+		"about.yaml": testAboutYAML,
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatalf("write %s: %v", name, err)
+		}
+	}
+	return dir
+}
+
+func newTestHarness(t *testing.T) *Harness {
+	t.Helper()
+	return New(t, FromBuilder(tviewyaml.NewAppBuilder(writeTestConfig(t, ""))), 40, 10)
+}
+
+func TestHarness_InitialDraw(t *testing.T) {
+	h := newTestHarness(t)
+	if !h.ScreenContains("Go to about") {
+		t.Errorf("initial screen should contain list item; got:\n%s", h.Content())
+	}
+	snap := h.TakeSnapshot()
+	if snap.Cols != 40 || snap.Rows != 10 {
+		t.Errorf("snapshot size = %dx%d, want 40x10", snap.Cols, snap.Rows)
+	}
+}
+
+func TestHarness_TypeKeyNavigates(t *testing.T) {
+	h := newTestHarness(t)
+	h.TypeKey("a")
+	if !h.WaitForContent("About Page") {
+		t.Fatalf("timeout waiting for about page; got:\n%s", h.Content())
+	}
+	h.TypeKey("Escape")
+	if !h.WaitForContent("Go to about") {
+		t.Errorf("Escape should return to main page; got:\n%s", h.Content())
+	}
+}
+
+func TestHarness_TypeKeyInvalidPanics(t *testing.T) {
+	h := newTestHarness(t)
+	defer func() {
+		if recover() == nil {
+			t.Error("TypeKey with invalid key should panic")
+		}
+	}()
+	h.TypeKey("Ctrl+Nope")
+}
+
+func TestHarness_AssertGolden(t *testing.T) {
+	h := newTestHarness(t)
+	path := filepath.Join(t.TempDir(), "snapshots", "main.terminal")
+
+	t.Setenv(SnapshotEnvUpdate, "1")
+	h.AssertGolden(t, path)
+	written, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("golden file not written: %v", err)
+	}
+	if !strings.Contains(string(written), "Go to about") {
+		t.Errorf("golden file should contain screen content; got:\n%s", written)
+	}
+
+	t.Setenv(SnapshotEnvUpdate, "")
+	h.AssertGolden(t, path)
+}
+
+func TestHarness_CellAtAndRegionText(t *testing.T) {
+	mainYAML := `type: flex
+items:
+  - primitive:
+      type: textView
+      dynamicColors: true
+      text: "Status: [red]DOWN[-]"
+    proportion: 1
+`
+	h := New(t, FromBuilder(tviewyaml.NewAppBuilder(writeTestConfig(t, mainYAML))), 40, 10)
+
+	r, style := h.CellAt(8, 0)
+	if r != 'D' {
+		t.Errorf("CellAt(8, 0) rune = %q, want 'D'", r)
+	}
+	if fg, _, _ := style.Decompose(); fg != tcell.ColorRed {
+		t.Errorf("CellAt(8, 0) foreground = %v, want red", fg)
+	}
+	if r, _ := h.CellAt(0, 5); r != ' ' {
+		t.Errorf("CellAt(0, 5) rune = %q, want ' '", r)
+	}
+	if r, _ := h.CellAt(40, 0); r != 0 {
+		t.Errorf("CellAt out of range rune = %q, want 0", r)
+	}
+
+	if got, want := h.RegionText(8, 0, 4, 1), "DOWN"; got != want {
+		t.Errorf("RegionText(8, 0, 4, 1) = %q, want %q", got, want)
+	}
+	if got, want := h.RegionText(0, 0, 6, 2), "Status\n      "; got != want {
+		t.Errorf("RegionText(0, 0, 6, 2) = %q, want %q", got, want)
+	}
+	if got, want := h.RegionText(38, 9, 5, 5), "  "; got != want {
+		t.Errorf("RegionText clipped = %q, want %q", got, want)
+	}
+}
+
+func TestHarness_StopIdempotent(t *testing.T) {
+	h := newTestHarness(t)
+	h.Stop()
+	h.Stop()
+}
+
+func TestGoldenPath(t *testing.T) {
+	tests := []struct {
+		name string
+		want string
+	}{
+		{"TestFoo/bar", filepath.Join("testdata", "snapshots", "80x24", "TestFoo_bar.terminal")},
+		{"  ", filepath.Join("testdata", "snapshots", "80x24", "default.terminal")},
+	}
+	for _, tt := range tests {
+		if got := GoldenPath(tt.name, 80, 24); got != tt.want {
+			t.Errorf("GoldenPath(%q) = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestStyleToSGR(t *testing.T) {
+	tests := []struct {
+		name  string
+		style tcell.Style
+		want  string
+	}{
+		{"default", tcell.StyleDefault, "\x1b[0m"},
+		{"bold", tcell.StyleDefault.Bold(true), "\x1b[39;49;1m"},
+		{"rgb fg", tcell.StyleDefault.Foreground(tcell.NewRGBColor(1, 2, 3)), "\x1b[38;2;1;2;3;49m"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := StyleToSGR(tt.style); got != tt.want {
+				t.Errorf("StyleToSGR() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,168 @@
+package testutil
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/gdamore/tcell/v2"
+)
+
+// SnapshotEnvUpdate is the environment variable that, when set, makes snapshot
+// assertions overwrite the golden files with the current terminal instead of comparing.
+const SnapshotEnvUpdate = "UPDATE_TERMINAL_SNAPSHOTS"
+
+const csi = "\x1b["
+
+// Snapshot is a point-in-time capture of the simulated terminal (character grid and dimensions).
+// Content is newline-separated lines; String() returns Content so it can be echoed or logged.
+type Snapshot struct {
+	Content string
+	Cols    int
+	Rows    int
+}
+
+// String returns the terminal content so that t.Log(snap) or echo displays the terminal.
+// In a narrower real terminal, long lines wrap naturally.
+func (s Snapshot) String() string {
+	return s.Content
+}
+
+// DelimitedString returns the snapshot with a header and footer for extraction from test output.
+func (s Snapshot) DelimitedString() string {
+	return "--- terminal snapshot " + fmt.Sprintf("%dx%d", s.Cols, s.Rows) + " ---\n" +
+		s.Content + "\n--- end snapshot ---"
+}
+
+// GoldenPath returns the default golden file path for a snapshot name at the given size:
+// testdata/snapshots/{cols}x{rows}/{name}.terminal, with "/" in name replaced by "_".
+func GoldenPath(name string, cols, rows int) string {
+	safe := strings.TrimSpace(strings.ReplaceAll(name, "/", "_"))
+	if safe == "" {
+		safe = "default"
+	}
+	return filepath.Join("testdata", "snapshots", fmt.Sprintf("%dx%d", cols, rows), safe+".terminal")
+}
+
+// AssertSnapshot compares the current terminal to the golden file at GoldenPath(name, cols, rows).
+// If name is empty, it is derived from t.Name().
+func (h *Harness) AssertSnapshot(t testing.TB, name string) {
+	t.Helper()
+	if name == "" {
+		name = t.Name()
+	}
+	snap := h.TakeSnapshot()
+	h.AssertGolden(t, GoldenPath(name, snap.Cols, snap.Rows))
+}
+
+// AssertGolden compares the current terminal to the golden file at path.
+// When UPDATE_TERMINAL_SNAPSHOTS is set, the golden file is overwritten with the current state and the assertion passes.
+func (h *Harness) AssertGolden(t testing.TB, path string) {
+	t.Helper()
+	snap := h.TakeSnapshot()
+
+	if os.Getenv(SnapshotEnvUpdate) != "" {
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("create snapshot dir: %v", err)
+		}
+		if err := os.WriteFile(path, []byte(snap.Content), 0644); err != nil {
+			t.Fatalf("write snapshot: %v", err)
+		}
+		return
+	}
+
+	expected, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			t.Logf("current terminal:\n%s", snap)
+			t.Fatalf("no golden snapshot at %s; run with %s=1 to create it", path, SnapshotEnvUpdate)
+		}
+		t.Fatalf("read golden snapshot: %v", err)
+	}
+	if snap.Content != string(expected) {
+		t.Errorf("snapshot mismatch for %s", path)
+		t.Logf("current terminal:\n%s", snap)
+		t.Logf("expected (golden):\n%s", expected)
+	}
+}
+
+// renderScreen converts the screen to newline-separated text with SGR style sequences,
+// so golden files can be viewed with cat in a color terminal.
+func renderScreen(screen tcell.Screen) (string, int, int) {
+	w, hi := screen.Size()
+	var b strings.Builder
+	var prevStyle tcell.Style
+	firstCell := true
+	for y := 0; y < hi; y++ {
+		for x := 0; x < w; x++ {
+			mainc, _, style, _ := screen.GetContent(x, y)
+			if firstCell || style != prevStyle {
+				b.WriteString(StyleToSGR(style))
+				prevStyle = style
+				firstCell = false
+			}
+			if mainc != 0 {
+				b.WriteRune(mainc)
+			} else {
+				b.WriteByte(' ')
+			}
+		}
+		if y < hi-1 {
+			b.WriteByte('\n')
+		}
+		firstCell = true
+		prevStyle = tcell.Style{}
+	}
+	return b.String(), w, hi
+}
+
+// StyleToSGR converts tcell Style to ANSI SGR escape sequence for cat-compatible terminal output.
+// Uses 24-bit true color (38;2;r;g;b) for both RGB and named palette colors—tcell's RGB() returns
+// the display color for both, while Hex()&0xff incorrectly gives 0 for colors like Yellow/Green.
+func StyleToSGR(st tcell.Style) string {
+	fg, bg, attr := st.Decompose()
+	var codes []string
+	// Foreground: use RGB() for all valid colors (named palette colors have RGB values too)
+	codes = append(codes, colorSGR(fg, "38", "39")...)
+	// Background
+	codes = append(codes, colorSGR(bg, "48", "49")...)
+	// Attributes (AttrMask: Bold=1, Dim=2, Italic=4, Underline=8, Blink=16, Reverse=64, StrikeThrough=128)
+	if attr&tcell.AttrBold != 0 {
+		codes = append(codes, "1")
+	}
+	if attr&tcell.AttrDim != 0 {
+		codes = append(codes, "2")
+	}
+	if attr&tcell.AttrItalic != 0 {
+		codes = append(codes, "3")
+	}
+	if attr&tcell.AttrUnderline != 0 {
+		codes = append(codes, "4")
+	}
+	if attr&tcell.AttrBlink != 0 {
+		codes = append(codes, "5")
+	}
+	if attr&tcell.AttrReverse != 0 {
+		codes = append(codes, "7")
+	}
+	if attr&tcell.AttrStrikeThrough != 0 {
+		codes = append(codes, "9")
+	}
+	if len(codes) == 2 && codes[0] == "39" && codes[1] == "49" {
+		return csi + "0m"
+	}
+	return csi + strings.Join(codes, ";") + "m"
+}
+
+// colorSGR returns the SGR codes for a color: "<set>;2;r;g;b" for valid colors, or "<reset>".
+func colorSGR(c tcell.Color, set, reset string) []string {
+	if c.Valid() && c != tcell.ColorDefault {
+		if r, g, b := c.RGB(); r >= 0 && r <= 255 && g >= 0 && g <= 255 && b >= 0 && b <= 255 {
+			return []string{set, "2", strconv.Itoa(int(r)), strconv.Itoa(int(g)), strconv.Itoa(int(b))}
+		}
+	}
+	return []string{reset}
+}